@@ -1,8 +1,11 @@
 package main
 
 import (
+	"flag"
 	"log"
 	"runtime"
+	"strings"
+	"time"
 
 	v41 "github.com/4ydx/gltext/v4.1"
 	"github.com/go-gl/gl/v4.1-core/gl"
@@ -24,6 +27,8 @@ const (
 	windowWidth  = 1200
 	windowHeight = 800
 	windowName   = "KubeChaser"
+
+	replaySeekStep = 10 * time.Second
 )
 
 func init() {
@@ -72,7 +77,8 @@ func createGL() {
 	log.Println("OpenGL version: ", version)
 }
 
-func createMainCluster(ctrl *controller.Controller, font *v41.Font) *gkube.GCluster {
+// createMainScene creates the scene shared by every cluster, with the light and crosshair, and binds the camera
+func createMainScene(ctrl *controller.Controller, font *v41.Font) (*scene.Scene, []*shader.Program) {
 	// camera
 	cam := &camera.Camera{}
 	cam.Init(windowWidth, windowHeight, nil) // Initialize a perspective camera with aspect ratio windowWidth/windowHeight
@@ -122,19 +128,145 @@ func createMainCluster(ctrl *controller.Controller, font *v41.Font) *gkube.GClus
 	// framebox.Init(font, "")
 	// frame.Init(framebox, camera.CreateTransform3D(&mgl.Vec3{3, 5, 0}, &mgl.Vec3{1, 1, 1}, nil), mvpProgram.ID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
 
+	shaderPrograms := []*shader.Program{texturedCubeProgram, mvpProgram, crosshairProgram, guiProgram}
+	mainScene := &scene.Scene{}
+	mainScene.Init(shaderPrograms, []*scene.SceneObject{}, cam)
+	ctrl.AddClickHandler(mainScene.Click)
+	ctrl.AddHoverHandler(mainScene.Hover)
+	// mainScene.AddObject(sceneGround)
+	mainScene.AddObject(sceneCube)
+	mainScene.AddObject(crosshair)
+	// mainScene.AddObject(gui)
+	return mainScene, shaderPrograms
+}
+
+// createCluster draws what the source watches into a new GCluster in the main scene
+func createCluster(mainScene *scene.Scene, font *v41.Font, shaderPrograms []*shader.Program, source watcher.Source) *gkube.GCluster {
 	gc := &gkube.GCluster{}
-	gc.Create(ctrl, cam, font, []*shader.Program{texturedCubeProgram, mvpProgram, crosshairProgram, guiProgram})
-	// gc.GetMainScene().AddObject(sceneGround)
-	gc.GetMainScene().AddObject(sceneCube)
-	gc.GetMainScene().AddObject(crosshair)
-	// gc.GetMainScene().AddObject(gui)
-
-	watcher := watcher.Watcher{}
-	watcher.Init(gc)
+	gc.CreateInScene(mainScene, font, shaderPrograms)
+	source.Start(gc)
 	return gc
 }
 
+// createSources watches the configured kubeconfig contexts, or reads the manifests at manifestPath without a cluster
+func createSources(watchConfig *watcher.WatchConfig, manifestPath string) []*watcher.Watcher {
+	if len(manifestPath) > 0 {
+		objects, err := watcher.LoadManifests(manifestPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		source, err := watcher.CreateManifestSource(watchConfig, objects)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		source.ClusterName = watcher.ManifestClusterName(manifestPath)
+		return []*watcher.Watcher{source}
+	}
+	if len(watchConfig.Contexts) == 0 {
+		// the current context is drawn under its name, so that it can be switched from
+		if _, current, err := watcher.ListKubeContexts(); err == nil && len(current) > 0 {
+			watchConfig.Contexts = []string{current}
+		}
+	}
+	sources := []*watcher.Watcher{}
+	if len(watchConfig.Contexts) == 0 {
+		sources = append(sources, watcher.CreateKubeSource(watchConfig, ""))
+	}
+	for _, kubeContext := range watchConfig.Contexts {
+		sources = append(sources, watcher.CreateKubeSource(watchConfig, strings.TrimSpace(kubeContext)))
+	}
+	return sources
+}
+
+// createRecorder records the events of the only watched context, recordings of several contexts could not be told apart
+func createRecorder(path string, sources []*watcher.Watcher) *watcher.Recorder {
+	if len(sources) != 1 {
+		log.Fatalln("-record takes a single context")
+	}
+	recorder, err := watcher.CreateRecorder(path)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	sources[0].Recorder = recorder
+	log.Printf("Recording to %s\n", path)
+	return recorder
+}
+
+// bindReplayKeys controls the replay: Enter pauses, . steps, [ and ] halve and double the speed, and the left and right
+// arrows seek 10 seconds
+func bindReplayKeys(ctrl *controller.Controller, replay *watcher.ReplaySource) {
+	ctrl.AddKeyHandler(glfw.KeyEnter, replay.TogglePause)
+	ctrl.AddKeyHandler(glfw.KeyPeriod, replay.Step)
+	ctrl.AddKeyHandler(glfw.KeyLeftBracket, func() { replay.SetSpeed(replay.GetSpeed() / 2) })
+	ctrl.AddKeyHandler(glfw.KeyRightBracket, func() { replay.SetSpeed(replay.GetSpeed() * 2) })
+	ctrl.AddKeyHandler(glfw.KeyLeft, func() { replay.SeekBy(-replaySeekStep) })
+	ctrl.AddKeyHandler(glfw.KeyRight, func() { replay.SeekBy(replaySeekStep) })
+}
+
+// switchContext tears down the cluster at index and rebuilds it against the next kubeconfig context that is not drawn
+// yet, keeping the window and the other clusters
+func switchContext(watchConfig *watcher.WatchConfig, sources []*watcher.Watcher, clusters []*gkube.GCluster, index int, rebuild func(*watcher.Watcher) *gkube.GCluster) {
+	contexts, _, err := watcher.ListKubeContexts()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	drawn := []string{}
+	for _, source := range sources {
+		drawn = append(drawn, source.ClusterName)
+	}
+	log.Printf("Kubeconfig contexts: %s\n", strings.Join(contexts, ", "))
+	next := watcher.NextKubeContext(contexts, sources[index].ClusterName, drawn)
+	if len(next) == 0 {
+		log.Println("No other kubeconfig context to switch to")
+		return
+	}
+	source, err := watcher.NewKubeSource(watchConfig, next)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	log.Printf("Switching from context %s to %s\n", sources[index].ClusterName, next)
+	sources[index].Stop() // nothing is pushed into the cluster once the watcher has stopped
+	clusters[index].Destroy()
+	sources[index] = source
+	clusters[index] = rebuild(source)
+}
+
+// reloadScope re-reads the config and re-scopes the running watchers, keeping the current scope if the config is invalid.
+// Watchers created later, when switching contexts, start with the reloaded scope.
+func reloadScope(configPath string, currentConfig *watcher.WatchConfig, sources []*watcher.Watcher) {
+	watchConfig, err := watcher.LoadWatchConfig(configPath)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if err := watchConfig.Scope.Validate(); err != nil {
+		log.Println(err)
+		return
+	}
+	currentConfig.Scope = watchConfig.Scope
+	for _, source := range sources {
+		source.SetScope(watchConfig.Scope)
+	}
+	log.Printf("Reloaded the watch scope from %s\n", configPath)
+}
+
 func main() {
+	configPath := flag.String("config", watcher.DefaultConfigPath, "path to the kubechaser config")
+	contexts := flag.String("contexts", "", "comma-separated kubeconfig contexts drawn side by side, overrides the config")
+	manifests := flag.String("manifests", "", "directory or multi-document YAML of manifests drawn without a cluster, - for stdin")
+	recordPath := flag.String("record", "", "record every event the watcher receives to this .jsonl.gz file")
+	replayPath := flag.String("replay", "", "replay a recording instead of watching a cluster")
+	flag.Parse()
+	watchConfig, err := watcher.LoadWatchConfig(*configPath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(*contexts) > 0 {
+		watchConfig.Contexts = strings.Split(*contexts, ",")
+	}
+
 	ctrl := &controller.Controller{}
 	ctrl.Init()
 
@@ -157,9 +289,53 @@ func main() {
 	// text := fonts.CreateText("KubeChaser", font, &mgl.Vec3{0.5, 0.6, 0.3}, 0.6)
 
 	// create scene
-	cluster := createMainCluster(ctrl, font)
+	mainScene, shaderPrograms := createMainScene(ctrl, font)
+	rebuild := func(source *watcher.Watcher) *gkube.GCluster {
+		return createCluster(mainScene, font, shaderPrograms, source)
+	}
+	sources := []*watcher.Watcher{}
+	clusters := []*gkube.GCluster{}
+	if len(*replayPath) > 0 {
+		replay, err := watcher.CreateReplaySource(*replayPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		clusters = append(clusters, createCluster(mainScene, font, shaderPrograms, replay))
+		bindReplayKeys(ctrl, replay)
+		defer replay.Stop()
+	} else {
+		sources = createSources(watchConfig, *manifests)
+		if len(*recordPath) > 0 {
+			recorder := createRecorder(*recordPath, sources)
+			defer recorder.Close()
+		}
+		for _, source := range sources {
+			clusters = append(clusters, rebuild(source))
+		}
+		defer func() { // before the recorder is closed, the watchers drawn at exit as switching contexts replaces them
+			for _, source := range sources {
+				source.Stop()
+			}
+		}()
+	}
+	gkube.ArrangeClusters(clusters)
+
+	ctrl.AddKeyHandler(glfw.KeyL, func() { // switch between the namespace and node layouts
+		for _, cluster := range clusters {
+			cluster.ToggleLayout()
+		}
+	})
+	ctrl.AddKeyHandler(glfw.KeyE, func() { // expand every pod into its containers, or collapse them
+		for _, cluster := range clusters {
+			cluster.ToggleContainers()
+		}
+	})
+	ctrl.AddKeyHandler(glfw.KeyC, func() { reloadScope(*configPath, watchConfig, sources) }) // apply the scope of an edited config
+	if len(*manifests) == 0 && len(*recordPath) == 0 && len(sources) > 0 {
+		ctrl.AddKeyHandler(glfw.KeyK, func() { switchContext(watchConfig, sources, clusters, 0, rebuild) }) // draw the next kubeconfig context instead of the first cluster
+	}
 	// mainWindow.AddCluster(cluster)
-	mainWindow.AddScenes([]*scene.Scene{cluster.GetMainScene()})
+	mainWindow.AddScenes([]*scene.Scene{mainScene})
 
 	debug := false
 
@@ -169,21 +345,33 @@ func main() {
 		gl.Enable(gl.DEPTH_TEST)
 		gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 
-		if i%20 == 0 {
-			cluster.GC()
-		}
+		for _, cluster := range clusters {
+			if i%20 == 0 {
+				cluster.GC()
+				cluster.UpdateKubeEventPulses()
+			}
 
-		if i%10 == 0 {
-			event, hasNewEvent := cluster.PopGObjectEvent()
-			if hasNewEvent {
-				switch event.GetType() {
-				case gkube.GCREATE:
-					cluster.AddGObject(event)
-				case gkube.GDELETE:
-					cluster.RemoveGObject(event)
+			if i%10 == 0 {
+				event, hasNewEvent := cluster.PopGObjectEvent()
+				if hasNewEvent {
+					switch event.GetType() {
+					case gkube.GCREATE:
+						cluster.AddGObject(event)
+					case gkube.GMODIFIED:
+						cluster.UpdateGObject(event)
+					case gkube.GDELETE:
+						cluster.RemoveGObject(event)
+					}
 				}
+				cluster.UpdateGObjectFrames(debug)
 			}
-			cluster.UpdateGObjectFrames(debug)
+			cluster.UpdateGLinks()
+		}
+		if i%10 == 0 {
+			gkube.ArrangeClusters(clusters)
+		}
+		if i%5 == 0 {
+			ctrl.Hover()
 		}
 		mainWindow.Draw(float32(timer.GetElapsedTime()))
 		glfwWindow.SwapBuffers()
@@ -17,6 +17,8 @@ type Controller struct {
 	pressed       map[glfw.Key]bool
 	Camera        *camera.Camera
 	clickHandlers []func(r *camera.Ray)
+	hoverHandlers []func(r *camera.Ray)
+	keyHandlers   map[glfw.Key][]func()
 
 	width  int
 	height int
@@ -30,6 +32,8 @@ type Controller struct {
 func (c *Controller) Init() {
 	c.pressed = make(map[glfw.Key]bool)
 	c.clickHandlers = make([]func(r *camera.Ray), 0)
+	c.hoverHandlers = make([]func(r *camera.Ray), 0)
+	c.keyHandlers = make(map[glfw.Key][]func())
 	c.width = 1200
 	c.height = 800
 	c.lastX = float64(c.width / 2.0)
@@ -49,6 +53,11 @@ func (c *Controller) KeyCallback(w *glfw.Window, key glfw.Key, scancode int, act
 			c.Camera.DisableCursor = false
 		}
 	}
+	if action == glfw.Press {
+		for _, handler := range c.keyHandlers[key] {
+			handler()
+		}
+	}
 }
 
 func (c *Controller) CursorPosCallback(w *glfw.Window, xpos, ypos float64) {
@@ -99,6 +108,27 @@ func (c *Controller) AddClickHandler(f func(r *camera.Ray)) {
 	c.clickHandlers = append(c.clickHandlers, f)
 }
 
+// AddKeyHandler calls f whenever key is pressed
+func (c *Controller) AddKeyHandler(key glfw.Key, f func()) {
+	c.keyHandlers[key] = append(c.keyHandlers[key], f)
+}
+
+func (c *Controller) AddHoverHandler(f func(r *camera.Ray)) {
+	c.hoverHandlers = append(c.hoverHandlers, f)
+}
+
+// Hover casts a ray through the crosshair to the hover handlers; called from the main loop since the view moves with the camera
+func (c *Controller) Hover() {
+	if c.Camera == nil {
+		return
+	}
+	r := &camera.Ray{}
+	r.Init(c.Camera.EyeAnimator.X_init, &c.Camera.Front) // first person view
+	for _, handler := range c.hoverHandlers {
+		handler(r)
+	}
+}
+
 func (c *Controller) ScrollCallback(w *glfw.Window, xOffset float64, yOffset float64) {
 	c.Camera.FOV -= yOffset
 	if c.Camera.FOV < 1.0 {
@@ -83,6 +83,11 @@ func (entity *Cube) Init(font *v41.Font, text string) {
 	// log.Printf("created cube at VAO: %d\n", entity.VAO)
 }
 
+// SetText replaces the label drawn above the cube
+func (entity *Cube) SetText(text string) {
+	entity.text.SetString("%s", text)
+}
+
 func (entity *Cube) Draw() {
 	gl.BindVertexArray(entity.VAO)
 	gl.DrawArrays(gl.TRIANGLES, 0, (entity.NumTriangles-2)*(3+3)) // (p-2) * (#vertices + #normal)
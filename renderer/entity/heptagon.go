@@ -19,9 +19,11 @@ type Heptagon struct {
 	BoundingBox  *AABB
 	text         *v41.Text
 	textPosition mgl.Vec3
+	font         *v41.Font
 }
 
 func (entity *Heptagon) Init(font *v41.Font, text string) {
+	entity.font = font
 	entity.textPosition = mgl.Vec3{0, 1.5, 0}
 	if len(text) > 0 {
 		entity.text = fonts.CreateText(text, font, &mgl.Vec3{0.5, 0.6, 0.3}, 0.6)
@@ -130,6 +132,15 @@ func (entity *Heptagon) Init(font *v41.Font, text string) {
 	// log.Printf("created heptagon at VAO: %d\n", entity.VAO)
 }
 
+// SetText replaces the label drawn above the heptagon
+func (entity *Heptagon) SetText(text string) {
+	if entity.text == nil {
+		entity.text = fonts.CreateText(text, entity.font, &mgl.Vec3{0.5, 0.6, 0.3}, 0.6)
+		return
+	}
+	entity.text.SetString("%s", text)
+}
+
 func (entity *Heptagon) BindTextures() {}
 
 func (entity *Heptagon) Draw() {
@@ -98,6 +98,11 @@ func (entity *HeptagonalPrism) Init(font *v41.Font, text string) {
 	// log.Printf("created heptagonalprism at VAO: %d\n", entity.VAO)
 }
 
+// SetText replaces the label drawn above the prism
+func (entity *HeptagonalPrism) SetText(text string) {
+	entity.text.SetString("%s", text)
+}
+
 func (entity *HeptagonalPrism) BindTextures() {}
 
 func (entity *HeptagonalPrism) Draw() {
@@ -17,6 +17,7 @@ type WavefrontOBJ struct {
 	NumTriangles int32
 	vertices     []mgl.Vec3
 	triangles    []*NTri
+	font         *v41.Font
 	text         *v41.Text
 	textPosition mgl.Vec3
 	BoundingBox  *AABB
@@ -26,6 +27,7 @@ type WavefrontOBJ struct {
 func (entity *WavefrontOBJ) BindTextures() {}
 
 func (entity *WavefrontOBJ) Init(font *v41.Font, text string) {
+	entity.font = font
 	entity.textPosition = mgl.Vec3{0, 0.8, 0}
 	entity.text = fonts.CreateText(text, font, &mgl.Vec3{0.35546875, 0.56640625, 0.23046875}, 0.5)
 
@@ -38,7 +40,7 @@ func (entity *WavefrontOBJ) Init(font *v41.Font, text string) {
 	}
 	triangles, err := LoadNTrianglesFromOBJ(fmt.Sprintf("./assets/models/%s", entity.FileName))
 	if err != nil {
-		log.Printf("Could not load triangles from OBJ in %f\n", entity.FileName)
+		log.Printf("Could not load triangles from OBJ in %s\n", entity.FileName)
 		log.Printf("%+v\n", err)
 	}
 	entity.triangles = triangles
@@ -58,6 +60,15 @@ func (entity *WavefrontOBJ) Init(font *v41.Font, text string) {
 	entity.BoundingBox = GenerateAABB(trianglePoints...)
 }
 
+// SetText replaces the label drawn above the model
+func (entity *WavefrontOBJ) SetText(text string) {
+	if entity.text == nil {
+		entity.text = fonts.CreateText(text, entity.font, &mgl.Vec3{0.35546875, 0.56640625, 0.23046875}, 0.5)
+		return
+	}
+	entity.text.SetString("%s", text)
+}
+
 func (entity *WavefrontOBJ) Draw() {
 	gl.BindVertexArray(entity.VAO)
 	gl.DrawArrays(gl.TRIANGLES, 0, (entity.NumTriangles-2)*(3+3)) // (p-2) * (#vertices + #normal)
@@ -17,7 +17,6 @@ import (
 	"github.com/kabicin/kubechaser/renderer/logg"
 	"github.com/kabicin/kubechaser/renderer/scene"
 	"github.com/kabicin/kubechaser/renderer/shader"
-	"github.com/kabicin/kubechaser/renderer/utils"
 )
 
 type State int
@@ -40,27 +39,33 @@ const (
 type GResource int
 
 const (
-	GWIRE                  GResource = iota
-	GDEPLOYMENT            GResource = iota
-	GSTATEFULSET           GResource = iota
-	GREPLICASET            GResource = iota
-	GPOD                   GResource = iota
-	GSERVICE               GResource = iota
-	GINGRESS               GResource = iota
-	GSERVICEACCOUNT        GResource = iota
-	GROLE                  GResource = iota
-	GROLEBINDING           GResource = iota
-	GCLUSTERROLE           GResource = iota
-	GCLUSTERROLEBINDING    GResource = iota
-	GJOB                   GResource = iota
-	GCRONJOB               GResource = iota
-	GDAEMONSET             GResource = iota
-	GSECRET                GResource = iota
-	GCONFIGMAP             GResource = iota
-	GPERSISTENTVOLUME      GResource = iota
-	GPERSISTENTVOLUMECLAIM GResource = iota
-	GCLUSTEROBJECTFRAME    GResource = iota
-	GNAMESPACEOBJECTFRAME  GResource = iota
+	GWIRE                    GResource = iota
+	GDEPLOYMENT              GResource = iota
+	GSTATEFULSET             GResource = iota
+	GREPLICASET              GResource = iota
+	GPOD                     GResource = iota
+	GSERVICE                 GResource = iota
+	GINGRESS                 GResource = iota
+	GSERVICEACCOUNT          GResource = iota
+	GROLE                    GResource = iota
+	GROLEBINDING             GResource = iota
+	GCLUSTERROLE             GResource = iota
+	GCLUSTERROLEBINDING      GResource = iota
+	GJOB                     GResource = iota
+	GCRONJOB                 GResource = iota
+	GDAEMONSET               GResource = iota
+	GSECRET                  GResource = iota
+	GCONFIGMAP               GResource = iota
+	GPERSISTENTVOLUME        GResource = iota
+	GPERSISTENTVOLUMECLAIM   GResource = iota
+	GSTORAGECLASS            GResource = iota
+	GNODE                    GResource = iota
+	GHORIZONTALPODAUTOSCALER GResource = iota
+	GCUSTOMRESOURCE          GResource = iota
+	GKUBEEVENT               GResource = iota
+	GRESOURCEUSAGE           GResource = iota
+	GCLUSTEROBJECTFRAME      GResource = iota
+	GNAMESPACEOBJECTFRAME    GResource = iota
 )
 
 type GDirection int
@@ -192,13 +197,20 @@ type GCluster struct {
 	currentObject    GObject
 	currentName      string
 	currentNamespace string
+	highlighted      []GObject
+	details          *GDetails
+	kubeEvents       *GKubeEventIndex
 
 	lastOffsets map[string]mgl.Vec3
 
-	slots          map[string][][]SlotResource
-	gcSlots        []GObject
-	gcSlotsMutex   *sync.Mutex
-	namespaceSlots []string
+	slots            map[string][][]SlotResource
+	gcSlots          []GObject
+	gcFrames         []GObjectFrame // frames of deleted namespaces, evicted once their slots are
+	gcSlotsMutex     *sync.Mutex
+	namespaceSlots   []string
+	layout           GLayout
+	expandContainers bool     // draws the containers of every pod, not only of the selected pod
+	origin           mgl.Vec3 // where the slots of the cluster start, clusters sharing a scene are placed side by side
 }
 
 var GOBJECTFRAME_FILTER_SAME_NAMESPACE = func(gobjectFrame GObjectFrame) func(obj GObject) bool {
@@ -219,25 +231,31 @@ var GOBJECTFRAME_FILTER_ALL = func(gobjectFrame GObjectFrame) func(obj GObject)
 }
 
 var GResourceNames map[GResource]string = map[GResource]string{
-	GWIRE:                  "GWIRE",
-	GDEPLOYMENT:            "GDEPLOYMENT",
-	GSTATEFULSET:           "GSTATEFULSET",
-	GREPLICASET:            "GREPLICASET",
-	GPOD:                   "GPOD",
-	GSERVICE:               "GSERVICE",
-	GINGRESS:               "GINGRESS",
-	GSERVICEACCOUNT:        "GSERVICEACCOUNT",
-	GROLE:                  "GROLE",
-	GROLEBINDING:           "GROLEBINDING",
-	GCLUSTERROLE:           "GCLUSTERROLE",
-	GCLUSTERROLEBINDING:    "GCLUSTERROLEBINDING",
-	GJOB:                   "GJOB",
-	GCRONJOB:               "GCRONJOB",
-	GDAEMONSET:             "GDAEMONSET",
-	GSECRET:                "GSECRET",
-	GCONFIGMAP:             "GCONFIGMAP",
-	GPERSISTENTVOLUME:      "GPERSISTENTVOLUME",
-	GPERSISTENTVOLUMECLAIM: "GPERSISTENTVOLUMECLAIM",
+	GWIRE:                    "GWIRE",
+	GDEPLOYMENT:              "GDEPLOYMENT",
+	GSTATEFULSET:             "GSTATEFULSET",
+	GREPLICASET:              "GREPLICASET",
+	GPOD:                     "GPOD",
+	GSERVICE:                 "GSERVICE",
+	GINGRESS:                 "GINGRESS",
+	GSERVICEACCOUNT:          "GSERVICEACCOUNT",
+	GROLE:                    "GROLE",
+	GROLEBINDING:             "GROLEBINDING",
+	GCLUSTERROLE:             "GCLUSTERROLE",
+	GCLUSTERROLEBINDING:      "GCLUSTERROLEBINDING",
+	GJOB:                     "GJOB",
+	GCRONJOB:                 "GCRONJOB",
+	GDAEMONSET:               "GDAEMONSET",
+	GSECRET:                  "GSECRET",
+	GCONFIGMAP:               "GCONFIGMAP",
+	GPERSISTENTVOLUME:        "GPERSISTENTVOLUME",
+	GPERSISTENTVOLUMECLAIM:   "GPERSISTENTVOLUMECLAIM",
+	GSTORAGECLASS:            "GSTORAGECLASS",
+	GNODE:                    "GNODE",
+	GHORIZONTALPODAUTOSCALER: "GHORIZONTALPODAUTOSCALER",
+	GCUSTOMRESOURCE:          "GCUSTOMRESOURCE",
+	GKUBEEVENT:               "GKUBEEVENT",
+	GRESOURCEUSAGE:           "GRESOURCEUSAGE",
 	// object frames
 	GCLUSTEROBJECTFRAME:   "GCLUSTEROBJECTFRAME",
 	GNAMESPACEOBJECTFRAME: "GNAMESPACEOBJECTFRAME",
@@ -255,6 +273,32 @@ var OBJECT_FRAMES = []GResource{
 	GNAMESPACEOBJECTFRAME,
 }
 
+// resources that reserve a slot when created, and are evicted from it by the garbage collector once deleted
+var SLOTTED_RESOURCES = []GResource{
+	GDEPLOYMENT,
+	GSTATEFULSET,
+	GREPLICASET,
+	GPOD,
+	GSERVICE,
+	GINGRESS,
+	GPERSISTENTVOLUMECLAIM,
+	GDAEMONSET,
+	GJOB,
+	GCRONJOB,
+	GCONFIGMAP,
+	GSECRET,
+	GSERVICEACCOUNT,
+	GPERSISTENTVOLUME,
+	GSTORAGECLASS,
+	GROLE,
+	GROLEBINDING,
+	GCLUSTERROLE,
+	GCLUSTERROLEBINDING,
+	GNODE,
+	GHORIZONTALPODAUTOSCALER,
+	GCUSTOMRESOURCE,
+}
+
 func isGResourceObjectFrame(gob GObject) bool {
 	return slices.Contains(OBJECT_FRAMES, gob.GetResource())
 }
@@ -263,7 +307,10 @@ func (gc *GCluster) GetMainScene() *scene.Scene {
 	return gc.mainScene
 }
 
+// SetSelected is called from the click handlers of GOBJECTs, outside of the lock on gobjects
 func (gc *GCluster) SetSelected(gobj GObject) {
+	gc.SetHighlighted(nil)
+	gc.showDetails(gobj)
 	gc.currentObject = gobj
 	name, namespace := gobj.GetIdentifier()
 	gc.currentName = name
@@ -272,6 +319,52 @@ func (gc *GCluster) SetSelected(gobj GObject) {
 	log.Printf("Set current object to name: %s in namespace: %s vec3(%f,%f,%f)\n", name, namespace, offset.X(), offset.Y(), offset.Z())
 }
 
+// SetHighlighted draws the GOBJECTs as if they were clicked, clearing the previous highlight
+func (gc *GCluster) SetHighlighted(gobs []GObject) {
+	for _, gob := range gc.highlighted {
+		gob.GetObject().OnClick = false
+		gob.GetObject().AccelerateForward = false
+	}
+	for _, gob := range gobs {
+		gob.GetObject().OnClick = true
+		gob.GetObject().AccelerateForward = true
+	}
+	gc.highlighted = gobs
+}
+
+// showDetails lists the details and recent events of the GOBJECT above it, replacing those of the previous selection
+func (gc *GCluster) showDetails(gobj GObject) {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	if lines := gc.detailLines(gobj); len(lines) > 0 && gc.details.target != gobj {
+		gc.details.Show(gobj, lines)
+	} else {
+		gc.details.Hide() // clicking the selected object again deselects it
+	}
+}
+
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) detailLines(gobj GObject) []string {
+	lines := []string{}
+	if detailed, ok := gobj.(GDetailedObject); ok {
+		lines = append(lines, detailed.GetDetails()...)
+	}
+	return append(lines, gc.kubeEventDetails(gobj)...)
+}
+
+// getConsumingPods finds the pods consuming the ConfigMap or Secret
+func (gc *GCluster) getConsumingPods(resource GResource, name, namespace string) []GObject {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	consumers := []GObject{}
+	for _, gob := range gc.gobjects {
+		if gp, ok := gob.(*GPod); ok && gp.namespace == namespace && gp.consumes(resource, name) {
+			consumers = append(consumers, gp)
+		}
+	}
+	return consumers
+}
+
 func (gc *GCluster) LockEventQueue() {
 	gc.gobjectEventQueueMutex.Lock()
 }
@@ -304,33 +397,14 @@ func (gc *GCluster) getSlotContainingGObject(gob GObject) SlotResource {
 func (gc *GCluster) GC() {
 	gc.gcSlotsMutex.Lock()
 	defer gc.gcSlotsMutex.Unlock()
-	n := len(gc.gcSlots)
-	if n == 0 {
-		return
-	}
-
-	deletedSlots := []int{} // array to hold indices of slots that were evicted
-	for i, gob := range gc.gcSlots {
-		if gob.GetObject().IsDeleteReady {
-			gc.EvictSlot(gc.getSlotContainingGObject(gob)) // evict
-			deletedSlots = append(deletedSlots, i)         // signal to remove from garbage collector
-		}
-	}
-	fmt.Println("delted slot")
-	fmt.Println(deletedSlots)
-	// actualize the remove from garbage collector
-	deletedSlots = utils.MergeSort(deletedSlots)
-	fmt.Println(deletedSlots)
-	for i := len(deletedSlots) - 1; i >= 0; i-- {
-		delIndex := deletedSlots[i]
-		if delIndex == 0 {
-			gc.gcSlots = gc.gcSlots[1:]
-		} else if delIndex == len(deletedSlots)-1 {
-			gc.gcSlots = gc.gcSlots[:len(deletedSlots)-1]
-		} else {
-			gc.gcSlots = append(gc.gcSlots[delIndex:], gc.gcSlots[:delIndex+1]...)
+	gc.gcSlots = slices.DeleteFunc(gc.gcSlots, func(gob GObject) bool {
+		if !gob.GetObject().IsDeleteReady {
+			return false
 		}
-	}
+		gc.EvictSlot(gc.getSlotContainingGObject(gob))
+		return true
+	})
+	gc.gcFrames = slices.DeleteFunc(gc.gcFrames, gc.evictNamespaceFrame)
 }
 
 func (gc *GCluster) DeleteGObject(gob GObject) {
@@ -357,6 +431,10 @@ func (gc *GCluster) PopGObjectEvent() (GObjectEvent, bool) {
 }
 
 func (gc *GCluster) PushGObjectEvent(eventType GEventStatus, resource GResource, name, namespace string, direction GDirection, settings GSettings, overrideLastOffset *mgl.Vec3, status GStatus, slot int, kubeState map[string]interface{}) {
+	if resource == GKUBEEVENT { // events are not drawn, so they skip the queue of GOBJECTs
+		gc.pushKubeEvent(eventType, name, namespace, status.(*GKubeEventStatus))
+		return
+	}
 	gc.LockEventQueue()
 	defer gc.UnlockEventQueue()
 	gc.gobjectEventQueue = append(gc.gobjectEventQueue, GObjectEvent{
@@ -374,8 +452,16 @@ func (gc *GCluster) PushGObjectEvent(eventType GEventStatus, resource GResource,
 }
 
 func (gc *GCluster) Create(ctrl *controller.Controller, cam *camera.Camera, font *v41.Font, shaderPrograms []*shader.Program) {
-	gc.mainScene = &scene.Scene{}
-	gc.mainScene.Init(shaderPrograms, []*scene.SceneObject{}, cam)
+	mainScene := &scene.Scene{}
+	mainScene.Init(shaderPrograms, []*scene.SceneObject{}, cam)
+	ctrl.AddClickHandler(mainScene.Click)
+	ctrl.AddHoverHandler(mainScene.Hover)
+	gc.CreateInScene(mainScene, font, shaderPrograms)
+}
+
+// CreateInScene creates the cluster in a scene it may share with other clusters, drawn from its origin
+func (gc *GCluster) CreateInScene(mainScene *scene.Scene, font *v41.Font, shaderPrograms []*shader.Program) {
+	gc.mainScene = mainScene
 
 	gc.gobjectEventQueue = make([]GObjectEvent, 0)
 	gc.gobjectEventQueueMutex = &sync.Mutex{}
@@ -387,6 +473,7 @@ func (gc *GCluster) Create(ctrl *controller.Controller, cam *camera.Camera, font
 
 	gc.slots = make(map[string][][]SlotResource)
 	gc.gcSlots = make([]GObject, 0)
+	gc.gcFrames = make([]GObjectFrame, 0)
 	gc.gcSlotsMutex = &sync.Mutex{}
 	gc.namespaceSlots = []string{}
 
@@ -398,7 +485,40 @@ func (gc *GCluster) Create(ctrl *controller.Controller, cam *camera.Camera, font
 		gc.shaders.Store(name, defaultShaderProgram)
 	}
 
-	ctrl.AddClickHandler(gc.mainScene.Click)
+	gc.details = &GDetails{}
+	gc.details.Create(gc, defaultShaderProgram.ID)
+	gc.kubeEvents = &GKubeEventIndex{}
+	gc.kubeEvents.Init()
+}
+
+// Destroy removes everything the cluster drew from its scene and drops its GOBJECTs and pending events, i.e. when the
+// cluster is rebuilt against another kubeconfig context. Its source must be stopped first.
+func (gc *GCluster) Destroy() {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	gc.details.Hide()
+	gc.highlighted = nil
+	gc.currentObject = nil
+	for _, gob := range gc.gobjects {
+		if object := gob.GetObject(); object != nil {
+			gc.mainScene.DeleteObject(object)
+		}
+		gob.Delete() // release anything the GOBJECT drew besides itself, i.e. links
+	}
+	gc.gobjects = make([]GObject, 0)
+	gc.gobjectFrames = make([]GObjectFrame, 0)
+
+	gc.gcSlotsMutex.Lock()
+	gc.slots = make(map[string][][]SlotResource)
+	gc.gcSlots = make([]GObject, 0)
+	gc.gcFrames = make([]GObjectFrame, 0)
+	gc.namespaceSlots = []string{}
+	gc.gcSlotsMutex.Unlock()
+	gc.kubeEvents.Init()
+
+	gc.LockEventQueue()
+	gc.gobjectEventQueue = make([]GObjectEvent, 0)
+	gc.UnlockEventQueue()
 }
 
 func (gc *GCluster) RemoveGObject(event GObjectEvent) {
@@ -410,7 +530,12 @@ func (gc *GCluster) RemoveGObject(event GObjectEvent) {
 	name := event.GetName()
 	namespace := event.GetNamespace()
 
-	if resource == GDEPLOYMENT || resource == GREPLICASET || resource == GPOD {
+	if resource == GNAMESPACEOBJECTFRAME {
+		gc.gcSlotsMutex.Lock()
+		gc.SetDeletingNamespace(name)
+		gc.gcSlotsMutex.Unlock()
+	}
+	if slices.Contains(SLOTTED_RESOURCES, resource) {
 		gc.gcSlotsMutex.Lock()
 		sr := SlotResource{name: name, namespace: namespace, resource: resource}
 		gc.SetDeletingSlot(sr) // signal deleting for this slot
@@ -423,8 +548,299 @@ func (gc *GCluster) RemoveGObject(event GObjectEvent) {
 }
 
 func (gc *GCluster) UpdateGObject(event GObjectEvent) {
-	// TODO: impl
 	log.Println("Updating GObject...")
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+
+	resource := event.GetResource()
+	name := event.GetName()
+	namespace := event.GetNamespace()
+	status := event.GetStatus()
+	kubeState := event.GetKubeState()
+
+	if resource == GRESOURCEUSAGE { // usage is attached to the pods and nodes it was polled for
+		gc.setResourceUsage(status.(*GResourceUsageStatus))
+		return
+	}
+
+	sr := SlotResource{name: name, namespace: namespace, resource: resource}
+	gob := gc.getGObjectFromSlot(sr)
+	if gob == nil {
+		log.Printf("GObject %s %s/%s could not be updated because it was not found\n", getGResourceName(resource), namespace, name)
+		return
+	}
+
+	if resource == GDEPLOYMENT {
+		gd := gob.(*GDeployment)
+		deploymentStatus := status.(*GDeploymentStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(deploymentStatus)
+		gc.UpdateSlotConnections(sr, customOwnerSignatureConnections(deploymentStatus.CustomOwner))
+		gc.refreshRollout(namespace, name)
+	}
+	if resource == GSTATEFULSET {
+		gd := gob.(*GStatefulSet)
+		statefulSetStatus := status.(*GStatefulSetStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetReplicas(statefulSetStatus.ReadyReplicas, statefulSetStatus.Replicas)
+		gc.UpdateSlotConnections(sr, statefulSetSignatureConnections(namespace, statefulSetStatus))
+	}
+	if resource == GREPLICASET {
+		grs := gob.(*GReplicaSet)
+		replicaSetStatus := status.(*GReplicaSetStatus)
+		grs.SetKubeState(kubeState)
+		grs.SetStatus(replicaSetStatus)
+		gc.UpdateSlotConnections(sr, replicaSetSignatureConnections(namespace, replicaSetStatus))
+		gc.refreshRollout(namespace, replicaSetStatus.OwnerReferenceName)
+	}
+	if resource == GPOD {
+		gp := gob.(*GPod)
+		podStatus := status.(*GPodStatus)
+		gp.SetKubeState(kubeState)
+		gp.SetStatus(podStatus)
+		gc.UpdateSlotConnections(sr, podSignatureConnections(namespace, podStatus))
+		gc.applyLayout() // the pod may have been scheduled onto a node
+	}
+	if resource == GNODE {
+		gd := gob.(*GNode)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GNodeStatus))
+	}
+	if resource == GSERVICE {
+		gs := gob.(*GService)
+		serviceStatus := status.(*GServiceStatus)
+		if kubeState != nil { // endpoint changes are pushed without the service's kube state
+			gs.SetKubeState(kubeState)
+		}
+		gs.SetStatus(serviceStatus)
+		gc.UpdateSlotConnections(sr, serviceSignatureConnections(namespace, serviceStatus))
+	}
+	if resource == GINGRESS {
+		gi := gob.(*GIngress)
+		ingressStatus := status.(*GIngressStatus)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(ingressStatus)
+		gc.UpdateSlotConnections(sr, ingressSignatureConnections(namespace, ingressStatus))
+	}
+	if resource == GCONFIGMAP {
+		gd := gob.(*GConfigMap)
+		configMapStatus := status.(*GConfigMapStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(configMapStatus)
+		gc.UpdateSlotConnections(sr, customOwnerSignatureConnections(configMapStatus.CustomOwner))
+	}
+	if resource == GSECRET {
+		gd := gob.(*GSecret)
+		secretStatus := status.(*GSecretStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(secretStatus)
+		gc.UpdateSlotConnections(sr, customOwnerSignatureConnections(secretStatus.CustomOwner))
+	}
+	if resource == GSERVICEACCOUNT {
+		gd := gob.(*GServiceAccount)
+		serviceAccountStatus := status.(*GServiceAccountStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(serviceAccountStatus)
+		gc.UpdateSlotConnections(sr, serviceAccountSignatureConnections(namespace, serviceAccountStatus))
+	}
+	if resource == GROLE {
+		gd := gob.(*GRole)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GRoleStatus))
+	}
+	if resource == GROLEBINDING {
+		gd := gob.(*GRoleBinding)
+		bindingStatus := status.(*GRoleBindingStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(bindingStatus)
+		gc.UpdateSlotConnections(sr, roleBindingSignatureConnections(namespace, bindingStatus))
+	}
+	if resource == GCLUSTERROLE {
+		gd := gob.(*GClusterRole)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GClusterRoleStatus))
+	}
+	if resource == GCLUSTERROLEBINDING {
+		gd := gob.(*GClusterRoleBinding)
+		bindingStatus := status.(*GClusterRoleBindingStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(bindingStatus)
+		gc.UpdateSlotConnections(sr, clusterRoleBindingSignatureConnections(bindingStatus))
+	}
+	if resource == GDAEMONSET {
+		gd := gob.(*GDaemonSet)
+		if kubeState != nil { // coverage changes are pushed without the daemonset's kube state
+			gd.SetKubeState(kubeState)
+		}
+		daemonSetStatus := status.(*GDaemonSetStatus)
+		gd.SetStatus(daemonSetStatus)
+		gc.UpdateSlotConnections(sr, customOwnerSignatureConnections(daemonSetStatus.CustomOwner))
+	}
+	if resource == GCUSTOMRESOURCE {
+		gd := gob.(*GCustomResource)
+		customResourceStatus := status.(*GCustomResourceStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(customResourceStatus)
+		gc.UpdateSlotConnections(sr, customResourceSignatureConnections(namespace, customResourceStatus))
+	}
+	if resource == GJOB {
+		gd := gob.(*GJob)
+		jobStatus := status.(*GJobStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(jobStatus)
+		gc.UpdateSlotConnections(sr, jobSignatureConnections(namespace, jobStatus))
+	}
+	if resource == GCRONJOB {
+		gd := gob.(*GCronJob)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GCronJobStatus))
+	}
+	if resource == GHORIZONTALPODAUTOSCALER {
+		gd := gob.(*GHorizontalPodAutoscaler)
+		hpaStatus := status.(*GHorizontalPodAutoscalerStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(hpaStatus)
+		gc.UpdateSlotConnections(sr, horizontalPodAutoscalerSignatureConnections(namespace, hpaStatus))
+	}
+	if resource == GPERSISTENTVOLUMECLAIM {
+		gd := gob.(*GPersistentVolumeClaim)
+		claimStatus := status.(*GPersistentVolumeClaimStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(claimStatus)
+		gc.UpdateSlotConnections(sr, customOwnerSignatureConnections(claimStatus.CustomOwner))
+	}
+	if resource == GPERSISTENTVOLUME {
+		gd := gob.(*GPersistentVolume)
+		volumeStatus := status.(*GPersistentVolumeStatus)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(volumeStatus)
+		gc.UpdateSlotConnections(sr, persistentVolumeSignatureConnections(volumeStatus))
+	}
+	if resource == GSTORAGECLASS {
+		gd := gob.(*GStorageClass)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GStorageClassStatus))
+	}
+}
+
+func replicaSetSignatureConnections(namespace string, replicaSetStatus *GReplicaSetStatus) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	if len(replicaSetStatus.OwnerReferenceName) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: GDEPLOYMENT, name: replicaSetStatus.OwnerReferenceName, namespace: namespace})
+	}
+	return sigConns
+}
+
+// a StatefulSet gathers the claims generated for its ordinals into its row, where they are ordered by their ordinal
+func statefulSetSignatureConnections(namespace string, statefulSetStatus *GStatefulSetStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(statefulSetStatus.CustomOwner)
+	for _, claim := range statefulSetStatus.Claims {
+		sigConns = append(sigConns, GSignatureConnection{resource: GPERSISTENTVOLUMECLAIM, name: claim, namespace: namespace})
+	}
+	return sigConns
+}
+
+func jobSignatureConnections(namespace string, jobStatus *GJobStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(jobStatus.CustomOwner)
+	if len(jobStatus.OwnerReferenceName) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: GCRONJOB, name: jobStatus.OwnerReferenceName, namespace: namespace})
+	}
+	return sigConns
+}
+
+func podSignatureConnections(namespace string, podStatus *GPodStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(podStatus.CustomOwner)
+	if len(podStatus.OwnerReferenceName) > 0 {
+		if ownerResource, found := OWNER_KIND_RESOURCES[podStatus.OwnerReferenceType]; found {
+			sigConns = append(sigConns, GSignatureConnection{resource: ownerResource, name: podStatus.OwnerReferenceName, namespace: namespace})
+		}
+	}
+	return sigConns
+}
+
+// a volume is placed in the row of the storage class it was provisioned from
+func persistentVolumeSignatureConnections(volumeStatus *GPersistentVolumeStatus) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	if len(volumeStatus.StorageClassName) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: GSTORAGECLASS, name: volumeStatus.StorageClassName, namespace: CLUSTER_SCOPED_NAMESPACE})
+	}
+	return sigConns
+}
+
+// customOwnerSignatureConnections places a resource in the row of the custom resource owning it, which is looked up in
+// its own namespace as it may be cluster-scoped
+func customOwnerSignatureConnections(customOwner GCustomOwner) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	if len(customOwner.Name) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: GCUSTOMRESOURCE, name: customOwner.Name, namespace: customOwner.Namespace})
+	}
+	return sigConns
+}
+
+// a custom resource is placed in the row of its owner, which is either another custom resource or a workload
+func customResourceSignatureConnections(namespace string, customResourceStatus *GCustomResourceStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(customResourceStatus.CustomOwner)
+	if ownerResource, found := OWNER_KIND_RESOURCES[customResourceStatus.OwnerReferenceType]; found && len(customResourceStatus.OwnerReferenceName) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: ownerResource, name: customResourceStatus.OwnerReferenceName, namespace: namespace})
+	}
+	return sigConns
+}
+
+// an autoscaler is placed in the row of its scale target, which is either a workload or a custom resource
+func horizontalPodAutoscalerSignatureConnections(namespace string, hpaStatus *GHorizontalPodAutoscalerStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(hpaStatus.CustomScaleTarget)
+	if targetResource, found := OWNER_KIND_RESOURCES[hpaStatus.ScaleTargetType]; found && len(hpaStatus.ScaleTargetName) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: targetResource, name: hpaStatus.ScaleTargetName, namespace: namespace})
+	}
+	return sigConns
+}
+
+// a role binding is placed in the row of the role it grants; bindings to a ClusterRole get a row of their own
+func roleBindingSignatureConnections(namespace string, bindingStatus *GRoleBindingStatus) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	if bindingStatus.RoleKind == "Role" {
+		sigConns = append(sigConns, GSignatureConnection{resource: GROLE, name: bindingStatus.RoleName, namespace: namespace})
+	}
+	return sigConns
+}
+
+// a cluster role binding is placed in the row of the cluster role it grants
+func clusterRoleBindingSignatureConnections(bindingStatus *GClusterRoleBindingStatus) []GSignatureConnection {
+	return []GSignatureConnection{{resource: GCLUSTERROLE, name: bindingStatus.RoleName, namespace: CLUSTER_SCOPED_NAMESPACE}}
+}
+
+// a service account is placed next to its image pull secrets
+func serviceAccountSignatureConnections(namespace string, serviceAccountStatus *GServiceAccountStatus) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	for _, secretName := range serviceAccountStatus.ImagePullSecrets {
+		sigConns = append(sigConns, GSignatureConnection{resource: GSECRET, name: secretName, namespace: namespace})
+	}
+	return sigConns
+}
+
+// an ingress is placed next to the first service it routes to
+func ingressSignatureConnections(namespace string, ingressStatus *GIngressStatus) []GSignatureConnection {
+	sigConns := []GSignatureConnection{}
+	for _, route := range ingressStatus.Routes {
+		if len(route.ServiceName) > 0 {
+			return append(sigConns, GSignatureConnection{resource: GSERVICE, name: route.ServiceName, namespace: namespace})
+		}
+	}
+	return sigConns
+}
+
+// a service is placed next to the workload it selects, by connecting it to the first pod backing it (preferring ready pods)
+func serviceSignatureConnections(namespace string, serviceStatus *GServiceStatus) []GSignatureConnection {
+	sigConns := customOwnerSignatureConnections(serviceStatus.CustomOwner)
+	for _, endpoint := range serviceStatus.Endpoints {
+		if endpoint.Ready {
+			return append(sigConns, GSignatureConnection{resource: GPOD, name: endpoint.PodName, namespace: namespace})
+		}
+	}
+	if len(serviceStatus.Endpoints) > 0 {
+		sigConns = append(sigConns, GSignatureConnection{resource: GPOD, name: serviceStatus.Endpoints[0].PodName, namespace: namespace})
+	}
+	return sigConns
 }
 
 // randomize this point p into space (i.e. far away from Origin)
@@ -457,9 +873,24 @@ func (gc *GCluster) UpdateGObjectFrames(debug bool) {
 	defer gc.gobjectMutex.Unlock()
 
 	for _, gobjectFrame := range gc.gobjectFrames {
+		if gobjectFrame.GetObject().IsDeleting {
+			continue // keep the bounds of a deleted namespace while it animates out
+		}
+		if gobjectFrame.GetResource() == GCLUSTEROBJECTFRAME {
+			boundaryPadding := mgl.Vec3{5, 0, 5}
+			hasPoints, center, bounds := gc.getBounds(boundaryPadding, GOBJECTFRAME_FILTER_ALL(gobjectFrame))
+			if hasPoints {
+				gobjectFrame.UpdateObjectFrame(center, bounds, entity.FrameStyleBorder, func() {
+					gc.GetMainScene().Update() // refresh shader after unsync between gd.Create and gd.SetFrame change
+				})
+			}
+		}
 		if gobjectFrame.GetResource() == GNAMESPACEOBJECTFRAME {
 			boundaryPadding := mgl.Vec3{10, 1, 10}
-			hasPoints, center, bounds := gc.getBounds(boundaryPadding, GOBJECTFRAME_FILTER_SAME_NAMESPACE(gobjectFrame))
+			sameNamespace := GOBJECTFRAME_FILTER_SAME_NAMESPACE(gobjectFrame)
+			hasPoints, center, bounds := gc.getBounds(boundaryPadding, func(obj GObject) bool {
+				return sameNamespace(obj) && gc.inNamespaceLayout(obj)
+			})
 			if hasPoints {
 				if debug {
 					fmt.Printf("gobjectframe has points: \n")
@@ -477,6 +908,19 @@ func (gc *GCluster) UpdateGObjectFrames(debug bool) {
 	}
 }
 
+// UpdateGLinks re-routes the wires of every GLinkedObject onto the current positions of the GOBJECTs they connect
+func (gc *GCluster) UpdateGLinks() {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+
+	for _, gob := range gc.gobjects {
+		if linked, ok := gob.(GLinkedObject); ok {
+			linked.UpdateLinks()
+		}
+	}
+	gc.details.Update()
+}
+
 func (gc *GCluster) AddGObject(event GObjectEvent) {
 	gc.gobjectMutex.Lock()
 	defer gc.gobjectMutex.Unlock()
@@ -518,40 +962,41 @@ func (gc *GCluster) AddGObject(event GObjectEvent) {
 	}
 	if resource == GDEPLOYMENT {
 		gd := &GDeployment{}
+		deploymentStatus := status.(*GDeploymentStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(deploymentStatus)
 		gc.gobjects = append(gc.gobjects, gd)
-		gc.CreateAndReserveSlot(name, namespace, gd, resource, []GSignatureConnection{})
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, customOwnerSignatureConnections(deploymentStatus.CustomOwner))
+		gc.refreshRollout(namespace, name)
 	}
 	if resource == GSTATEFULSET {
 		gd := &GStatefulSet{}
+		statefulSetStatus := status.(*GStatefulSetStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetReplicas(statefulSetStatus.ReadyReplicas, statefulSetStatus.Replicas)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, statefulSetSignatureConnections(namespace, statefulSetStatus))
 	}
 	if resource == GREPLICASET {
 		grs := &GReplicaSet{}
 		grs.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
-		gc.gobjects = append(gc.gobjects, grs)
 		replicaSetStatus := status.(*GReplicaSetStatus)
-		sigConns := []GSignatureConnection{}
-		if len(replicaSetStatus.OwnerReferenceName) > 0 {
-			sigConns = append(sigConns, GSignatureConnection{resource: GDEPLOYMENT, name: replicaSetStatus.OwnerReferenceName, namespace: namespace})
-		}
-		gc.CreateAndReserveSlot(name, namespace, grs, resource, sigConns)
+		grs.SetKubeState(kubeState)
+		grs.SetStatus(replicaSetStatus)
+		gc.gobjects = append(gc.gobjects, grs)
+		gc.CreateAndReserveOrdinalSlot(name, namespace, grs, resource, replicaSetSignatureConnections(namespace, replicaSetStatus), revisionOrdinal(replicaSetStatus.Revision))
+		gc.refreshRollout(namespace, replicaSetStatus.OwnerReferenceName)
 	}
 	if resource == GPOD {
 		gp := &GPod{}
 		podStatus := status.(*GPodStatus)
-		if podStatus.Up {
-			gp.state = Running
-		} else {
-			gp.state = Loading
-		}
 
 		fmt.Println("Pod is creating with kube state:")
 		fmt.Println(KubeStateToString(kubeState))
 
 		gp.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
-		gp.object.Spinning = true
 		// orderedMap := utils.ParseKubeState(event.GetKubeState())
 		// orderedMap2 := utils.ParseKubeState(event.GetKubeState())
 		// orderedMap3 := utils.DuplicateOrderedMap(orderedMap2)
@@ -561,84 +1006,166 @@ func (gc *GCluster) AddGObject(event GObjectEvent) {
 		// 	fmt.Println("ORDERED MAP DOES NOT EQUAL!")
 		// }
 		gp.SetKubeState(event.GetKubeState())
+		gp.SetStatus(podStatus)
 		gc.gobjects = append(gc.gobjects, gp)
-
-		sigConns := []GSignatureConnection{}
-		if len(podStatus.OwnerReferenceName) > 0 {
-			// fmt.Printf("Adding owner ref! %s\n", podStatus.OwnerReferenceName)
-			sigConns = append(sigConns, GSignatureConnection{resource: GREPLICASET, name: podStatus.OwnerReferenceName, namespace: namespace})
-		}
-		gc.CreateAndReserveSlot(name, namespace, gp, resource, sigConns)
+		gc.CreateAndReserveOrdinalSlot(name, namespace, gp, resource, podSignatureConnections(namespace, podStatus), gc.podOrdinal(namespace, podStatus))
 	}
 	if resource == GSERVICE {
 		gp := &GService{}
+		serviceStatus := status.(*GServiceStatus)
 		gp.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gp.SetKubeState(kubeState)
+		gp.SetStatus(serviceStatus)
 		gc.gobjects = append(gc.gobjects, gp)
+		gc.CreateAndReserveSlot(name, namespace, gp, resource, serviceSignatureConnections(namespace, serviceStatus))
 	}
 	if resource == GINGRESS {
 		gi := &GIngress{}
+		ingressStatus := status.(*GIngressStatus)
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(ingressStatus)
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, namespace, gi, resource, ingressSignatureConnections(namespace, ingressStatus))
 	}
 	if resource == GSERVICEACCOUNT {
 		gi := &GServiceAccount{}
+		serviceAccountStatus := status.(*GServiceAccountStatus)
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(serviceAccountStatus)
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, namespace, gi, resource, serviceAccountSignatureConnections(namespace, serviceAccountStatus))
+	}
+	if resource == GNODE {
+		gd := &GNode{}
+		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GNodeStatus))
+		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, CLUSTER_SCOPED_NAMESPACE, gd, resource, []GSignatureConnection{})
+	}
+	if resource == GCUSTOMRESOURCE {
+		gd := &GCustomResource{}
+		customResourceStatus := status.(*GCustomResourceStatus)
+		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(customResourceStatus)
+		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, customResourceSignatureConnections(namespace, customResourceStatus))
 	}
 	if resource == GROLE {
 		gi := &GRole{}
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(status.(*GRoleStatus))
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, namespace, gi, resource, []GSignatureConnection{})
 	}
 	if resource == GROLEBINDING {
 		gi := &GRoleBinding{}
+		bindingStatus := status.(*GRoleBindingStatus)
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(bindingStatus)
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, namespace, gi, resource, roleBindingSignatureConnections(namespace, bindingStatus))
 	}
 	if resource == GCLUSTERROLE {
 		gi := &GClusterRole{}
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(status.(*GClusterRoleStatus))
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, CLUSTER_SCOPED_NAMESPACE, gi, resource, []GSignatureConnection{})
 	}
 	if resource == GCLUSTERROLEBINDING {
 		gi := &GClusterRoleBinding{}
+		bindingStatus := status.(*GClusterRoleBindingStatus)
 		gi.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gi.SetKubeState(kubeState)
+		gi.SetStatus(bindingStatus)
 		gc.gobjects = append(gc.gobjects, gi)
+		gc.CreateAndReserveSlot(name, CLUSTER_SCOPED_NAMESPACE, gi, resource, clusterRoleBindingSignatureConnections(bindingStatus))
 	}
 	if resource == GJOB {
 		gd := &GJob{}
+		jobStatus := status.(*GJobStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(jobStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, jobSignatureConnections(namespace, jobStatus))
 	}
 	if resource == GCRONJOB {
 		gd := &GCronJob{}
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GCronJobStatus))
+		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, []GSignatureConnection{})
+	}
+	if resource == GHORIZONTALPODAUTOSCALER {
+		gd := &GHorizontalPodAutoscaler{}
+		hpaStatus := status.(*GHorizontalPodAutoscalerStatus)
+		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(hpaStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, horizontalPodAutoscalerSignatureConnections(namespace, hpaStatus))
 	}
 	if resource == GDAEMONSET {
 		gd := &GDaemonSet{}
+		daemonSetStatus := status.(*GDaemonSetStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(daemonSetStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, customOwnerSignatureConnections(daemonSetStatus.CustomOwner))
 	}
 	if resource == GSECRET {
 		gd := &GSecret{}
+		secretStatus := status.(*GSecretStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(secretStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, customOwnerSignatureConnections(secretStatus.CustomOwner))
 	}
 	if resource == GCONFIGMAP {
 		gd := &GConfigMap{}
+		configMapStatus := status.(*GConfigMapStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(configMapStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, namespace, gd, resource, customOwnerSignatureConnections(configMapStatus.CustomOwner))
 	}
 	if resource == GPERSISTENTVOLUME {
 		gd := &GPersistentVolume{}
+		volumeStatus := status.(*GPersistentVolumeStatus)
+		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(volumeStatus)
+		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, CLUSTER_SCOPED_NAMESPACE, gd, resource, persistentVolumeSignatureConnections(volumeStatus))
+	}
+	if resource == GSTORAGECLASS {
+		gd := &GStorageClass{}
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(status.(*GStorageClassStatus))
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveSlot(name, CLUSTER_SCOPED_NAMESPACE, gd, resource, []GSignatureConnection{})
 	}
 	if resource == GPERSISTENTVOLUMECLAIM {
 		gd := &GPersistentVolumeClaim{}
+		claimStatus := status.(*GPersistentVolumeClaimStatus)
 		gd.Create(gc, name, namespace, randomDisplacement, gc.font, shader.ID, settings, true)
+		gd.SetKubeState(kubeState)
+		gd.SetStatus(claimStatus)
 		gc.gobjects = append(gc.gobjects, gd)
+		gc.CreateAndReserveOrdinalSlot(name, namespace, gd, resource, customOwnerSignatureConnections(claimStatus.CustomOwner), claimStatus.Index)
 	}
 	if resource == GCLUSTEROBJECTFRAME {
 		gof := &GClusterObjectFrame{}
@@ -0,0 +1,136 @@
+package gkube
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+type GLayout int
+
+const (
+	GLAYOUT_NAMESPACE GLayout = iota // pods are slotted next to their owners, one layer per namespace
+	GLAYOUT_NODE      GLayout = iota // pods are pulled out of their namespaces and grouped under the node they are scheduled on
+)
+
+// the node layout is drawn in a layer below the cluster-scoped layer
+const nodeLayoutLayer = -2
+
+// SetLayout switches between the namespace and node layouts and moves the GOBJECTs into place
+func (gc *GCluster) SetLayout(layout GLayout) {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	gc.layout = layout
+	gc.resyncAllSlotOffsets()
+	gc.applyLayout()
+}
+
+// ToggleLayout is bound to a key in the main loop
+func (gc *GCluster) ToggleLayout() {
+	if gc.layout == GLAYOUT_NODE {
+		log.Println("Switching to namespace layout")
+		gc.SetLayout(GLAYOUT_NAMESPACE)
+	} else {
+		log.Println("Switching to node layout")
+		gc.SetLayout(GLAYOUT_NODE)
+	}
+}
+
+// resyncAllSlotOffsets moves every slotted GOBJECT back to its place in the namespace layout
+func (gc *GCluster) resyncAllSlotOffsets() {
+	for namespace, slotRows := range gc.slots {
+		nsIndex := getIndex(gc.namespaceSlots, namespace)
+		for rowIndex := range slotRows {
+			syncSlotOffsets(gc.origin, nsIndex, rowIndex, slotRows[rowIndex])
+		}
+	}
+}
+
+// applyLayout overrides the namespace layout offsets of nodes and pods while in the node layout. It is called after
+// anything that resyncs slot offsets, and after pods are rescheduled.
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) applyLayout() {
+	if gc.layout != GLAYOUT_NODE {
+		return
+	}
+	nodes := []*GNode{}
+	podsByNode := map[string][]*GPod{}
+	for _, gob := range gc.gobjects {
+		if gn, ok := gob.(*GNode); ok {
+			nodes = append(nodes, gn)
+		}
+		if gp, ok := gob.(*GPod); ok {
+			podsByNode[gp.status.NodeName] = append(podsByNode[gp.status.NodeName], gp)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *GNode) int {
+		return strings.Compare(a.name, b.name)
+	})
+	rowIndex := 0
+	for _, gn := range nodes {
+		syncNodeLayoutOffsets(gc.origin, rowIndex, gn, podsByNode[gn.name])
+		delete(podsByNode, gn.name)
+		rowIndex++
+	}
+	// pods that are not scheduled yet, or whose node is not drawn yet, are grouped in a row after every node
+	unscheduled := []*GPod{}
+	for _, pods := range podsByNode {
+		unscheduled = append(unscheduled, pods...)
+	}
+	if len(unscheduled) > 0 {
+		syncNodeLayoutOffsets(gc.origin, rowIndex, nil, unscheduled)
+	}
+}
+
+// syncNodeLayoutOffsets lines up a node and its pods, ordered by namespace and name, in a row of the node layer
+func syncNodeLayoutOffsets(origin mgl.Vec3, rowIndex int, node *GNode, pods []*GPod) {
+	slices.SortFunc(pods, func(a, b *GPod) int {
+		if c := strings.Compare(a.namespace, b.namespace); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+	stride := float32(6.0)
+	xOffset := float32(rowIndex) * stride
+	yOffset := float32(nodeLayoutLayer) * stride
+	if node != nil {
+		node.GetCurrentOffset()[0] = origin.X() + xOffset
+		node.GetCurrentOffset()[1] = origin.Y() + yOffset
+		node.GetCurrentOffset()[2] = origin.Z()
+	}
+	for i, gp := range pods {
+		gp.GetCurrentOffset()[0] = origin.X() + xOffset
+		gp.GetCurrentOffset()[1] = origin.Y() + yOffset
+		gp.GetCurrentOffset()[2] = origin.Z() + float32(i+1)*stride
+	}
+}
+
+// inNamespaceLayout reports whether the GOBJECT is drawn in its namespace, i.e. pods are not while in the node layout
+func (gc *GCluster) inNamespaceLayout(gob GObject) bool {
+	return gc.layout != GLAYOUT_NODE || gob.GetResource() != GPOD
+}
+
+// nodePods finds the pods scheduled on the node
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) nodePods(nodeName string) []*GPod {
+	pods := []*GPod{}
+	for _, gob := range gc.gobjects {
+		if gp, ok := gob.(*GPod); ok && gp.status.NodeName == nodeName {
+			pods = append(pods, gp)
+		}
+	}
+	return pods
+}
+
+// getPodsOnNode finds the pods scheduled on the node
+func (gc *GCluster) getPodsOnNode(nodeName string) []GObject {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	pods := []GObject{}
+	for _, gp := range gc.nodePods(nodeName) {
+		pods = append(pods, gp)
+	}
+	return pods
+}
@@ -5,6 +5,8 @@ import (
 	"slices"
 	"strings"
 
+	mgl "github.com/go-gl/mathgl/mgl32"
+
 	"github.com/kabicin/kubechaser/renderer/utils"
 )
 
@@ -27,6 +29,7 @@ type SlotResource struct {
 	resource                    GResource
 	name                        string
 	namespace                   string
+	ordinal                     int32 // orders slots of the same resource within a slot row, i.e. StatefulSet pods
 	connectedResourceSignatures []GSignatureConnection
 }
 
@@ -63,55 +66,130 @@ func (sr1 *SlotResource) hasCollision(sr2 SlotResource) int {
 	return 0
 }
 
+// cluster-scoped resources, i.e. PersistentVolumes and StorageClasses, are slotted under the empty namespace. It is never
+// added to namespaceSlots, so its rows are laid out in a layer of their own below every namespace.
+const CLUSTER_SCOPED_NAMESPACE = ""
+
+// resources that other resources are slotted under, by the kind found in ownerReferences
+var OWNER_KIND_RESOURCES = map[string]GResource{
+	"Deployment":  GDEPLOYMENT,
+	"StatefulSet": GSTATEFULSET,
+	"DaemonSet":   GDAEMONSET,
+	"CronJob":     GCRONJOB,
+	"ReplicaSet":  GREPLICASET,
+	"Job":         GJOB,
+}
+
+// GetResourceIndex orders the resources of a slot row: custom resources, which may own anything, then top-level owners,
+// intermediate owners, pods and claims. Autoscalers sit with the workloads they scale, roles lead the rows of their
+// bindings, and in the cluster-scoped layer a storage class leads the row of its volumes.
 func GetResourceIndex(resource GResource) int {
-	if resource == GDEPLOYMENT {
+	if resource == GCUSTOMRESOURCE {
+		return -1
+	} else if resource == GDEPLOYMENT || resource == GSTATEFULSET || resource == GDAEMONSET || resource == GCRONJOB || resource == GSTORAGECLASS || resource == GROLE || resource == GCLUSTERROLE || resource == GHORIZONTALPODAUTOSCALER {
 		return 0
-	} else if resource == GREPLICASET {
+	} else if resource == GREPLICASET || resource == GJOB || resource == GPERSISTENTVOLUME || resource == GROLEBINDING || resource == GCLUSTERROLEBINDING {
 		return 1
 	} else if resource == GPOD {
 		return 2
+	} else if resource == GPERSISTENTVOLUMECLAIM {
+		return 3
 	}
 	return 100
 }
 
+// detachSlot removes the slot matching resource from its slot row without deleting its GOBJECT, drops the row if it
+// became empty and resyncs the offsets of the remaining rows in the namespace
+func (gc *GCluster) detachSlot(resource SlotResource) (SlotResource, bool) {
+	namespace := resource.namespace
+	if _, found := gc.slots[namespace]; !found {
+		return SlotResource{}, false
+	}
+	foundI := -1
+	foundJ := -1
+	for i, slotRow := range gc.slots[namespace] {
+		for j, slot := range slotRow {
+			if slot.GetSignature() == resource.GetSignature() {
+				foundI = i
+				foundJ = j
+			}
+		}
+	}
+	if foundI == -1 || foundJ == -1 {
+		return SlotResource{}, false
+	}
+	detached := gc.slots[namespace][foundI][foundJ]
+	gc.slots[namespace][foundI] = slices.Delete(gc.slots[namespace][foundI], foundJ, foundJ+1)
+	// if the surrounding array is empty, remove it
+	if len(gc.slots[namespace][foundI]) == 0 {
+		gc.slots[namespace] = slices.Delete(gc.slots[namespace], foundI, foundI+1)
+	}
+	nsIndex := getIndex(gc.namespaceSlots, namespace)
+	for rowIndex := range gc.slots[namespace] {
+		syncSlotOffsets(gc.origin, nsIndex, rowIndex, gc.slots[namespace][rowIndex])
+	}
+	gc.applyLayout()
+	return detached, true
+}
+
 func (gc *GCluster) EvictSlot(deleteResource SlotResource) {
-	if _, found := gc.slots[deleteResource.namespace]; found {
-		foundI := -1
-		foundJ := -1
-		for i, slotRow := range gc.slots[deleteResource.namespace] {
-			for j, slot := range slotRow {
-				if slot.GetSignature() == deleteResource.GetSignature() {
-					foundI = i
-					foundJ = j
-				}
+	// if found, evict the slot and GOBJECT with it
+	if slot, found := gc.detachSlot(deleteResource); found {
+		fmt.Println("Evicting...")
+		object := slot.object                         // hold handle to the GOBJECT
+		gc.mainScene.DeleteObject(object.GetObject()) // remove from the main scene - stops drawing
+		object.Delete()                               // release anything the GOBJECT drew besides itself, i.e. links
+		gc.DeleteGObject(object)                      // delete the GOBJECT from cluster
+	}
+}
+
+func sameSignatureConnections(a, b []GSignatureConnection) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equals(&b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateSlotConnections moves a slot to the row of its new connected resources, i.e. when a ReplicaSet changes owners
+func (gc *GCluster) UpdateSlotConnections(resource SlotResource, connectedResources []GSignatureConnection) {
+	for _, slotRow := range gc.slots[resource.namespace] {
+		for _, slot := range slotRow {
+			if slot.GetSignature() == resource.GetSignature() && sameSignatureConnections(slot.connectedResourceSignatures, connectedResources) {
+				return // nothing to move
 			}
 		}
-		// if found, evict the slot and GOBJECT with it
-		if foundI != -1 && foundJ != -1 {
-			fmt.Println("Evicting...")
-			object := gc.slots[deleteResource.namespace][foundI][foundJ].object // hold handle to the GOBJECT
-
-			if foundJ == 0 {
-				gc.slots[deleteResource.namespace][foundI] = gc.slots[deleteResource.namespace][foundI][1:]
-			} else if foundJ == len(gc.slots[deleteResource.namespace][foundI])-1 {
-				gc.slots[deleteResource.namespace][foundI] = gc.slots[deleteResource.namespace][foundI][:len(gc.slots[deleteResource.namespace][foundI])-1]
-			} else {
-				gc.slots[deleteResource.namespace][foundI] = append(gc.slots[deleteResource.namespace][foundI][:foundJ], gc.slots[deleteResource.namespace][foundI][foundJ+1:]...) // cluster to remove ref to GOBJECT
+	}
+	if slot, found := gc.detachSlot(resource); found {
+		slot.connectedResourceSignatures = connectedResources
+		gc.ReserveSlot(slot.namespace, slot)
+	}
+}
+
+// setSlotOrdinal moves a slot among the slots of the same resource in its row to where its new ordinal orders it, i.e.
+// once a rollback makes an old ReplicaSet the newest revision. It reports whether the slot moved, so that the caller
+// applies the layout once after setting every ordinal.
+func (gc *GCluster) setSlotOrdinal(resource SlotResource, ordinal int32) bool {
+	nsIndex := getIndex(gc.namespaceSlots, resource.namespace)
+	for rowIndex, slotRow := range gc.slots[resource.namespace] {
+		for i := range slotRow {
+			if slotRow[i].GetSignature() != resource.GetSignature() {
+				continue
 			}
-			// if the surrounding array is empty, remove it
-			if len(gc.slots[deleteResource.namespace][foundI]) == 0 {
-				if foundI == 0 {
-					gc.slots[deleteResource.namespace] = gc.slots[deleteResource.namespace][1:]
-				} else if foundI == len(gc.slots[deleteResource.namespace])-1 {
-					gc.slots[deleteResource.namespace] = gc.slots[deleteResource.namespace][:len(gc.slots[deleteResource.namespace][foundI])-1]
-				} else {
-					gc.slots[deleteResource.namespace] = append(gc.slots[deleteResource.namespace][:foundI], gc.slots[deleteResource.namespace][foundI+1:]...)
-				}
+			if slotRow[i].ordinal != ordinal {
+				slotRow[i].ordinal = ordinal
+				gc.slots[resource.namespace][rowIndex] = MergeSort(slotRow)
+				syncSlotOffsets(gc.origin, nsIndex, rowIndex, gc.slots[resource.namespace][rowIndex])
+				return true
 			}
-			gc.mainScene.DeleteObject(object.GetObject()) // remove from the main scene - stops drawing
-			gc.DeleteGObject(object)                      // delete the GOBJECT from cluster
+			return false
 		}
 	}
+	return false
 }
 
 func (gc *GCluster) SetDeletingSlot(deleteResource SlotResource) {
@@ -134,6 +212,54 @@ func (gc *GCluster) SetDeletingSlot(deleteResource SlotResource) {
 	}
 }
 
+// SetDeletingNamespace animates out the frame of a deleted namespace along with every slot within it. The garbage
+// collector evicts the slots, then the frame, and finally compacts the remaining namespaces.
+func (gc *GCluster) SetDeletingNamespace(namespace string) {
+	for _, slotRow := range gc.slots[namespace] {
+		for _, slot := range slotRow {
+			slot.object.SetDeleting()
+			if !slices.Contains(gc.gcSlots, slot.object) {
+				gc.gcSlots = append(gc.gcSlots, slot.object)
+			}
+		}
+	}
+	for _, frame := range gc.gobjectFrames {
+		if name, _ := frame.GetIdentifier(); frame.GetResource() == GNAMESPACEOBJECTFRAME && name == namespace && !slices.Contains(gc.gcFrames, frame) {
+			frame.SetDeleting()
+			gc.gcFrames = append(gc.gcFrames, frame)
+		}
+	}
+}
+
+// evictNamespaceFrame removes the frame of a deleted namespace once it and its slots have animated out. The namespace
+// then gives up its layer, unless it was re-created in the meantime, and the layers below move up.
+func (gc *GCluster) evictNamespaceFrame(frame GObjectFrame) bool {
+	namespace, _ := frame.GetIdentifier()
+	if !frame.GetObject().IsDeleteReady {
+		return false
+	}
+	for _, gob := range gc.gcSlots {
+		if _, ns := gob.GetIdentifier(); ns == namespace {
+			return false
+		}
+	}
+	gc.mainScene.DeleteObject(frame.GetObject())
+	frame.Delete()
+	gc.DeleteGObject(frame)
+	gc.gobjectFrames = slices.DeleteFunc(gc.gobjectFrames, func(gof GObjectFrame) bool {
+		return gof == frame
+	})
+	if len(gc.slots[namespace]) == 0 {
+		delete(gc.slots, namespace)
+		gc.namespaceSlots = slices.DeleteFunc(gc.namespaceSlots, func(ns string) bool {
+			return ns == namespace
+		})
+		gc.resyncAllSlotOffsets()
+		gc.applyLayout()
+	}
+	return true
+}
+
 // RESOURCE SLOTS
 //
 //	0   D - RS - P1 - P2
@@ -149,11 +275,17 @@ func (gc *GCluster) SetDeletingSlot(deleteResource SlotResource) {
 //	}
 
 func (gc *GCluster) CreateAndReserveSlot(name, namespace string, object GObject, reservee GResource, connectedResources []GSignatureConnection) {
+	gc.CreateAndReserveOrdinalSlot(name, namespace, object, reservee, connectedResources, 0)
+}
+
+// CreateAndReserveOrdinalSlot reserves a slot that is kept in ordinal order among slots of the same resource in its row
+func (gc *GCluster) CreateAndReserveOrdinalSlot(name, namespace string, object GObject, reservee GResource, connectedResources []GSignatureConnection, ordinal int32) {
 	sr := SlotResource{
 		name:                        name,
 		namespace:                   namespace,
 		object:                      object,
 		resource:                    reservee,
+		ordinal:                     ordinal,
 		connectedResourceSignatures: connectedResources,
 	}
 	debug := false
@@ -169,13 +301,25 @@ func (gc *GCluster) CreateAndReserveSlot(name, namespace string, object GObject,
 	gc.ReserveSlot(namespace, sr)
 }
 
-func getReplicaSetInsertIndex(slotRow []SlotResource) int {
+// getTopLevelInsertIndex returns the index after all custom resources, i.e. where a top-level owner is prepended
+func getTopLevelInsertIndex(slotRow []SlotResource) int {
+	for i, slot := range slotRow {
+		if GetResourceIndex(slot.resource) >= 0 {
+			return i
+		}
+	}
+	return len(slotRow)
+}
+
+// getReplicaSetInsertIndex returns the index after all top-level owners and any slots of the same resource ordered ahead
+// of sr, i.e. where a ReplicaSet or Job is inserted
+func getReplicaSetInsertIndex(slotRow []SlotResource, sr SlotResource) int {
 	insertIndex := 0
 	if len(slotRow) == 0 {
 		return insertIndex
 	}
 	for i, slot := range slotRow {
-		if slot.resource == GPOD || slot.resource == GREPLICASET {
+		if GetResourceIndex(slot.resource) > 0 && !slot.LessThan(&sr) {
 			return i
 		}
 		insertIndex = i + 1
@@ -183,6 +327,16 @@ func getReplicaSetInsertIndex(slotRow []SlotResource) int {
 	return insertIndex
 }
 
+// getSlotInsertIndex returns the index before the first slot in the row that should be ordered after sr
+func getSlotInsertIndex(slotRow []SlotResource, sr SlotResource) int {
+	for i := range slotRow {
+		if sr.LessThan(&slotRow[i]) {
+			return i
+		}
+	}
+	return len(slotRow)
+}
+
 func getIndex(arr []string, elem string) int {
 	for j, v := range arr {
 		if v == elem {
@@ -192,10 +346,11 @@ func getIndex(arr []string, elem string) int {
 	return -1
 }
 
-func syncSlotOffsets(nsIndex int, rowIndex int, sr []SlotResource) {
+func syncSlotOffsets(origin mgl.Vec3, nsIndex int, rowIndex int, sr []SlotResource) {
 	// rowIndex provides x offset
 	// position in namespaceSlots provides y offset
 	// slotResourceIndex provides z offset
+	// all relative to the origin of the cluster
 
 	stride := float32(6.0)
 	xOffset := float32(rowIndex) * stride
@@ -203,9 +358,9 @@ func syncSlotOffsets(nsIndex int, rowIndex int, sr []SlotResource) {
 	zOffset := float32(-1)
 	for i := range sr {
 		zOffset = float32(i) * stride
-		sr[i].GetObject().GetCurrentOffset()[0] = xOffset
-		sr[i].GetObject().GetCurrentOffset()[1] = yOffset
-		sr[i].GetObject().GetCurrentOffset()[2] = zOffset
+		sr[i].GetObject().GetCurrentOffset()[0] = origin.X() + xOffset
+		sr[i].GetObject().GetCurrentOffset()[1] = origin.Y() + yOffset
+		sr[i].GetObject().GetCurrentOffset()[2] = origin.Z() + zOffset
 	}
 	// fmt.Printf("    - SYNC slot at (%f,%f,%f) - slot size is now %d\n", xOffset, yOffset, zOffset, len(sr))
 
@@ -213,7 +368,12 @@ func syncSlotOffsets(nsIndex int, rowIndex int, sr []SlotResource) {
 
 // TODO: consolidate with type parameters in utils.go
 func (sr *SlotResource) LessThan(o *SlotResource) bool {
-	return GetResourceIndex(sr.resource) < GetResourceIndex(o.resource)
+	srIndex := GetResourceIndex(sr.resource)
+	oIndex := GetResourceIndex(o.resource)
+	if srIndex == oIndex {
+		return sr.ordinal < o.ordinal
+	}
+	return srIndex < oIndex
 }
 
 // TODO: consolidate with type parameters in utils.go
@@ -224,7 +384,7 @@ func merge(left []SlotResource, right []SlotResource) []SlotResource {
 	m := len(right)
 	out := []SlotResource{}
 	for i < n && j < m {
-		if left[i].LessThan(&right[j]) {
+		if !right[j].LessThan(&left[i]) { // keep equal slots in their current order
 			out = append(out, left[i])
 			i++
 		} else {
@@ -304,25 +464,25 @@ func (gc *GCluster) flattenSlots(namespace string, sr SlotResource, insertedRowI
 		}
 	}
 	// merge all collided slots with insertedRowIndex
-	for csi := range collidedSlots {
+	for _, row := range collidedSlots {
 		if debug {
 			fmt.Printf("MERGE COLLIDED SLOT for BEFORE %s\n", sr.name)
 			for _, slot := range gc.slots[namespace][insertedRowIndex] {
 				fmt.Printf("     - slot: %s (%s)\n", getGResourceName(slot.resource), slot.name)
 			}
-			fmt.Printf("MERGE COLLIDED SLOT csi for BEFORE %s\n", sr.name)
-			for _, slot := range gc.slots[namespace][csi] {
+			fmt.Printf("MERGE COLLIDED SLOT row for BEFORE %s\n", sr.name)
+			for _, slot := range gc.slots[namespace][row] {
 				fmt.Printf("     - slot: %s (%s)\n", getGResourceName(slot.resource), slot.name)
 			}
 		}
-		gc.slots[namespace][insertedRowIndex] = MergeSort(mergeAndPickUnique2(gc.slots[namespace][insertedRowIndex], gc.slots[namespace][csi])) // TOOD: requires animations
+		gc.slots[namespace][insertedRowIndex] = MergeSort(mergeAndPickUnique2(gc.slots[namespace][insertedRowIndex], gc.slots[namespace][row])) // TOOD: requires animations
 		if debug {
 			fmt.Printf("MERGE COLLIDED SLOT for AFTER %s\n", sr.name)
 			for _, slot := range gc.slots[namespace][insertedRowIndex] {
 				fmt.Printf("     - slot: %s (%s)\n", getGResourceName(slot.resource), slot.name)
 			}
-			fmt.Printf("MERGE COLLIDED SLOT csi for AFTER %s\n", sr.name)
-			for _, slot := range gc.slots[namespace][csi] {
+			fmt.Printf("MERGE COLLIDED SLOT row for AFTER %s\n", sr.name)
+			for _, slot := range gc.slots[namespace][row] {
 				fmt.Printf("     - slot: %s (%s)\n", getGResourceName(slot.resource), slot.name)
 			}
 		}
@@ -343,8 +503,9 @@ func (gc *GCluster) flattenSlots(namespace string, sr SlotResource, insertedRowI
 	// slots have been moved, so offsets must be resynced
 	nsIndex := getIndex(gc.namespaceSlots, namespace)
 	for rowIndex := range gc.slots[namespace] {
-		syncSlotOffsets(nsIndex, rowIndex, gc.slots[namespace][rowIndex])
+		syncSlotOffsets(gc.origin, nsIndex, rowIndex, gc.slots[namespace][rowIndex])
 	}
+	gc.applyLayout()
 }
 
 func (gc *GCluster) ReserveSlot(namespace string, sr SlotResource) {
@@ -354,7 +515,7 @@ func (gc *GCluster) ReserveSlot(namespace string, sr SlotResource) {
 	}
 
 	// populate namespace slots, if this is a new namespace
-	if !slices.Contains(gc.namespaceSlots, namespace) {
+	if namespace != CLUSTER_SCOPED_NAMESPACE && !slices.Contains(gc.namespaceSlots, namespace) {
 		gc.namespaceSlots = append(gc.namespaceSlots, namespace)
 	}
 
@@ -362,7 +523,8 @@ func (gc *GCluster) ReserveSlot(namespace string, sr SlotResource) {
 	// if there are no slots, reserve create the first slot row
 	if len(gc.slots[namespace]) == 0 {
 		gc.slots[namespace] = append(gc.slots[namespace], []SlotResource{sr})
-		syncSlotOffsets(nsIndex, 0, gc.slots[namespace][0])
+		syncSlotOffsets(gc.origin, nsIndex, 0, gc.slots[namespace][0])
+		gc.applyLayout()
 		fmt.Println("RESERVING FIRST SLOT")
 		return
 	}
@@ -374,22 +536,25 @@ func (gc *GCluster) ReserveSlot(namespace string, sr SlotResource) {
 		for _, slot := range slotRow { // for colIndex, slot := range slotRow {
 			if collisionSkew := slot.hasCollision(sr); collisionSkew != 0 {
 				// there is a collision
-				if sr.resource == GDEPLOYMENT {
-					gc.slots[namespace][rowIndex] = append([]SlotResource{sr}, gc.slots[namespace][rowIndex]...) // prepend sr into the Slot Row at rowIndex
-				} else if sr.resource == GREPLICASET { // insert sr after all deployments and before all pods in the Slot Row at rowIndex
-					insertIndex := getReplicaSetInsertIndex(gc.slots[namespace][rowIndex])
+				if GetResourceIndex(sr.resource) == 0 {
+					insertIndex := getTopLevelInsertIndex(gc.slots[namespace][rowIndex]) // prepend sr into the Slot Row at rowIndex, after any custom resources owning it
+					gc.slots[namespace][rowIndex] = slices.Concat(gc.slots[namespace][rowIndex][:insertIndex], []SlotResource{sr}, gc.slots[namespace][rowIndex][insertIndex:])
+				} else if GetResourceIndex(sr.resource) == 1 { // insert sr after all top-level owners and before all pods in the Slot Row at rowIndex
+					insertIndex := getReplicaSetInsertIndex(gc.slots[namespace][rowIndex], sr)
 					if insertIndex >= len(gc.slots[namespace][rowIndex]) {
 						gc.slots[namespace][rowIndex] = append(gc.slots[namespace][rowIndex], sr) // case 1: insert index is out of array bounds
 					} else {
 						gc.slots[namespace][rowIndex] = append(gc.slots[namespace][rowIndex][:insertIndex], append([]SlotResource{sr}, gc.slots[namespace][rowIndex][insertIndex:]...)...) // case 2: insert index is in array bounds
 					}
 				} else {
-					gc.slots[namespace][rowIndex] = append(gc.slots[namespace][rowIndex], sr) // append sr into the Slot Row at rowIndex
+					insertIndex := getSlotInsertIndex(gc.slots[namespace][rowIndex], sr) // append sr into the Slot Row at rowIndex, ahead of any slots ordered after it
+					gc.slots[namespace][rowIndex] = slices.Insert(gc.slots[namespace][rowIndex], insertIndex, sr)
 				}
-				syncSlotOffsets(nsIndex, rowIndex, gc.slots[namespace][rowIndex]) // refresh the Slot Row by syncing all slot offsets
+				syncSlotOffsets(gc.origin, nsIndex, rowIndex, gc.slots[namespace][rowIndex]) // refresh the Slot Row by syncing all slot offsets
 				// insertIndex := len(gc.slots[namespace][rowIndex]) - 1
 				inserted = true // find the index of insertion for sr
 				insertRowIndex = rowIndex
+				break // sr is inserted once per Slot Row, however many slots in it it collides with
 			}
 		}
 	}
@@ -397,7 +562,7 @@ func (gc *GCluster) ReserveSlot(namespace string, sr SlotResource) {
 	if !inserted {
 		gc.slots[namespace] = append(gc.slots[namespace], []SlotResource{sr})
 		lastInsertIndex := len(gc.slots[namespace]) - 1
-		syncSlotOffsets(nsIndex, lastInsertIndex, gc.slots[namespace][lastInsertIndex])
+		syncSlotOffsets(gc.origin, nsIndex, lastInsertIndex, gc.slots[namespace][lastInsertIndex])
 		inserted = true
 		insertRowIndex = lastInsertIndex
 	}
@@ -1,8 +1,16 @@
 package gkube
 
 import (
+	"fmt"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	v41 "github.com/4ydx/gltext/v4.1"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
 )
 
 type Test struct {
@@ -73,3 +81,629 @@ func Test_MergeSort(t *testing.T) {
 		}}},
 	})
 }
+
+// testGObject stands in for a rendered GObject so that slot layout can be tested without a GL context
+type testGObject struct {
+	name      string
+	namespace string
+	resource  GResource
+	offset    *mgl.Vec3
+	object    *scene.SceneObject
+}
+
+func (gt *testGObject) Create(*GCluster, string, string, *mgl.Vec3, *v41.Font, uint32, GSettings, bool) *scene.SceneObject {
+	return nil
+}
+func (gt *testGObject) OnClick()                        {}
+func (gt *testGObject) GetObject() *scene.SceneObject   { return gt.object }
+func (gt *testGObject) GetIdentifier() (string, string) { return gt.name, gt.namespace }
+func (gt *testGObject) GetResource() GResource          { return gt.resource }
+func (gt *testGObject) GetCurrentOffset() *mgl.Vec3     { return gt.offset }
+func (gt *testGObject) SetDeleting()                    { gt.object.IsDeleting = true }
+func (gt *testGObject) Delete()                         {}
+
+type testGObjectFrame struct {
+	testGObject
+}
+
+func (gt *testGObjectFrame) SetObjectFrame(center, bounds mgl.Vec3, frameStyle entity.FrameStyle, onPostInitCallback func()) {
+}
+func (gt *testGObjectFrame) UpdateObjectFrame(center, bounds mgl.Vec3, frameStyle entity.FrameStyle, onPostInitCallback func()) {
+}
+
+func createTestCluster() *GCluster {
+	return &GCluster{
+		mainScene:      &scene.Scene{},
+		gobjectMutex:   &sync.Mutex{},
+		gcSlotsMutex:   &sync.Mutex{},
+		slots:          make(map[string][][]SlotResource),
+		namespaceSlots: []string{},
+	}
+}
+
+func reserveTestSlot(gc *GCluster, resource GResource, name, namespace string, owner *GSignatureConnection) *testGObject {
+	gob := &testGObject{name: name, namespace: namespace, resource: resource, offset: &mgl.Vec3{}, object: &scene.SceneObject{}}
+	sigConns := []GSignatureConnection{}
+	if owner != nil {
+		sigConns = append(sigConns, *owner)
+	}
+	gc.CreateAndReserveSlot(name, namespace, gob, resource, sigConns)
+	return gob
+}
+
+func slotRowNames(row []SlotResource) []string {
+	names := []string{}
+	for _, slot := range row {
+		names = append(names, slot.name)
+	}
+	return names
+}
+
+func Test_ReserveSlot(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	// pods arrive before their owners, which must pull them into a single row
+	reserveTestSlot(gc, GPOD, "web-abc-1", ns, &GSignatureConnection{resource: GREPLICASET, name: "web-abc", namespace: ns})
+	reserveTestSlot(gc, GPOD, "orphan", ns, nil)
+	reserveTestSlot(gc, GREPLICASET, "web-abc", ns, &GSignatureConnection{resource: GDEPLOYMENT, name: "web", namespace: ns})
+	deploy := reserveTestSlot(gc, GDEPLOYMENT, "web", ns, nil)
+	pod := reserveTestSlot(gc, GPOD, "web-abc-2", ns, &GSignatureConnection{resource: GREPLICASET, name: "web-abc", namespace: ns})
+	other := reserveTestSlot(gc, GDEPLOYMENT, "other", "other-namespace", nil)
+
+	checkTests(t, []Test{
+		{len(gc.slots[ns]), 2},
+		{slotRowNames(gc.slots[ns][0]), []string{"web", "web-abc", "web-abc-1", "web-abc-2"}},
+		{slotRowNames(gc.slots[ns][1]), []string{"orphan"}},
+		{*deploy.offset, mgl.Vec3{0, 0, 0}},
+		{*pod.offset, mgl.Vec3{0, 0, 18}},
+		{*other.offset, mgl.Vec3{0, 6, 0}},
+	})
+}
+
+func Test_UpdateSlotConnections(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GDEPLOYMENT, "a", ns, nil)
+	reserveTestSlot(gc, GDEPLOYMENT, "b", ns, nil)
+	rs := reserveTestSlot(gc, GREPLICASET, "rs", ns, &GSignatureConnection{resource: GDEPLOYMENT, name: "a", namespace: ns})
+
+	// the replicaset is adopted by deployment b
+	gc.UpdateSlotConnections(SlotResource{name: "rs", namespace: ns, resource: GREPLICASET}, []GSignatureConnection{{resource: GDEPLOYMENT, name: "b", namespace: ns}})
+
+	checkTests(t, []Test{
+		{slotRowNames(gc.slots[ns][0]), []string{"a"}},
+		{slotRowNames(gc.slots[ns][1]), []string{"b", "rs"}},
+		{*rs.offset, mgl.Vec3{6, 0, 6}},
+	})
+}
+
+func Test_ReserveSlotSharedConfiguration(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GDEPLOYMENT, "a", ns, nil)
+	reserveTestSlot(gc, GDEPLOYMENT, "b", ns, nil)
+	reserveTestSlot(gc, GREPLICASET, "a-1", ns, &GSignatureConnection{resource: GDEPLOYMENT, name: "a", namespace: ns})
+	reserveTestSlot(gc, GREPLICASET, "b-1", ns, &GSignatureConnection{resource: GDEPLOYMENT, name: "b", namespace: ns})
+	reserveTestSlot(gc, GSECRET, "shared", ns, nil)
+	// every pod pulls with the same secret, which is linked but does not pull the workloads into one row
+	for _, pod := range []struct{ name, replicaSet string }{{"a-1-x", "a-1"}, {"a-1-y", "a-1"}, {"b-1-x", "b-1"}} {
+		status := &GPodStatus{OwnerReferenceName: pod.replicaSet, OwnerReferenceType: "ReplicaSet", Secrets: []string{"shared"}}
+		gob := &testGObject{name: pod.name, namespace: ns, resource: GPOD, offset: &mgl.Vec3{}, object: &scene.SceneObject{}}
+		gc.CreateAndReserveSlot(pod.name, ns, gob, GPOD, podSignatureConnections(ns, status))
+	}
+	// a slot colliding with several slots of a row is still inserted once
+	gob := &testGObject{name: "a-1-z", namespace: ns, resource: GPOD, offset: &mgl.Vec3{}, object: &scene.SceneObject{}}
+	gc.CreateAndReserveSlot("a-1-z", ns, gob, GPOD, []GSignatureConnection{{resource: GREPLICASET, name: "a-1", namespace: ns}, {resource: GDEPLOYMENT, name: "a", namespace: ns}})
+	rows := [][]string{}
+	for _, row := range gc.slots[ns] {
+		rows = append(rows, slotRowNames(row))
+	}
+	checkTests(t, []Test{
+		{rows, [][]string{{"a", "a-1", "a-1-x", "a-1-y", "a-1-z"}, {"b", "b-1", "b-1-x"}, {"shared"}}},
+	})
+}
+
+func Test_FlattenCollidedSlots(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GDEPLOYMENT, "other", ns, nil)
+	// the pods arrive before their replicaset, each in a row of its own
+	for _, name := range []string{"web-1-x", "web-1-y", "web-1-z"} {
+		reserveTestSlot(gc, GPOD, name, ns, &GSignatureConnection{resource: GREPLICASET, name: "web-1", namespace: ns})
+	}
+	// the replicaset is inserted into the row of the first pod and merges the rows of the others, leaving the first row be
+	reserveTestSlot(gc, GREPLICASET, "web-1", ns, nil)
+	rows := [][]string{}
+	for _, row := range gc.slots[ns] {
+		rows = append(rows, slotRowNames(row))
+	}
+	checkTests(t, []Test{
+		{rows, [][]string{{"other"}, {"web-1", "web-1-z", "web-1-x", "web-1-y"}}},
+	})
+}
+
+func Test_ReserveOrdinalSlot(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	for _, ordinal := range []int32{2, 0, 1} {
+		name := fmt.Sprintf("db-%d", ordinal)
+		status := &GPodStatus{OwnerReferenceName: "db", OwnerReferenceType: "StatefulSet", PersistentVolumeClaims: []string{"data-" + name, "shared"}}
+		gc.CreateAndReserveOrdinalSlot(name, ns, &testGObject{name: name, namespace: ns, resource: GPOD, offset: &mgl.Vec3{}}, GPOD, podSignatureConnections(ns, status), ordinal)
+	}
+	gc.CreateAndReserveOrdinalSlot("data-db-1", ns, &testGObject{name: "data-db-1", namespace: ns, resource: GPERSISTENTVOLUMECLAIM, offset: &mgl.Vec3{}}, GPERSISTENTVOLUMECLAIM, []GSignatureConnection{}, 1)
+	gc.CreateAndReserveOrdinalSlot("data-db-0", ns, &testGObject{name: "data-db-0", namespace: ns, resource: GPERSISTENTVOLUMECLAIM, offset: &mgl.Vec3{}}, GPERSISTENTVOLUMECLAIM, []GSignatureConnection{}, 0)
+	db := &GStatefulSetStatus{Claims: []string{"data-db-0", "data-db-1", "data-db-2"}}
+	gc.CreateAndReserveSlot("db", ns, &testGObject{name: "db", namespace: ns, resource: GSTATEFULSET, offset: &mgl.Vec3{}}, GSTATEFULSET, statefulSetSignatureConnections(ns, db))
+	// a pod of another workload mounting the same RWX claim stays in the row of its own workload
+	reserveTestSlot(gc, GDEPLOYMENT, "backup", ns, nil)
+	backup := &GPodStatus{OwnerReferenceName: "backup", OwnerReferenceType: "Deployment", PersistentVolumeClaims: []string{"shared"}}
+	gc.CreateAndReserveSlot("backup-x", ns, &testGObject{name: "backup-x", namespace: ns, resource: GPOD, offset: &mgl.Vec3{}}, GPOD, podSignatureConnections(ns, backup))
+	reserveTestSlot(gc, GPERSISTENTVOLUMECLAIM, "shared", ns, nil)
+
+	// the claims of the StatefulSet line up in its row by their ordinal, other claims are only linked to the pods mounting
+	// them rather than slotted with them
+	rows := [][]string{}
+	for _, row := range gc.slots[ns] {
+		rows = append(rows, slotRowNames(row))
+	}
+	checkTests(t, []Test{
+		{rows, [][]string{{"db", "db-0", "db-1", "db-2", "data-db-0", "data-db-1"}, {"backup", "backup-x"}, {"shared"}}},
+	})
+}
+
+func Test_ReserveSlotCronJob(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GPOD, "backup-28500-x", ns, &GSignatureConnection{resource: GJOB, name: "backup-28500", namespace: ns})
+	reserveTestSlot(gc, GSERVICE, "backup", ns, &GSignatureConnection{resource: GPOD, name: "backup-28500-x", namespace: ns})
+	reserveTestSlot(gc, GJOB, "backup-28500", ns, &GSignatureConnection{resource: GCRONJOB, name: "backup", namespace: ns})
+	reserveTestSlot(gc, GCRONJOB, "backup", ns, nil)
+	reserveTestSlot(gc, GJOB, "backup-28501", ns, &GSignatureConnection{resource: GCRONJOB, name: "backup", namespace: ns})
+
+	checkTests(t, []Test{
+		{len(gc.slots[ns]), 1},
+		{slotRowNames(gc.slots[ns][0]), []string{"backup", "backup-28501", "backup-28500", "backup-28500-x", "backup"}},
+	})
+}
+
+func Test_ReserveClusterScopedSlot(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GDEPLOYMENT, "web", ns, nil)
+	pv := reserveTestSlot(gc, GPERSISTENTVOLUME, "pv-1", CLUSTER_SCOPED_NAMESPACE, &GSignatureConnection{resource: GSTORAGECLASS, name: "standard", namespace: CLUSTER_SCOPED_NAMESPACE})
+	sc := reserveTestSlot(gc, GSTORAGECLASS, "standard", CLUSTER_SCOPED_NAMESPACE, nil)
+
+	// cluster-scoped slots form a layer below every namespace, with the storage class leading its volumes
+	checkTests(t, []Test{
+		{gc.namespaceSlots, []string{ns}},
+		{slotRowNames(gc.slots[CLUSTER_SCOPED_NAMESPACE][0]), []string{"standard", "pv-1"}},
+		{*sc.offset, mgl.Vec3{0, -6, 0}},
+		{*pv.offset, mgl.Vec3{0, -6, 6}},
+	})
+}
+
+func Test_NodeLayout(t *testing.T) {
+	gc := createTestCluster()
+	gc.layout = GLAYOUT_NODE
+	nodeA := &GNode{name: "node-a", status: &GNodeStatus{Ready: true}, currentOffset: &mgl.Vec3{}}
+	nodeB := &GNode{name: "node-b", status: &GNodeStatus{}, currentOffset: &mgl.Vec3{}}
+	web := &GPod{name: "web", namespace: "a", status: &GPodStatus{NodeName: "node-b"}, currentOffset: &mgl.Vec3{}}
+	db := &GPod{name: "db", namespace: "b", status: &GPodStatus{NodeName: "node-a"}, currentOffset: &mgl.Vec3{}}
+	api := &GPod{name: "api", namespace: "a", status: &GPodStatus{NodeName: "node-b"}, currentOffset: &mgl.Vec3{}}
+	pending := &GPod{name: "pending", namespace: "a", status: &GPodStatus{}, currentOffset: &mgl.Vec3{}}
+	gc.gobjects = []GObject{nodeB, nodeA, web, db, api, pending}
+	for _, gob := range gc.gobjects {
+		name, namespace := gob.GetIdentifier()
+		gc.CreateAndReserveSlot(name, namespace, gob, gob.GetResource(), []GSignatureConnection{})
+	}
+
+	// nodes lead rows of their pods in a layer below the cluster-scoped layer, unscheduled pods come last
+	checkTests(t, []Test{
+		{*nodeA.currentOffset, mgl.Vec3{0, -12, 0}},
+		{*db.currentOffset, mgl.Vec3{0, -12, 6}},
+		{*nodeB.currentOffset, mgl.Vec3{6, -12, 0}},
+		{*api.currentOffset, mgl.Vec3{6, -12, 6}},
+		{*web.currentOffset, mgl.Vec3{6, -12, 12}},
+		{*pending.currentOffset, mgl.Vec3{12, -12, 6}},
+		{len(gc.nodePods("node-b")), 2},
+	})
+
+	// switching back returns every pod to its slot
+	gc.layout = GLAYOUT_NAMESPACE
+	gc.resyncAllSlotOffsets()
+	checkTests(t, []Test{
+		{*web.currentOffset, mgl.Vec3{0, 0, 0}},
+		{*db.currentOffset, mgl.Vec3{0, 6, 0}},
+		{*nodeB.currentOffset, mgl.Vec3{0, -6, 0}},
+	})
+}
+
+func Test_ReserveCustomResourceSlot(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	kafka := &GSignatureConnection{resource: GCUSTOMRESOURCE, name: "kafka.kafka.strimzi.io/events", namespace: ns}
+	reserveTestSlot(gc, GCUSTOMRESOURCE, "kafka.kafka.strimzi.io/events", ns, nil)
+	statefulSet := reserveTestSlot(gc, GSTATEFULSET, "events-kafka", ns, kafka)
+	reserveTestSlot(gc, GPOD, "events-kafka-0", ns, &GSignatureConnection{resource: GSTATEFULSET, name: "events-kafka", namespace: ns})
+	reserveTestSlot(gc, GDEPLOYMENT, "events-entity-operator", ns, kafka)
+	owner := GCustomOwner{Name: "kafka.kafka.strimzi.io/events", Namespace: ns}
+	exporter := &GPodStatus{CustomOwner: owner}
+	gc.CreateAndReserveSlot("events-exporter", ns, &testGObject{name: "events-exporter", namespace: ns, resource: GPOD, offset: &mgl.Vec3{}}, GPOD, podSignatureConnections(ns, exporter))
+
+	// the custom resource leads the row of every workload and pod it owns, and cluster-scoped owners are looked up among
+	// the cluster-scoped resources
+	checkTests(t, []Test{
+		{len(gc.slots[ns]), 1},
+		{slotRowNames(gc.slots[ns][0]), []string{"kafka.kafka.strimzi.io/events", "events-entity-operator", "events-kafka", "events-kafka-0", "events-exporter"}},
+		{*statefulSet.offset, mgl.Vec3{0, 0, 12}},
+		{jobSignatureConnections(ns, &GJobStatus{CustomOwner: GCustomOwner{Name: "clusterissuer.cert-manager.io/letsencrypt", Namespace: CLUSTER_SCOPED_NAMESPACE}}),
+			[]GSignatureConnection{{resource: GCUSTOMRESOURCE, name: "clusterissuer.cert-manager.io/letsencrypt", namespace: CLUSTER_SCOPED_NAMESPACE}}},
+	})
+}
+
+func Test_DeleteNamespace(t *testing.T) {
+	gc := createTestCluster()
+	ns := "ci-123"
+	deploy := reserveTestSlot(gc, GDEPLOYMENT, "web", ns, nil)
+	pod := reserveTestSlot(gc, GPOD, "web-abc-1", ns, &GSignatureConnection{resource: GDEPLOYMENT, name: "web", namespace: ns})
+	kept := reserveTestSlot(gc, GDEPLOYMENT, "api", "prod", nil)
+	frame := &testGObjectFrame{testGObject{name: ns, resource: GNAMESPACEOBJECTFRAME, offset: &mgl.Vec3{}, object: &scene.SceneObject{}}}
+	gc.gobjects = []GObject{deploy, pod, kept, frame}
+	gc.gobjectFrames = []GObjectFrame{frame}
+
+	// nothing is evicted until the namespace has animated out
+	gc.SetDeletingNamespace(ns)
+	gc.GC()
+	checkTests(t, []Test{
+		{deploy.object.IsDeleting && pod.object.IsDeleting && frame.object.IsDeleting, true},
+		{gc.namespaceSlots, []string{ns, "prod"}},
+		{*kept.offset, mgl.Vec3{0, 6, 0}},
+	})
+
+	deploy.object.IsDeleteReady = true
+	pod.object.IsDeleteReady = true
+	frame.object.IsDeleteReady = true
+	gc.GC()
+	checkTests(t, []Test{
+		{gc.namespaceSlots, []string{"prod"}},
+		{len(gc.slots[ns]), 0},
+		{len(gc.gobjectFrames), 0},
+		{gc.gobjects, []GObject{kept}},
+		{*kept.offset, mgl.Vec3{0, 0, 0}},
+	})
+}
+
+func Test_ArrangeClusters(t *testing.T) {
+	staging := createTestCluster()
+	web := reserveTestSlot(staging, GDEPLOYMENT, "web", "shop", nil)
+	reserveTestSlot(staging, GDEPLOYMENT, "api", "shop", nil)
+	production := createTestCluster()
+	production.mainScene = staging.mainScene
+	db := reserveTestSlot(production, GDEPLOYMENT, "db", "shop", nil)
+
+	// the second cluster starts after the rows of the first and a gap
+	ArrangeClusters([]*GCluster{staging, production})
+	checkTests(t, []Test{
+		{*web.offset, mgl.Vec3{0, 0, 0}},
+		{*db.offset, mgl.Vec3{36, 0, 0}},
+	})
+
+	// and moves along as the first cluster grows
+	reserveTestSlot(staging, GDEPLOYMENT, "cart", "shop", nil)
+	ArrangeClusters([]*GCluster{staging, production})
+	checkTests(t, []Test{
+		{*db.offset, mgl.Vec3{42, 0, 0}},
+		{production.origin, mgl.Vec3{42, 0, 0}},
+	})
+
+	// slots reserved later are placed from the origin of their cluster
+	cache := reserveTestSlot(production, GDEPLOYMENT, "cache", "shop", nil)
+	checkTests(t, []Test{
+		{*cache.offset, mgl.Vec3{48, 0, 0}},
+	})
+}
+
+func Test_DestroyCluster(t *testing.T) {
+	gc := createTestCluster()
+	gc.gobjectEventQueueMutex = &sync.Mutex{}
+	gc.details = &GDetails{}
+	gc.details.Create(gc, 0)
+	gc.kubeEvents = &GKubeEventIndex{}
+	gc.kubeEvents.Init()
+	light := &scene.SceneObject{}
+	gc.mainScene.AddObject(light)
+
+	web := reserveTestSlot(gc, GDEPLOYMENT, "web", "shop", nil)
+	pv := reserveTestSlot(gc, GPERSISTENTVOLUME, "data", "", nil)
+	frame := &testGObjectFrame{testGObject{name: "shop", resource: GNAMESPACEOBJECTFRAME, offset: &mgl.Vec3{}, object: &scene.SceneObject{}}}
+	gc.gobjects = []GObject{web, pv, frame}
+	gc.gobjectFrames = []GObjectFrame{frame}
+	for _, gob := range gc.gobjects {
+		gc.mainScene.AddObject(gob.GetObject())
+	}
+	gc.gobjectEventQueue = []GObjectEvent{{name: "api", namespace: "shop", resource: GDEPLOYMENT}}
+
+	// only what the cluster drew leaves the shared scene
+	gc.Destroy()
+	checkTests(t, []Test{
+		{gc.mainScene.Objects, []*scene.SceneObject{light}},
+		{len(gc.gobjects), 0},
+		{len(gc.gobjectFrames), 0},
+		{len(gc.slots), 0},
+		{gc.namespaceSlots, []string{}},
+		{len(gc.gobjectEventQueue), 0},
+	})
+}
+
+func Test_ResourceUsage(t *testing.T) {
+	gc := createTestCluster()
+	gc.kubeEvents = &GKubeEventIndex{}
+	gc.kubeEvents.Init()
+	limits := GResourceUsage{CPU: 500, Memory: 100 << 20}
+	web := &GPod{name: "web", namespace: "a", state: Running, object: &scene.SceneObject{}, status: &GPodStatus{Lifecycle: PodRunning, Requests: GResourceUsage{CPU: 100, Memory: 50 << 20}, Limits: limits,
+		Containers: []GContainerStatus{{Name: "web", Limits: limits}}}}
+	// only the sidecar is limited, so the pod is not, but the sidecar is OOM-killed by its own limit
+	sidecar := &GPod{name: "sidecar", namespace: "a", state: Running, object: &scene.SceneObject{}, status: &GPodStatus{Lifecycle: PodRunning,
+		Containers: []GContainerStatus{{Name: "app"}, {Name: "proxy", Limits: GResourceUsage{Memory: 32 << 20}}}}}
+	batch := &GPod{name: "batch", namespace: "a", state: Running, object: &scene.SceneObject{}, status: &GPodStatus{Lifecycle: PodRunning}}
+	pending := &GPod{name: "pending", namespace: "a", state: Loading, object: &scene.SceneObject{}, status: &GPodStatus{Lifecycle: PodPending, Limits: limits}}
+	node := &GNode{name: "node-a", state: Running, object: &scene.SceneObject{}, status: &GNodeStatus{Ready: true, Allocatable: GResourceUsage{CPU: 4000, Memory: 8 << 30}}}
+	gc.gobjects = []GObject{web, sidecar, batch, pending, node}
+
+	gc.setResourceUsage(&GResourceUsageStatus{
+		Pods: map[string]GResourceUsage{
+			"a/web":     {CPU: 250, Memory: 95 << 20},
+			"a/sidecar": {CPU: 100, Memory: 530 << 20},
+			"a/batch":   {CPU: 2000, Memory: 1 << 30},
+			"a/pending": {},
+		},
+		Containers: map[string]map[string]GResourceUsage{
+			"a/web":     {"web": {CPU: 250, Memory: 95 << 20}},
+			"a/sidecar": {"app": {CPU: 70, Memory: 500 << 20}, "proxy": {CPU: 30, Memory: 30 << 20}},
+		},
+		Nodes: map[string]GResourceUsage{"node-a": {CPU: 2000, Memory: 2 << 30}},
+	})
+	// the memory of web is within 10% of its limit, so it runs hot and glows; batch sets neither requests nor limits and
+	// is not heated
+	checkTests(t, []Test{
+		{web.object.Color, heatColor(podStateColors[Running], 0.95)},
+		{web.object.IsPulsing, true},
+		{sidecar.object.IsPulsing, true},
+		{sidecar.usageDetails(), []string{"usage: cpu 100m, memory 530Mi", "container proxy near its memory limit: 30Mi of 32Mi"}},
+		{batch.object.Color, podStateColors[Running]},
+		{batch.object.IsPulsing, false},
+		{pending.object.Color, podLifecycleVisuals[PodPending].color},
+		{node.object.Color, heatColor(nodeStateColors[Running], 0.5)},
+		{web.usageDetails(), []string{"usage: cpu 250m (request 100m, limit 500m), memory 95Mi (request 50Mi, limit 100Mi)", "container web near its memory limit: 95Mi of 100Mi"}},
+		{batch.usageDetails(), []string{"usage: cpu 2000m, memory 1024Mi"}},
+		{node.usageDetails(), []string{"usage: cpu 2000m of 4000m, memory 2048Mi of 8192Mi allocatable"}},
+	})
+
+	// the glow outlasts the pulse updates until the memory drops, and pods no longer reported are not heated
+	gc.UpdateKubeEventPulses()
+	checkTests(t, []Test{{web.object.IsPulsing, true}})
+	gc.setResourceUsage(&GResourceUsageStatus{Pods: map[string]GResourceUsage{"a/web": {CPU: 0, Memory: 10 << 20}}})
+	gc.UpdateKubeEventPulses()
+	checkTests(t, []Test{
+		{web.object.IsPulsing, false},
+		{web.object.Color, heatColor(podStateColors[Running], 0.1)},
+		{node.object.Color, nodeStateColors[Running]},
+		{node.usageDetails(), []string{}},
+	})
+}
+
+func Test_Containers(t *testing.T) {
+	migrate := GContainerStatus{Name: "migrate", Image: "flyway:10", State: "Terminated", Reason: "Completed"}
+	seed := GContainerStatus{Name: "seed", Image: "busybox", State: "Running"}
+	warmup := GContainerStatus{Name: "warmup", Image: "busybox"}
+	web := GContainerStatus{Name: "web", Image: "nginx:1.25", State: "Running", Ready: true, RestartCount: 1}
+	proxy := GContainerStatus{Name: "proxy", Image: "envoy:1.29", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 12, LastTerminationReason: "OOMKilled"}
+	starting := GContainerStatus{Name: "web", Image: "nginx:1.25", State: "Waiting", Reason: "ContainerCreating"}
+	sidecar := GContainerStatus{Name: "istio-proxy", Image: "proxyv2", State: "Running", RestartPolicy: "Always", Started: true}
+	checkTests(t, []Test{
+		{containerState(&migrate), Succeeded},
+		{containerState(&seed), Loading},
+		{containerState(&warmup), Loading},
+		{containerState(&web), Running},
+		{containerState(&proxy), Failed},
+		{containerState(&starting), Loading},
+		{containerState(&GContainerStatus{State: "Terminated", Reason: "Error"}), Failed},
+		{containerLabel(&migrate, 0, 3), "init 1/3 migrate (flyway:10): Completed"},
+		{containerLabel(&seed, 1, 3), "init 2/3 seed (busybox): Running"},
+		{containerLabel(&warmup, 2, 3), "init 3/3 warmup (busybox): Pending"},
+		{containerLabel(&web, 0, 0), "web (nginx:1.25): Running, 1 restart"},
+		{containerLabel(&proxy, 0, 0), "proxy (envoy:1.29): CrashLoopBackOff, 12 restarts, last OOMKilled"},
+		{containerLabel(&GContainerStatus{Name: "web", Image: "nginx", State: "Running"}, 0, 0), "web (nginx): Running, not ready"},
+		// init containers run in order, so progress stops at the first that has not completed
+		{initProgress([]GContainerStatus{migrate, seed, warmup}), 1},
+		{initProgress([]GContainerStatus{migrate, migrate}), 2},
+		{initProgress([]GContainerStatus{}), 0},
+		// native sidecars never complete, but count as done once started
+		{initProgress([]GContainerStatus{migrate, sidecar, seed}), 2},
+		{initProgress([]GContainerStatus{migrate, {Name: "istio-proxy", State: "Running", RestartPolicy: "Always"}, seed}), 1},
+	})
+}
+
+func Test_PodLifecycle(t *testing.T) {
+	gc := createTestCluster()
+	gp := &GPod{name: "web-1", namespace: "a", parent: gc, object: &scene.SceneObject{}, status: &GPodStatus{}}
+
+	gp.SetStatus(&GPodStatus{Lifecycle: PodNotReady, Phase: "Running"})
+	checkTests(t, []Test{
+		{gp.state, Running},
+		{gp.object.Spinning, true},
+		{gp.object.IsPulsing, true},
+		{gp.object.PulseColor, *podLifecycleVisuals[PodNotReady].pulse},
+	})
+
+	// a crash-looping pod stops spinning and pulses in the color of its new lifecycle
+	crashing := &GPodStatus{Lifecycle: PodCrashLoopBackOff, Phase: "Running", Containers: []GContainerStatus{{Name: "web", RestartCount: 12}}}
+	gp.SetStatus(crashing)
+	checkTests(t, []Test{
+		{gp.state, Failed},
+		{gp.object.Spinning, false},
+		{gp.object.Color, podLifecycleVisuals[PodCrashLoopBackOff].color},
+		{gp.object.PulseColor, *podLifecycleVisuals[PodCrashLoopBackOff].pulse},
+		{podLabel(gp.name, crashing), "web-1 (CrashLoopBackOff, 12 restarts)"},
+	})
+
+	gp.SetStatus(&GPodStatus{Lifecycle: PodPending})
+	checkTests(t, []Test{
+		{gp.state, Loading},
+		{gp.object.IsPulsing, false},
+		{gp.object.Wireframe, true},
+	})
+
+	initializing := &GPodStatus{Lifecycle: PodContainerCreating, InitContainers: []GContainerStatus{{State: "Terminated", Reason: "Completed"}, {State: "Running"}, {}}}
+	checkTests(t, []Test{
+		{podLabel("web-1", initializing), "web-1 (Init:1/3)"},
+		{podLabel("web-1", &GPodStatus{Phase: "Succeeded"}), "web-1 (Succeeded)"},
+		{podLabel("web-1", &GPodStatus{}), "web-1 (Pending)"},
+	})
+
+	// every lifecycle is told apart by the way it is drawn
+	drawn := map[string]GPodLifecycle{}
+	for lifecycle, visual := range podLifecycleVisuals {
+		way := fmt.Sprintf("%v %v %v %v", visual.color, visual.pulse != nil, visual.spinning, visual.wireframe)
+		if other, found := drawn[way]; found {
+			t.Errorf("Error: %s is drawn like %s\n", lifecycle, other)
+		}
+		drawn[way] = lifecycle
+	}
+}
+
+func Test_DeploymentRollout(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	owner := func(resource GResource, name string) []GSignatureConnection {
+		return []GSignatureConnection{{resource: resource, name: name, namespace: ns}}
+	}
+	deploy := &GDeployment{name: "web", namespace: ns, parent: gc, object: &scene.SceneObject{}, currentOffset: &mgl.Vec3{}}
+	deploy.SetStatus(&GDeploymentStatus{Replicas: 4, DesiredReplicas: 3, UpdatedReplicas: 1, ReadyReplicas: 3, AvailableReplicas: 3, Revision: 2})
+	gc.gobjects = append(gc.gobjects, deploy)
+	gc.CreateAndReserveSlot("web", ns, deploy, GDEPLOYMENT, nil)
+	replicaSets := map[string]*GReplicaSet{}
+	// the pods of the old revision are listed first, as they are when the rollout starts
+	for _, revision := range []int64{1, 2} {
+		name := fmt.Sprintf("web-%d", revision)
+		grs := &GReplicaSet{name: name, namespace: ns, parent: gc, object: &scene.SceneObject{}, currentOffset: &mgl.Vec3{}}
+		grs.SetStatus(&GReplicaSetStatus{Revision: revision, OwnerReferenceName: "web", OwnerReferenceType: "Deployment"})
+		replicaSets[name] = grs
+		gc.gobjects = append(gc.gobjects, grs)
+		gc.CreateAndReserveOrdinalSlot(name, ns, grs, GREPLICASET, owner(GDEPLOYMENT, "web"), revisionOrdinal(revision))
+		gc.refreshRollout(ns, "web")
+
+		podStatus := &GPodStatus{OwnerReferenceName: name, OwnerReferenceType: "ReplicaSet"}
+		gp := &GPod{name: name + "-a", namespace: ns, parent: gc, status: podStatus, currentOffset: &mgl.Vec3{}}
+		gc.gobjects = append(gc.gobjects, gp)
+		gc.CreateAndReserveOrdinalSlot(gp.name, ns, gp, GPOD, owner(GREPLICASET, name), gc.podOrdinal(ns, podStatus))
+	}
+	checkTests(t, []Test{
+		{slotRowNames(gc.slots[ns][0]), []string{"web", "web-2", "web-1", "web-2-a", "web-1-a"}},
+		{replicaSets["web-1"].superseded, true},
+		{replicaSets["web-1"].object.Wireframe, true},
+		{replicaSets["web-1"].object.Color, replicaSetSupersededColor},
+		{replicaSets["web-2"].superseded, false},
+		{deploy.state, Loading},
+		{deploymentLabel("web", deploy.status), "web rev 2: 3 desired, 1 updated, 3 ready, 3 available"},
+	})
+
+	// rolling back re-annotates the old replicaset with the next revision, which moves it and its pods to the front
+	replicaSets["web-1"].SetStatus(&GReplicaSetStatus{Revision: 3, OwnerReferenceName: "web", OwnerReferenceType: "Deployment"})
+	gc.refreshRollout(ns, "web")
+	checkTests(t, []Test{
+		{slotRowNames(gc.slots[ns][0]), []string{"web", "web-1", "web-2", "web-1-a", "web-2-a"}},
+		{replicaSets["web-1"].superseded, false},
+		{replicaSets["web-1"].object.Color, replicaSetColor},
+		{replicaSets["web-2"].superseded, true},
+		// slots already in order are not moved again, so the layout is not reapplied
+		{gc.setSlotOrdinal(SlotResource{name: "web-1-a", namespace: ns, resource: GPOD}, revisionOrdinal(3)), false},
+		{gc.setSlotOrdinal(SlotResource{name: "web-1-a", namespace: ns, resource: GPOD}, revisionOrdinal(1)), true},
+	})
+
+	// a stalled rollout is flagged in red and pulses
+	deploy.SetStatus(&GDeploymentStatus{Replicas: 4, DesiredReplicas: 3, UpdatedReplicas: 1, ReadyReplicas: 3, AvailableReplicas: 3, Revision: 3, Stalled: true})
+	color, pulsing := deploy.pulseColor()
+	checkTests(t, []Test{
+		{deploy.state, Failed},
+		{deploy.object.Color, deploymentStalledColor},
+		{color, deploymentStalledColor},
+		{pulsing, true},
+		{deploymentLabel("web", deploy.status), "web rev 3: 3 desired, 1 updated, 3 ready, 3 available, ProgressDeadlineExceeded"},
+	})
+}
+
+func Test_HorizontalPodAutoscaler(t *testing.T) {
+	gc := createTestCluster()
+	ns := "test-namespace"
+	reserveTestSlot(gc, GDEPLOYMENT, "web", ns, nil)
+	reserveTestSlot(gc, GDEPLOYMENT, "api", ns, nil)
+	hpa := &GHorizontalPodAutoscaler{name: "web", namespace: ns, parent: gc, object: &scene.SceneObject{}, status: &GHorizontalPodAutoscalerStatus{}, currentOffset: &mgl.Vec3{}}
+	status := func(current, desired int32, cpu string, scaledAt time.Time) *GHorizontalPodAutoscalerStatus {
+		return &GHorizontalPodAutoscalerStatus{
+			ScaleTargetName: "web", ScaleTargetType: "Deployment", CurrentReplicas: current, DesiredReplicas: desired, MinReplicas: 2, MaxReplicas: 10,
+			Metrics: []GAutoscalerMetric{{Name: "cpu", Current: cpu, Target: "70%"}}, LastScaleTime: scaledAt,
+		}
+	}
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	hpa.SetStatus(status(3, 3, "60%", start))
+	gc.gobjects = append(gc.gobjects, hpa)
+	gc.CreateAndReserveSlot("web", ns, hpa, GHORIZONTALPODAUTOSCALER, horizontalPodAutoscalerSignatureConnections(ns, hpa.status))
+	target, found := hpa.scaleTarget()
+	checkTests(t, []Test{
+		{slotRowNames(gc.slots[ns][0]), []string{"web", "web"}},
+		{[]GResource{gc.slots[ns][0][0].resource, gc.slots[ns][0][1].resource}, []GResource{GHORIZONTALPODAUTOSCALER, GDEPLOYMENT}}, // leading the row of the workload it scales,
+		{target, SlotResource{name: "web", namespace: ns, resource: GDEPLOYMENT}},
+		{found, true},
+		{hpa.state, Running},
+		{len(hpa.history), 0}, // the replicas it was first seen with are not a scale
+	})
+
+	// scaling up and back down is remembered, newest first in the details
+	hpa.SetStatus(status(3, 5, "110%", start.Add(time.Minute)))
+	hpa.SetStatus(status(5, 5, "75%", start.Add(time.Minute)))
+	hpa.SetStatus(status(5, 4, "40%", start.Add(6*time.Minute)))
+	details := hpa.GetDetails()
+	checkTests(t, []Test{
+		{hpa.history, []GScaleEvent{
+			{Time: start.Add(time.Minute), From: 3, To: 5, Metrics: "cpu 110%/70%"},
+			{Time: start.Add(6 * time.Minute), From: 5, To: 4, Metrics: "cpu 40%/70%"},
+		}},
+		{scaleColor(hpa.history[0]), hpaScaleUpColor},
+		{scaleColor(hpa.history[1]), hpaScaleDownColor},
+		{hpa.state, Loading},
+		{hpaLabel("web", hpa.status), "web: 5 replicas, desired 4 (2-10), cpu 40%/70%"},
+		{details[:2], []string{"scales Deployment web: 5 replicas, desired 4, min 2, max 10", "metric cpu 40%/70%"}},
+		{len(details), 4},
+		{details[2][len(details[2])-len("scaled down 5 -> 4 (cpu 40%/70%)"):], "scaled down 5 -> 4 (cpu 40%/70%)"},
+	})
+
+	// only the latest scale events are kept
+	for desired := int32(6); desired <= 15; desired++ {
+		hpa.SetStatus(status(desired-1, desired, "90%", start.Add(time.Duration(desired)*time.Hour)))
+	}
+	checkTests(t, []Test{
+		{len(hpa.history), hpaHistoryLength},
+		{hpa.history[0].From, int32(7)},
+		{hpa.history[hpaHistoryLength-1].To, int32(15)},
+	})
+
+	// pinned at maxReplicas it pulses amber, and red once it cannot read its metrics
+	limited := status(10, 10, "140%", start.Add(20*time.Hour))
+	limited.Limited = "TooManyReplicas"
+	hpa.SetStatus(limited)
+	limitedColor, limitedPulsing := hpa.pulseColor()
+	inactive := status(10, 10, "", start.Add(20*time.Hour))
+	inactive.Inactive = "FailedGetResourceMetric"
+	hpa.SetStatus(inactive)
+	inactiveColor, inactivePulsing := hpa.pulseColor()
+	checkTests(t, []Test{
+		{limitedColor, hpaLimitedColor},
+		{limitedPulsing, true},
+		{inactiveColor, hpaInactiveColor},
+		{inactivePulsing, true},
+		{hpa.state, Failed},
+		{hpa.object.Color, hpaInactiveColor},
+		{hpaLabel("web", inactive), "web: 10 replicas, desired 10 (2-10), cpu <unknown>/70%, FailedGetResourceMetric"},
+	})
+}
@@ -1,27 +1,306 @@
 package gkube
 
+import "time"
+
 type GNamespaceObjectFrameStatus struct{}
 
+type GClusterObjectFrameStatus struct{}
+
 type GDeploymentStatus struct {
-	ReadyReplicas int32
-	Replicas      int32
+	ReadyReplicas     int32
+	Replicas          int32
+	DesiredReplicas   int32 // spec.replicas, Replicas counts the pods of every revision while rolling out
+	UpdatedReplicas   int32
+	AvailableReplicas int32
+	Revision          int64        // deployment.kubernetes.io/revision, shared with its newest ReplicaSet
+	Stalled           bool         // the rollout exceeded its progress deadline
+	CustomOwner       GCustomOwner // the custom resource owning it, i.e. an operator's CR
 }
 
 type GReplicaSetStatus struct {
 	ReadyReplicas      int32
 	Replicas           int32
+	Revision           int64 // deployment.kubernetes.io/revision, 0 when not rolled out by a Deployment
 	OwnerReferenceName string
 	OwnerReferenceType string
 }
 
+type GStatefulSetStatus struct {
+	ReadyReplicas int32
+	Replicas      int32
+	CustomOwner   GCustomOwner
+	Claims        []string // generated from its volumeClaimTemplates for each ordinal, named <template>-<statefulset>-<ordinal>
+}
+
+// GPodLifecycle is what a pod is going through, derived from its phase, conditions and container statuses like the STATUS
+// column of kubectl get pods
+type GPodLifecycle string
+
+const (
+	PodPending           GPodLifecycle = "Pending"           // not scheduled yet
+	PodContainerCreating GPodLifecycle = "ContainerCreating" // scheduled, its containers are pulled, initialized and started
+	PodRunning           GPodLifecycle = "Running"           // running and ready
+	PodNotReady          GPodLifecycle = "NotReady"          // running, but failing its readiness probes
+	PodCrashLoopBackOff  GPodLifecycle = "CrashLoopBackOff"
+	PodImagePullBackOff  GPodLifecycle = "ImagePullBackOff" // also for ErrImagePull and InvalidImageName
+	PodOOMKilled         GPodLifecycle = "OOMKilled"        // a container was killed for running out of memory, and may be backing off
+	PodTerminating       GPodLifecycle = "Terminating"
+	PodSucceeded         GPodLifecycle = "Succeeded"
+	PodFailed            GPodLifecycle = "Failed"
+	PodUnknown           GPodLifecycle = "Unknown" // the node of the pod is lost or stopped reporting
+)
+
 type GPodStatus struct {
-	Up                 bool
-	Index              int32
-	OwnerReferenceName string
-	OwnerReferenceType string
+	Up                     bool
+	Phase                  string
+	Lifecycle              GPodLifecycle
+	Index                  int32
+	OwnerReferenceName     string
+	OwnerReferenceType     string
+	PersistentVolumeClaims []string
+	ConfigMaps             []string // consumed through volumes, projected volumes, envFrom or env.valueFrom
+	Secrets                []string // consumed like ConfigMaps, or as image pull secrets
+	ServiceAccountName     string
+	NodeName               string             // empty until the pod is scheduled
+	InitContainers         []GContainerStatus // in the order they run
+	Containers             []GContainerStatus
+	CustomOwner            GCustomOwner
+	Requests               GResourceUsage // summed over the containers
+	Limits                 GResourceUsage // summed over the containers, zero unless every container is limited
+}
+
+// GContainerStatus is a container or init container of a pod, drawn beside the pod while it is expanded
+type GContainerStatus struct {
+	Name                  string
+	Image                 string
+	State                 string // Waiting, Running or Terminated, empty until the kubelet reports the container
+	Reason                string // of a Waiting or Terminated state, i.e. CrashLoopBackOff or Completed
+	Ready                 bool
+	RestartCount          int32
+	LastTerminationReason string // why the previous run ended, i.e. OOMKilled or Error
+	Limits                GResourceUsage
+	RestartPolicy         string // Always for the native sidecars among init containers, empty otherwise
+	Started               bool   // whether the kubelet reports the container as started, past its startup probe
+}
+
+type GConfigMapStatus struct {
+	Keys        int
+	CustomOwner GCustomOwner
+}
+
+type GSecretStatus struct {
+	Type        string
+	Keys        int
+	CustomOwner GCustomOwner
+}
+
+type GServiceAccountStatus struct {
+	ImagePullSecrets []string
+}
+
+type GPersistentVolumeClaimStatus struct {
+	Phase            string
+	VolumeName       string
+	Index            int32
+	StorageClassName string
+	Capacity         string // the bound capacity, or the requested storage while pending
+	AccessModes      []string
+	CustomOwner      GCustomOwner
+}
+
+type GPersistentVolumeStatus struct {
+	Phase            string
+	ClaimName        string
+	ClaimNamespace   string
+	StorageClassName string
+	Capacity         string
+	AccessModes      []string
+	ReclaimPolicy    string
+}
+
+type GStorageClassStatus struct {
+	Provisioner       string
+	ReclaimPolicy     string
+	VolumeBindingMode string
+	IsDefault         bool
 }
 
 type GWireStatus struct {
 	Up    bool
 	Index int32
 }
+
+type GServiceEndpoint struct {
+	PodName string
+	Ready   bool
+}
+
+type GServiceStatus struct {
+	Type        string
+	Selector    map[string]string
+	Endpoints   []GServiceEndpoint
+	CustomOwner GCustomOwner
+}
+
+type GIngressRoute struct {
+	Host        string
+	Path        string
+	ServiceName string
+	ServicePort string
+	TLSSecret   string
+}
+
+type GIngressStatus struct {
+	ClassName string
+	Routes    []GIngressRoute
+}
+
+type GPolicyRule struct {
+	Verbs           []string
+	APIGroups       []string
+	Resources       []string
+	ResourceNames   []string
+	NonResourceURLs []string
+}
+
+type GRoleStatus struct {
+	Rules []GPolicyRule
+}
+
+type GClusterRoleStatus struct {
+	Rules []GPolicyRule
+}
+
+// GRoleSubject is a subject of a binding; Kind is one of ServiceAccount, User or Group
+type GRoleSubject struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+type GRoleBindingStatus struct {
+	RoleKind string // Role or ClusterRole
+	RoleName string
+	Subjects []GRoleSubject
+}
+
+type GClusterRoleBindingStatus struct {
+	RoleName string
+	Subjects []GRoleSubject
+}
+
+type GNodeStatus struct {
+	Ready         bool
+	Unschedulable bool     // cordoned
+	Pressures     []string // conditions other than Ready that are True, i.e. MemoryPressure or DiskPressure
+	Allocatable   GResourceUsage
+}
+
+type GNodeCoverageState string
+
+const (
+	NodeCoverageHealthy   GNodeCoverageState = "Healthy"   // node runs a ready daemon pod
+	NodeCoverageUnhealthy GNodeCoverageState = "Unhealthy" // node runs a daemon pod that is not ready
+	NodeCoverageMissing   GNodeCoverageState = "Missing"   // node should run a daemon pod but does not
+	NodeCoverageExcluded  GNodeCoverageState = "Excluded"  // node is excluded by node selectors, affinity or taints
+)
+
+type GNodeCoverage struct {
+	NodeName string
+	State    GNodeCoverageState
+}
+
+type GDaemonSetStatus struct {
+	DesiredNumberScheduled int32
+	NumberReady            int32
+	Coverage               []GNodeCoverage
+	CustomOwner            GCustomOwner
+}
+
+type GJobStatus struct {
+	Completions        int32
+	Parallelism        int32
+	Active             int32
+	Succeeded          int32
+	Failed             int32
+	Condition          string // Complete, Failed or Suspended once the job has reached it
+	OwnerReferenceName string
+	OwnerReferenceType string
+	CustomOwner        GCustomOwner
+}
+
+type GCronJobStatus struct {
+	Schedule string
+	TimeZone string
+	Suspend  bool
+	Active   int32
+}
+
+// GAutoscalerMetric is a metric a HorizontalPodAutoscaler scales on, with its reading and target as kubectl prints them
+type GAutoscalerMetric struct {
+	Name    string  // i.e. cpu, or http_requests on Ingress/web
+	Current string  // i.e. 82%, empty until the metric is read
+	Target  string  // i.e. 70%
+	Ratio   float64 // current over target, the autoscaler scales up above 1 and down below it
+}
+
+type GHorizontalPodAutoscalerStatus struct {
+	ScaleTargetName   string
+	ScaleTargetType   string       // kind of the scaleTargetRef, i.e. Deployment
+	CustomScaleTarget GCustomOwner // when the scaleTargetRef is a custom resource
+	CurrentReplicas   int32
+	DesiredReplicas   int32
+	MinReplicas       int32
+	MaxReplicas       int32
+	Metrics           []GAutoscalerMetric
+	LastScaleTime     time.Time // zero until it scaled
+	Limited           string    // reason of the ScalingLimited condition while it holds, i.e. TooManyReplicas
+	Inactive          string    // reason it is unable to scale or to read its metrics, i.e. FailedGetResourceMetric
+}
+
+// GCustomOwner is the watched custom resource owning an object, i.e. an operator's CR, named kind.group/name. Namespace
+// is that of the owner, which is CLUSTER_SCOPED_NAMESPACE for cluster-scoped custom resources. Name is empty when the
+// object has no custom owner.
+type GCustomOwner struct {
+	Name      string
+	Namespace string
+}
+
+// GCustomResourceStatus is the generic status of a resource watched through the dynamic client. Custom resources are
+// named kind.group/name, i.e. kafka.kafka.strimzi.io/my-cluster, which is also how other resources refer to them
+// through CustomOwner.
+type GCustomResourceStatus struct {
+	APIVersion         string
+	Kind               string
+	Phase              string // status.phase, if the resource reports one
+	Ready              string // status of the Ready condition, if the resource reports one
+	OwnerReferenceName string
+	OwnerReferenceType string
+	CustomOwner        GCustomOwner
+}
+
+// GResourceUsage is an amount of CPU in millicores and of memory in bytes
+type GResourceUsage struct {
+	CPU    int64
+	Memory int64
+}
+
+// GResourceUsageStatus is a poll of metrics.k8s.io, attached to the GOBJECTs of the pods and nodes it reports on. Usage
+// is not drawn on its own.
+type GResourceUsageStatus struct {
+	Pods       map[string]GResourceUsage            // by namespace/name
+	Containers map[string]map[string]GResourceUsage // of each pod by namespace/name, then by container name
+	Nodes      map[string]GResourceUsage            // by name
+}
+
+// GKubeEventStatus is a core/v1 Event, attached to the GOBJECT of its involved object. Events are not drawn on their own.
+type GKubeEventStatus struct {
+	InvolvedResource  GResource
+	InvolvedName      string
+	InvolvedNamespace string
+	Type              string // Normal or Warning
+	Reason            string // i.e. FailedScheduling, BackOff or FailedMount
+	Message           string
+	Count             int32
+	LastTimestamp     time.Time
+}
@@ -11,9 +11,11 @@ import (
 )
 
 type GClusterRole struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GClusterRoleStatus
 
 	name      string
 	namespace string
@@ -26,6 +28,7 @@ func (gd *GClusterRole) Create(parent *GCluster, name string, namespace string,
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GClusterRoleStatus{}
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
@@ -45,6 +48,23 @@ func (gd *GClusterRole) GetResource() GResource {
 	return GCLUSTERROLE
 }
 
+func (gd *GClusterRole) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GClusterRole) SetStatus(status *GClusterRoleStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Heptagon); ok {
+		obj.SetText(fmt.Sprintf("%s (%d rules)", gd.name, len(status.Rules)))
+	}
+}
+
+// GetDetails summarizes the rules of the role
+func (gd *GClusterRole) GetDetails() []string {
+	return SummarizePermissions(scopePolicyRules(gd.status.Rules, CLUSTER_SCOPED_NAMESPACE))
+}
+
 func (gd *GClusterRole) Delete() {
 }
 
@@ -65,4 +85,5 @@ func (gd *GClusterRole) OnClick() {
 }
 
 func (gd *GClusterRole) SetDeleting() {
+	gd.object.IsDeleting = true
 }
@@ -11,9 +11,14 @@ import (
 )
 
 type GClusterRoleBinding struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GClusterRoleBindingStatus
+	links     map[string]*GLink
+	subjects  []*scene.SceneObject // markers of User and Group subjects
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -25,6 +30,9 @@ func (gd *GClusterRoleBinding) Create(parent *GCluster, name string, namespace s
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GClusterRoleBindingStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 	gpod := &entity.Cube{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
@@ -44,8 +52,32 @@ func (gd *GClusterRoleBinding) GetResource() GResource {
 	return GCLUSTERROLEBINDING
 }
 
-func (gd *GClusterRoleBinding) Delete() {
+func (gd *GClusterRoleBinding) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the label and redraws the markers of the User and Group subjects
+func (gd *GClusterRoleBinding) SetStatus(status *GClusterRoleBindingStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Cube); ok {
+		obj.SetText(fmt.Sprintf("%s -> ClusterRole/%s", gd.name, status.RoleName))
+	}
+	gd.parent.deleteSubjectMarkers(gd.subjects)
+	gd.subjects = gd.parent.createSubjectMarkers(status.Subjects, gd.shaderID, gd.OnClick)
+}
 
+// UpdateLinks wires the binding to its role and service account subjects, and keeps the other subjects above it
+func (gd *GClusterRoleBinding) UpdateLinks() {
+	role := SlotResource{name: gd.status.RoleName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GCLUSTERROLE}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, bindingLinkTargets(role, gd.status.Subjects, CLUSTER_SCOPED_NAMESPACE))
+	placeSubjectMarkers(gd, gd.subjects)
+}
+
+// removes the links and subject markers from the main scene
+func (gd *GClusterRoleBinding) Delete() {
+	deleteGLinks(gd.links)
+	gd.parent.deleteSubjectMarkers(gd.subjects)
 }
 
 func (gd *GClusterRoleBinding) GetCurrentOffset() *mgl.Vec3 {
@@ -65,5 +97,11 @@ func (gd *GClusterRoleBinding) OnClick() {
 }
 
 func (gd *GClusterRoleBinding) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
+	for _, subject := range gd.subjects {
+		subject.IsDeleting = true
+	}
 }
@@ -0,0 +1,51 @@
+package gkube
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// the space left between the rows of clusters placed side by side
+const clusterGap = 4
+
+// SetOrigin moves every slotted GOBJECT of the cluster along with its origin
+func (gc *GCluster) SetOrigin(origin mgl.Vec3) {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	if gc.origin == origin {
+		return
+	}
+	gc.origin = origin
+	gc.resyncAllSlotOffsets()
+	gc.applyLayout()
+}
+
+// rowCount is the number of rows the cluster spans along x, which is the longest namespace or the node layer
+func (gc *GCluster) rowCount() int {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	rows := 0
+	for _, slotRows := range gc.slots {
+		rows = max(rows, len(slotRows))
+	}
+	if gc.layout == GLAYOUT_NODE {
+		nodes := 0
+		for _, gob := range gc.gobjects {
+			if gob.GetResource() == GNODE {
+				nodes++
+			}
+		}
+		rows = max(rows, nodes+1) // the unscheduled pods are grouped after the nodes
+	}
+	return rows
+}
+
+// ArrangeClusters places clusters that share a scene side by side along x, in order, so that their frames do not
+// overlap. It is called from the main loop, as clusters grow and shrink while they are watched.
+func ArrangeClusters(clusters []*GCluster) {
+	stride := float32(6.0)
+	x := float32(0)
+	for _, gc := range clusters {
+		gc.SetOrigin(mgl.Vec3{x, 0, 0})
+		x += float32(gc.rowCount()+clusterGap) * stride
+	}
+}
@@ -11,26 +11,31 @@ import (
 )
 
 type GConfigMap struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GConfigMapStatus
 
 	name          string
 	namespace     string
 	currentOffset *mgl.Vec3
 }
 
+var configMapColor = mgl.Vec3{float32(92) / 255, float32(184) / 255, float32(160) / 255}
+
 func (gd *GConfigMap) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
 	gd.name = name
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GConfigMapStatus{}
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
 	tOffset := mgl.Vec3{offset.X(), offset.Y() + 0.25, offset.Z()}
 	t.Init(&tOffset, &mgl.Vec3{1.6, 0.5, 1}, nil, true)
-	gd.object.Init(gpod, t, shaderID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gpod, t, shaderID, configMapColor, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -43,6 +48,18 @@ func (gd *GConfigMap) GetResource() GResource {
 	return GCONFIGMAP
 }
 
+func (gd *GConfigMap) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GConfigMap) SetStatus(status *GConfigMapStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Heptagon); ok {
+		obj.SetText(fmt.Sprintf("%s (%d keys)", gd.name, status.Keys))
+	}
+}
+
 func (gd *GConfigMap) Delete() {
 
 }
@@ -59,10 +76,15 @@ func (gd *GConfigMap) GetIdentifier() (string, string) {
 	return gd.name, gd.namespace
 }
 
+// OnClick selects the ConfigMap and highlights every pod consuming it. Click handlers run before the scene toggles
+// the selection, so a ConfigMap that is already selected is being deselected.
 func (gd *GConfigMap) OnClick() {
 	gd.parent.SetSelected(gd)
+	if !gd.object.OnClick {
+		gd.parent.SetHighlighted(gd.parent.getConsumingPods(GCONFIGMAP, gd.name, gd.namespace))
+	}
 }
 
 func (gd *GConfigMap) SetDeleting() {
-
+	gd.object.IsDeleting = true
 }
@@ -0,0 +1,174 @@
+package gkube
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+const (
+	containerSize    = float32(0.6)
+	containerSpacing = float32(1.0) // containers are stacked above their pod, init containers first
+)
+
+// reasons a container waits with while it is still being started, every other waiting reason is a failure
+var containerStartingReasons = []string{"", "ContainerCreating", "PodInitializing"}
+
+// GContainer is a container or init container drawn above its pod while the pod is expanded. Containers are neither
+// slotted nor GOBJECTs of their own, clicking one selects its pod.
+type GContainer struct {
+	object *scene.SceneObject
+	status GContainerStatus
+	init   bool
+}
+
+// containerState maps the status of a container onto the State drawn by the scene. Init containers that completed are
+// drawn as Succeeded, containers that crash, fail to pull or exit with an error as Failed.
+func containerState(status *GContainerStatus) State {
+	switch status.State {
+	case "Running":
+		if status.Ready {
+			return Running
+		}
+	case "Terminated":
+		if status.Reason == "Completed" {
+			return Succeeded
+		}
+		return Failed
+	case "Waiting":
+		if !slices.Contains(containerStartingReasons, status.Reason) {
+			return Failed
+		}
+	}
+	return Loading
+}
+
+// containerLabel describes the container on a single line, i.e. "init 2/3 migrate (flyway:10): Running" or
+// "proxy (envoy:1.29): CrashLoopBackOff, 12 restarts, last OOMKilled". Init containers are numbered in the order they run.
+func containerLabel(status *GContainerStatus, initIndex, initCount int) string {
+	label := fmt.Sprintf("%s (%s)", status.Name, status.Image)
+	if initCount > 0 {
+		label = fmt.Sprintf("init %d/%d %s", initIndex+1, initCount, label)
+	}
+	state := []string{}
+	if len(status.Reason) > 0 {
+		state = append(state, status.Reason)
+	} else if status.State == "Running" && !status.Ready && initCount == 0 {
+		state = append(state, "Running, not ready")
+	} else if len(status.State) > 0 {
+		state = append(state, status.State)
+	} else {
+		state = append(state, "Pending")
+	}
+	if status.RestartCount == 1 {
+		state = append(state, "1 restart")
+	} else if status.RestartCount > 1 {
+		state = append(state, fmt.Sprintf("%d restarts", status.RestartCount))
+	}
+	if len(status.LastTerminationReason) > 0 {
+		state = append(state, "last "+status.LastTerminationReason)
+	}
+	return fmt.Sprintf("%s: %s", label, strings.Join(state, ", "))
+}
+
+// SetStatus refreshes the color and label of the container. Init containers that have not started yet are drawn as
+// wireframes.
+func (gd *GContainer) SetStatus(status GContainerStatus, initIndex, initCount int) {
+	gd.status = status
+	gd.object.Color = podStateColors[containerState(&status)]
+	gd.object.Wireframe = gd.init && len(status.State) == 0
+	if obj, ok := gd.object.Object.(*entity.Cube); ok {
+		obj.SetText(containerLabel(&status, initIndex, initCount))
+	}
+}
+
+// initProgress counts the init containers that completed, which run one after another. Native sidecars keep running
+// alongside the containers, so like kubectl they count as done once started.
+func initProgress(initContainers []GContainerStatus) int {
+	completed := 0
+	for _, status := range initContainers {
+		sidecarStarted := status.RestartPolicy == "Always" && status.Started
+		if containerState(&status) != Succeeded && !sidecarStarted {
+			break
+		}
+		completed++
+	}
+	return completed
+}
+
+// podContainerStatuses lists the init containers of the pod followed by its containers, as they are drawn
+func podContainerStatuses(status *GPodStatus) []GContainerStatus {
+	return slices.Concat(status.InitContainers, status.Containers)
+}
+
+// expanded reports whether the containers of the pod are drawn, which they are for every pod while the cluster expands
+// containers and for the selected pod
+// pre-condition: already has lock on gobjects
+func (gd *GPod) expanded() bool {
+	return gd.parent.expandContainers || gd.parent.currentObject == gd
+}
+
+// refreshContainers draws a child for every container of the pod while it is expanded, and removes them otherwise
+func (gd *GPod) refreshContainers() {
+	statuses := podContainerStatuses(gd.status)
+	gd.containersShown = gd.expanded()
+	if !gd.containersShown {
+		statuses = nil
+	}
+	if len(gd.containers) != len(statuses) {
+		gd.deleteContainers()
+		for i := range statuses {
+			cube := &entity.Cube{}
+			cube.Init(gd.parent.font, "")
+			t := &camera.Transform3D{}
+			t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{containerSize, containerSize, containerSize}, nil, false)
+			object := &scene.SceneObject{}
+			object.Init(cube, t, gd.shaderID, podStateColors[Loading], mgl.Vec3{1, 1, 1})
+			object.AddOnClickHandler(gd.OnClick)
+			gd.parent.mainScene.AddObject(object)
+			gd.containers = append(gd.containers, &GContainer{object: object, init: i < len(gd.status.InitContainers)})
+		}
+	}
+	initCount := len(gd.status.InitContainers)
+	for i, container := range gd.containers {
+		if container.init {
+			container.SetStatus(statuses[i], i, initCount)
+		} else {
+			container.SetStatus(statuses[i], 0, 0)
+		}
+	}
+}
+
+// placeContainers stacks the containers above the pod
+func (gd *GPod) placeContainers() {
+	origin := glinkEndpoint(gd)
+	for i, container := range gd.containers {
+		position := mgl.Vec3{origin.X(), origin.Y() + 1.5 + containerSpacing*float32(i), origin.Z()}
+		*container.object.Transform.PositionAnimator.X_init = position
+		*container.object.Transform.PositionAnimator.X_final = position
+	}
+}
+
+func (gd *GPod) deleteContainers() {
+	for _, container := range gd.containers {
+		gd.parent.mainScene.DeleteObject(container.object)
+	}
+	gd.containers = nil
+}
+
+// ToggleContainers expands every pod into its containers, or collapses them again. It is bound to a key in the main loop.
+func (gc *GCluster) ToggleContainers() {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+	gc.expandContainers = !gc.expandContainers
+	for _, gob := range gc.gobjects {
+		if gp, ok := gob.(*GPod); ok {
+			gp.refreshContainers()
+		}
+	}
+}
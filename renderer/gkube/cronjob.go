@@ -2,12 +2,15 @@ package gkube
 
 import (
 	"fmt"
+	"log"
+	"time"
 
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
 	"github.com/kabicin/kubechaser/renderer/entity"
 	"github.com/kabicin/kubechaser/renderer/scene"
+	"github.com/kabicin/kubechaser/renderer/utils"
 )
 
 type CronJobConditionType string // custom type
@@ -20,10 +23,18 @@ const (
 	CronJobIdle CronJobConditionType = "Idle"
 )
 
+// number of upcoming scheduled runs drawn as ghost placeholders
+const cronJobGhostRuns = 3
+
 type GCronJob struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GCronJobStatus
+	runs      []time.Time
+	ghosts    []*scene.SceneObject
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -35,12 +46,13 @@ func (gd *GCronJob) Create(parent *GCluster, name string, namespace string, offs
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
-	gdeploymentCube := &entity.Cube{}
-	gdeploymentCube.Init(font, fmt.Sprintf("%s", name))
+	gd.status = &GCronJobStatus{}
+	gd.shaderID = shaderID
+	gcronjob := &entity.WavefrontOBJ{FileName: "cronjob.obj"}
+	gcronjob.Init(font, "")
 	t := &camera.Transform3D{}
-	tOffset := mgl.Vec3{offset.X(), offset.Y(), offset.Z()}
-	t.Init(&tOffset, &mgl.Vec3{3, 3, 3}, nil, true)
-	gd.object.Init(gdeploymentCube, t, shaderID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(gcronjob, t, shaderID, mgl.Vec3{float32(229) / 255, float32(128) / 255, float32(50) / 255}, mgl.Vec3{1, 1, 1})
 
 	gd.object.AddOnClickHandler(gd.OnClick)
 
@@ -50,12 +62,84 @@ func (gd *GCronJob) Create(parent *GCluster, name string, namespace string, offs
 	return gd.object
 }
 
+func (gd *GCronJob) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the label and the ghost placeholders of the upcoming runs
+func (gd *GCronJob) SetStatus(status *GCronJobStatus) {
+	gd.status = status
+	label := fmt.Sprintf("%s (%s)", gd.name, status.Schedule)
+	if status.Suspend {
+		gd.state = Succeeded
+		label += " suspended"
+	} else if status.Active > 0 {
+		gd.state = Running
+		label = fmt.Sprintf("%s %d active", label, status.Active)
+	} else {
+		gd.state = Loading
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(label)
+	}
+	gd.refreshGhosts(time.Now())
+}
+
+// refreshGhosts recomputes the next scheduled runs after now and draws one wireframe placeholder labelled with the time
+// of each
+func (gd *GCronJob) refreshGhosts(now time.Time) {
+	gd.deleteGhosts()
+	gd.runs = nil
+	if gd.status.Suspend {
+		return
+	}
+	runs, err := utils.NextScheduledRuns(gd.status.Schedule, gd.status.TimeZone, now, cronJobGhostRuns)
+	if err != nil {
+		log.Printf("CronJob %s/%s has an invalid schedule: %v\n", gd.namespace, gd.name, err)
+		return
+	}
+	gd.runs = runs
+	for _, run := range runs {
+		ghostCube := &entity.Cube{}
+		ghostCube.Init(gd.parent.font, run.Local().Format("Jan 2 15:04"))
+		t := &camera.Transform3D{}
+		t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{1.5, 1.5, 1.5}, nil, false)
+		ghost := &scene.SceneObject{}
+		ghost.Init(ghostCube, t, gd.shaderID, mgl.Vec3{0.6, 0.6, 0.6}, mgl.Vec3{1, 1, 1})
+		ghost.Wireframe = true
+		ghost.AddOnClickHandler(gd.OnClick)
+		gd.parent.mainScene.AddObject(ghost)
+		gd.ghosts = append(gd.ghosts, ghost)
+	}
+}
+
+func (gd *GCronJob) deleteGhosts() {
+	for _, ghost := range gd.ghosts {
+		gd.parent.mainScene.DeleteObject(ghost)
+	}
+	gd.ghosts = nil
+}
+
+// UpdateLinks queues the ghost placeholders ahead of the cronjob's slot row, and moves on once the earliest run has passed
+func (gd *GCronJob) UpdateLinks() {
+	if now := time.Now(); len(gd.runs) > 0 && now.After(gd.runs[0]) {
+		gd.refreshGhosts(now)
+	}
+	origin := glinkEndpoint(gd)
+	for i, ghost := range gd.ghosts {
+		position := mgl.Vec3{origin.X(), origin.Y(), origin.Z() - 3*float32(i+1)}
+		*ghost.Transform.PositionAnimator.X_init = position
+		*ghost.Transform.PositionAnimator.X_final = position
+	}
+}
+
 func (gd *GCronJob) GetResource() GResource {
 	return GCRONJOB
 }
 
+// removes the ghost placeholders from the main scene
 func (gd *GCronJob) Delete() {
-
+	gd.deleteGhosts()
 }
 
 func (gd *GCronJob) GetCurrentOffset() *mgl.Vec3 {
@@ -75,5 +159,8 @@ func (gd *GCronJob) OnClick() {
 }
 
 func (gd *GCronJob) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, ghost := range gd.ghosts {
+		ghost.IsDeleting = true
+	}
 }
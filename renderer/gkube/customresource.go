@@ -0,0 +1,116 @@
+package gkube
+
+import (
+	"fmt"
+
+	v41 "github.com/4ydx/gltext/v4.1"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+// GCustomResource draws any resource watched through the dynamic client with the generic CRD model
+type GCustomResource struct {
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GCustomResourceStatus
+
+	name          string
+	namespace     string
+	currentOffset *mgl.Vec3
+}
+
+func (gd *GCustomResource) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
+	gd.name = name
+	gd.namespace = namespace
+	gd.parent = parent
+	gd.object = &scene.SceneObject{}
+	gd.status = &GCustomResourceStatus{}
+
+	gcrd := &entity.WavefrontOBJ{FileName: "crd.obj"}
+	gcrd.Init(font, "")
+
+	t := &camera.Transform3D{}
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(gcrd, t, shaderID, customResourceStateColors[Loading], mgl.Vec3{1, 1, 1})
+	gd.object.AddOnClickHandler(gd.OnClick)
+
+	gd.currentOffset = offset
+
+	gd.parent.mainScene.AddObject(gd.object)
+	return gd.object
+}
+
+func (gd *GCustomResource) GetResource() GResource {
+	return GCUSTOMRESOURCE
+}
+
+func (gd *GCustomResource) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+var customResourceStateColors = map[State]mgl.Vec3{
+	Loading: {0.94901960784, 0.65098039215, 0.16470588235},
+	Running: {float32(126) / 255, float32(87) / 255, float32(194) / 255},
+	Failed:  {0.89803921568, 0.19607843137, 0.19607843137},
+}
+
+// CustomResourcePhaseToState maps the Ready condition, or failing that the phase, of a custom resource onto a State.
+// Resources that report neither are drawn as running.
+func CustomResourcePhaseToState(status *GCustomResourceStatus) State {
+	switch status.Ready {
+	case "True":
+		return Running
+	case "False":
+		return Failed
+	case "Unknown":
+		return Loading
+	}
+	switch status.Phase {
+	case "", "Running", "Ready", "Active", "Bound", "Succeeded", "Complete":
+		return Running
+	case "Failed", "Error":
+		return Failed
+	}
+	return Loading
+}
+
+func (gd *GCustomResource) SetStatus(status *GCustomResourceStatus) {
+	gd.status = status
+	gd.state = CustomResourcePhaseToState(status)
+	gd.object.Color = customResourceStateColors[gd.state]
+	label := gd.name
+	if len(status.Phase) > 0 {
+		label = fmt.Sprintf("%s (%s)", label, status.Phase)
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(label)
+	}
+}
+
+func (gd *GCustomResource) Delete() {
+
+}
+
+func (gd *GCustomResource) GetCurrentOffset() *mgl.Vec3 {
+	return gd.currentOffset
+}
+
+func (gd *GCustomResource) GetObject() *scene.SceneObject {
+	return gd.object
+}
+
+func (gd *GCustomResource) GetIdentifier() (string, string) {
+	return gd.name, gd.namespace
+}
+
+func (gd *GCustomResource) OnClick() {
+	gd.parent.SetSelected(gd)
+}
+
+func (gd *GCustomResource) SetDeleting() {
+	gd.object.IsDeleting = true
+}
@@ -1,6 +1,8 @@
 package gkube
 
 import (
+	"fmt"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -9,9 +11,13 @@ import (
 )
 
 type GDaemonSet struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GDaemonSetStatus
+	coverage  map[string]*scene.SceneObject // one marker per node, keyed by node name
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -24,6 +30,9 @@ func (gd *GDaemonSet) Create(parent *GCluster, name string, namespace string, of
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
 	gd.object.Color = mgl.Vec3{0.05, 0.05, 0.05}
+	gd.status = &GDaemonSetStatus{}
+	gd.coverage = map[string]*scene.SceneObject{}
+	gd.shaderID = shaderID
 	gdaemonset := &entity.WavefrontOBJ{FileName: "daemonset.obj"}
 	gdaemonset.Init(font, "")
 	t := &camera.Transform3D{}
@@ -37,11 +46,89 @@ func (gd *GDaemonSet) Create(parent *GCluster, name string, namespace string, of
 	return gd.object
 }
 
+func (gd *GDaemonSet) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+var nodeCoverageColors = map[GNodeCoverageState]mgl.Vec3{
+	NodeCoverageHealthy:   {0.30980392156, 0.78431372549, 0.43137254902},
+	NodeCoverageUnhealthy: {0.94901960784, 0.65098039215, 0.16470588235},
+	NodeCoverageMissing:   {0.89803921568, 0.19607843137, 0.19607843137},
+	NodeCoverageExcluded:  {0.5, 0.5, 0.5},
+}
+
+// SetStatus refreshes the ready/desired label and the coverage markers, one per node
+func (gd *GDaemonSet) SetStatus(status *GDaemonSetStatus) {
+	gd.status = status
+	if status.NumberReady == status.DesiredNumberScheduled {
+		gd.state = Running
+	} else {
+		gd.state = Loading
+	}
+	missing := 0
+	seen := map[string]bool{}
+	for _, nodeCoverage := range status.Coverage {
+		seen[nodeCoverage.NodeName] = true
+		if nodeCoverage.State == NodeCoverageMissing {
+			missing++
+		}
+		marker, found := gd.coverage[nodeCoverage.NodeName]
+		if !found {
+			markerCube := &entity.Cube{}
+			markerCube.Init(gd.parent.font, nodeCoverage.NodeName)
+			t := &camera.Transform3D{}
+			t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0.6, 0.6, 0.6}, nil, false)
+			marker = &scene.SceneObject{}
+			marker.Init(markerCube, t, gd.shaderID, nodeCoverageColors[nodeCoverage.State], mgl.Vec3{1, 1, 1})
+			marker.AddOnClickHandler(gd.OnClick)
+			gd.parent.mainScene.AddObject(marker)
+			gd.coverage[nodeCoverage.NodeName] = marker
+		}
+		marker.Color = nodeCoverageColors[nodeCoverage.State]
+		marker.Wireframe = nodeCoverage.State == NodeCoverageExcluded
+	}
+	for nodeName, marker := range gd.coverage {
+		if !seen[nodeName] {
+			gd.parent.mainScene.DeleteObject(marker)
+			delete(gd.coverage, nodeName)
+		}
+	}
+	if missing > 0 {
+		gd.state = Failed
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		label := fmt.Sprintf("%s %d/%d", gd.name, status.NumberReady, status.DesiredNumberScheduled)
+		if missing > 0 {
+			label = fmt.Sprintf("%s (%d missing)", label, missing)
+		}
+		obj.SetText(label)
+	}
+}
+
+// UpdateLinks keeps the coverage markers in a line above the daemonset while it moves between slots
+func (gd *GDaemonSet) UpdateLinks() {
+	origin := glinkEndpoint(gd)
+	spacing := float32(0.9)
+	start := origin.X() - spacing*float32(len(gd.status.Coverage)-1)/2
+	for i, nodeCoverage := range gd.status.Coverage {
+		if marker, found := gd.coverage[nodeCoverage.NodeName]; found {
+			position := mgl.Vec3{start + spacing*float32(i), origin.Y() + 2.5, origin.Z()}
+			*marker.Transform.PositionAnimator.X_init = position
+			*marker.Transform.PositionAnimator.X_final = position
+		}
+	}
+}
+
 func (gd *GDaemonSet) GetResource() GResource {
 	return GDAEMONSET
 }
 
+// removes the coverage markers from the main scene
 func (gd *GDaemonSet) Delete() {
+	for nodeName, marker := range gd.coverage {
+		gd.parent.mainScene.DeleteObject(marker)
+		delete(gd.coverage, nodeName)
+	}
 }
 
 func (gd *GDaemonSet) GetCurrentOffset() *mgl.Vec3 {
@@ -61,4 +148,8 @@ func (gd *GDaemonSet) OnClick() {
 }
 
 func (gd *GDaemonSet) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, marker := range gd.coverage {
+		marker.IsDeleting = true
+	}
 }
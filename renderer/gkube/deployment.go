@@ -1,6 +1,8 @@
 package gkube
 
 import (
+	"fmt"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -26,10 +28,20 @@ const (
 	DeploymentReplicaFailure DeploymentConditionType = "ReplicaFailure"
 )
 
+var (
+	deploymentColor        = mgl.Vec3{float32(50) / 255, float32(229) / 255, float32(148) / 255}
+	deploymentStalledColor = mgl.Vec3{0.89803921568, 0.19607843137, 0.19607843137}
+	// fills of the updated, ready and available bars of the rollout gauge, from the top
+	deploymentGaugeColors = []mgl.Vec3{{0.2, 0.6, 1}, {0.30980392156, 0.78431372549, 0.43137254902}, {0.2, 0.8, 0.8}}
+)
+
 type GDeployment struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GDeploymentStatus
+	gauge     []*scene.SceneObject // track and fill of the updated, ready and available bars
 
 	name      string
 	namespace string
@@ -42,6 +54,7 @@ func (gd *GDeployment) Create(parent *GCluster, name string, namespace string, o
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GDeploymentStatus{}
 
 	// gdeploymentCube := &entity.Cube{}
 	// gdeploymentCube.Init(font, name)
@@ -55,20 +68,100 @@ func (gd *GDeployment) Create(parent *GCluster, name string, namespace string, o
 
 	t := &camera.Transform3D{}
 	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
-	gd.object.Init(gdeploymentCube, t, shaderID, mgl.Vec3{float32(50) / 255, float32(229) / 255, float32(148) / 255}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gdeploymentCube, t, shaderID, deploymentColor, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
+	for _, color := range deploymentGaugeColors {
+		for _, barColor := range []mgl.Vec3{{0.5, 0.5, 0.5}, color} {
+			barCube := &entity.Cube{}
+			barCube.Init(font, "")
+			barTransform := &camera.Transform3D{}
+			barTransform.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0, 0, 0}, nil, false)
+			bar := &scene.SceneObject{}
+			bar.Init(barCube, barTransform, shaderID, barColor, mgl.Vec3{1, 1, 1})
+			bar.Wireframe = len(gd.gauge)%2 == 0
+			bar.AddOnClickHandler(gd.OnClick)
+			gd.parent.mainScene.AddObject(bar)
+			gd.gauge = append(gd.gauge, bar)
+		}
+	}
+
 	gd.currentOffset = offset
 
 	gd.parent.mainScene.AddObject(gd.object)
 	return gd.object
 }
 
+func (gd *GDeployment) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// deploymentLabel describes the rollout, i.e. "web rev 4: 3 desired, 2 updated, 3 ready, 3 available"
+func deploymentLabel(name string, status *GDeploymentStatus) string {
+	if status.Revision > 0 {
+		name = fmt.Sprintf("%s rev %d", name, status.Revision)
+	}
+	label := fmt.Sprintf("%s: %d desired, %d updated, %d ready, %d available", name, status.DesiredReplicas, status.UpdatedReplicas, status.ReadyReplicas, status.AvailableReplicas)
+	if status.Stalled {
+		label += ", ProgressDeadlineExceeded"
+	}
+	return label
+}
+
+// SetStatus refreshes the state, the rollout label and the gauge. A stalled rollout turns the deployment red.
+func (gd *GDeployment) SetStatus(status *GDeploymentStatus) {
+	gd.status = status
+	gd.object.Color = deploymentColor
+	if status.Stalled {
+		gd.state = Failed
+		gd.object.Color = deploymentStalledColor
+	} else if status.ReadyReplicas == status.Replicas && status.UpdatedReplicas == status.Replicas {
+		gd.state = Running
+	} else {
+		gd.state = Loading
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(deploymentLabel(gd.name, status))
+	}
+}
+
+// pulseColor is the color the deployment pulses towards while its rollout is stalled
+func (gd *GDeployment) pulseColor() (mgl.Vec3, bool) {
+	return deploymentStalledColor, gd.status.Stalled
+}
+
+// UpdateLinks keeps the rollout gauge above the deployment, its updated, ready and available bars filled by their share
+// of the desired replicas
+func (gd *GDeployment) UpdateLinks() {
+	origin := glinkEndpoint(gd)
+	width := float32(2.4)
+	counts := []int32{gd.status.UpdatedReplicas, gd.status.ReadyReplicas, gd.status.AvailableReplicas}
+	for i, count := range counts {
+		fill := float32(0)
+		if gd.status.DesiredReplicas > 0 {
+			fill = width * float32(min(count, gd.status.DesiredReplicas)) / float32(gd.status.DesiredReplicas)
+		}
+		height := origin.Y() + 2.8 - 0.5*float32(i)
+		track := mgl.Vec3{origin.X(), height, origin.Z()}
+		filled := mgl.Vec3{origin.X(), height, origin.Z() - width/2 + fill/2}
+		*gd.gauge[2*i].Transform.PositionAnimator.X_init = track
+		*gd.gauge[2*i].Transform.PositionAnimator.X_final = track
+		*gd.gauge[2*i].Transform.Scale = mgl.Vec3{0.3, 0.3, width}
+		*gd.gauge[2*i+1].Transform.PositionAnimator.X_init = filled
+		*gd.gauge[2*i+1].Transform.PositionAnimator.X_final = filled
+		*gd.gauge[2*i+1].Transform.Scale = mgl.Vec3{0.25, 0.25, fill}
+	}
+}
+
 func (gd *GDeployment) GetResource() GResource {
 	return GDEPLOYMENT
 }
 
+// removes the rollout gauge from the main scene
 func (gd *GDeployment) Delete() {
+	for _, bar := range gd.gauge {
+		gd.parent.mainScene.DeleteObject(bar)
+	}
 }
 
 func (gd *GDeployment) GetCurrentOffset() *mgl.Vec3 {
@@ -88,4 +181,8 @@ func (gd *GDeployment) OnClick() {
 }
 
 func (gd *GDeployment) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, bar := range gd.gauge {
+		bar.IsDeleting = true
+	}
 }
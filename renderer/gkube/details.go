@@ -0,0 +1,76 @@
+package gkube
+
+import (
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+// GDetailedObject is implemented by GOBJECTs that list details above themselves while selected
+type GDetailedObject interface {
+	GObject
+	// pre-condition: already has lock on gobjects
+	GetDetails() []string
+}
+
+const (
+	gdetailsHeight      = float32(3)   // height of the last line above the selected GOBJECT
+	gdetailsLineSpacing = float32(0.7) // distance between lines
+)
+
+// GDetails draws lines of text stacked above the selected GOBJECT
+type GDetails struct {
+	parent   *GCluster
+	target   GObject
+	lines    []*scene.SceneObject
+	shaderID uint32
+}
+
+func (gd *GDetails) Create(parent *GCluster, shaderID uint32) {
+	gd.parent = parent
+	gd.shaderID = shaderID
+}
+
+// Show replaces the lines and pins them above target
+func (gd *GDetails) Show(target GObject, lines []string) {
+	gd.Hide()
+	gd.target = target
+	for _, line := range lines {
+		lineCube := &entity.Cube{}
+		lineCube.Init(gd.parent.font, line)
+		t := &camera.Transform3D{}
+		t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0.05, 0.05, 0.05}, nil, false)
+		lineObject := &scene.SceneObject{}
+		lineObject.Init(lineCube, t, gd.shaderID, mgl.Vec3{0.2, 0.2, 0.2}, mgl.Vec3{1, 1, 1})
+		gd.parent.mainScene.AddObject(lineObject)
+		gd.lines = append(gd.lines, lineObject)
+	}
+	gd.Update()
+}
+
+// Hide removes every line from the main scene
+func (gd *GDetails) Hide() {
+	for _, line := range gd.lines {
+		gd.parent.mainScene.DeleteObject(line)
+	}
+	gd.lines = nil
+	gd.target = nil
+}
+
+// Update keeps the lines above the target while it moves, and hides them once the target is deleted
+func (gd *GDetails) Update() {
+	if gd.target == nil {
+		return
+	}
+	if object := gd.target.GetObject(); object != nil && object.IsDeleting {
+		gd.Hide()
+		return
+	}
+	origin := glinkEndpoint(gd.target)
+	for i, line := range gd.lines {
+		position := mgl.Vec3{origin.X(), origin.Y() + gdetailsHeight + gdetailsLineSpacing*float32(len(gd.lines)-1-i), origin.Z()}
+		*line.Transform.PositionAnimator.X_init = position
+		*line.Transform.PositionAnimator.X_final = position
+	}
+}
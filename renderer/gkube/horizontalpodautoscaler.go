@@ -0,0 +1,275 @@
+package gkube
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	v41 "github.com/4ydx/gltext/v4.1"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+const (
+	hpaHistoryLength = 8            // scale-ups and scale-downs remembered by each autoscaler
+	hpaHistoryHeight = float32(2.4) // height of a history bar at maxReplicas
+)
+
+var (
+	hpaColor          = mgl.Vec3{0.4, 0.6, 1}
+	hpaInactiveColor  = mgl.Vec3{0.89803921568, 0.19607843137, 0.19607843137}
+	hpaLimitedColor   = mgl.Vec3{0.94901960784, 0.65098039215, 0.16470588235} // pulsed while pinned at maxReplicas
+	hpaScaleUpColor   = mgl.Vec3{0.30980392156, 0.78431372549, 0.43137254902}
+	hpaScaleDownColor = mgl.Vec3{0.2, 0.8, 0.8}
+)
+
+// GScaleEvent is a scale-up or scale-down of an autoscaler observed while it was drawn
+type GScaleEvent struct {
+	Time    time.Time
+	From    int32
+	To      int32
+	Metrics string // the readings it scaled on, i.e. "cpu 92%/70%"
+}
+
+type GHorizontalPodAutoscaler struct {
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GHorizontalPodAutoscalerStatus
+	history   []GScaleEvent
+	bars      []*scene.SceneObject // one per scale event of the history, oldest first
+	links     map[string]*GLink
+	shaderID  uint32
+
+	name          string
+	namespace     string
+	currentOffset *mgl.Vec3
+}
+
+func (gd *GHorizontalPodAutoscaler) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
+	gd.name = name
+	gd.namespace = namespace
+	gd.parent = parent
+	gd.object = &scene.SceneObject{}
+	gd.status = &GHorizontalPodAutoscalerStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
+
+	ghpa := &entity.WavefrontOBJ{FileName: "hpa.obj"}
+	ghpa.Init(font, "")
+	t := &camera.Transform3D{}
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(ghpa, t, shaderID, hpaColor, mgl.Vec3{1, 1, 1})
+	gd.object.AddOnClickHandler(gd.OnClick)
+
+	gd.currentOffset = offset
+
+	gd.parent.mainScene.AddObject(gd.object)
+	return gd.object
+}
+
+func (gd *GHorizontalPodAutoscaler) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// formatAutoscalerMetrics lists the readings against the targets as kubectl does, i.e. "cpu 82%/70%, memory <unknown>/80%"
+func formatAutoscalerMetrics(metrics []GAutoscalerMetric) string {
+	readings := []string{}
+	for _, metric := range metrics {
+		current := metric.Current
+		if len(current) == 0 {
+			current = "<unknown>"
+		}
+		readings = append(readings, fmt.Sprintf("%s %s/%s", metric.Name, current, metric.Target))
+	}
+	return strings.Join(readings, ", ")
+}
+
+// hpaLabel describes the autoscaler on a single line, i.e. "web: 3 replicas, desired 5 (2-10), cpu 82%/70%"
+func hpaLabel(name string, status *GHorizontalPodAutoscalerStatus) string {
+	label := fmt.Sprintf("%s: %d replicas, desired %d (%d-%d)", name, status.CurrentReplicas, status.DesiredReplicas, status.MinReplicas, status.MaxReplicas)
+	if metrics := formatAutoscalerMetrics(status.Metrics); len(metrics) > 0 {
+		label += ", " + metrics
+	}
+	for _, reason := range []string{status.Limited, status.Inactive} {
+		if len(reason) > 0 {
+			label += ", " + reason
+		}
+	}
+	return label
+}
+
+// SetStatus refreshes the state and the label, and remembers the scale-up or scale-down when the desired replicas
+// moved. An autoscaler that cannot scale or read its metrics turns red.
+func (gd *GHorizontalPodAutoscaler) SetStatus(status *GHorizontalPodAutoscalerStatus) {
+	previous := gd.status
+	gd.status = status
+	if previous.DesiredReplicas > 0 && status.DesiredReplicas != previous.DesiredReplicas {
+		scaledAt := status.LastScaleTime
+		if !scaledAt.After(previous.LastScaleTime) {
+			scaledAt = time.Now() // the rescale was not carried out, i.e. it failed, so no time was reported
+		}
+		gd.recordScale(GScaleEvent{Time: scaledAt, From: previous.DesiredReplicas, To: status.DesiredReplicas, Metrics: formatAutoscalerMetrics(status.Metrics)})
+	}
+	gd.object.Color = hpaColor
+	if len(status.Inactive) > 0 {
+		gd.state = Failed
+		gd.object.Color = hpaInactiveColor
+	} else if status.CurrentReplicas != status.DesiredReplicas || len(status.Limited) > 0 {
+		gd.state = Loading
+	} else {
+		gd.state = Running
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(hpaLabel(gd.name, status))
+	}
+}
+
+// recordScale appends to the history, forgetting the oldest scale event once it is full
+func (gd *GHorizontalPodAutoscaler) recordScale(scale GScaleEvent) {
+	gd.history = append(gd.history, scale)
+	if len(gd.history) > hpaHistoryLength {
+		gd.history = gd.history[len(gd.history)-hpaHistoryLength:]
+	}
+}
+
+// scaleColor is green for a scale-up and teal for a scale-down
+func scaleColor(scale GScaleEvent) mgl.Vec3 {
+	if scale.To < scale.From {
+		return hpaScaleDownColor
+	}
+	return hpaScaleUpColor
+}
+
+// syncBars adds or removes bars until there is one for each scale event of the history
+func (gd *GHorizontalPodAutoscaler) syncBars() {
+	for len(gd.bars) < len(gd.history) {
+		barCube := &entity.Cube{}
+		barCube.Init(gd.parent.font, "")
+		barTransform := &camera.Transform3D{}
+		barTransform.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0, 0, 0}, nil, false)
+		bar := &scene.SceneObject{}
+		bar.Init(barCube, barTransform, gd.shaderID, hpaScaleUpColor, mgl.Vec3{1, 1, 1})
+		bar.AddOnClickHandler(gd.OnClick)
+		gd.parent.mainScene.AddObject(bar)
+		gd.bars = append(gd.bars, bar)
+	}
+	for len(gd.bars) > len(gd.history) {
+		gd.parent.mainScene.DeleteObject(gd.bars[len(gd.bars)-1])
+		gd.bars = gd.bars[:len(gd.bars)-1]
+	}
+}
+
+func (gd *GHorizontalPodAutoscaler) deleteBars() {
+	for _, bar := range gd.bars {
+		gd.parent.mainScene.DeleteObject(bar)
+	}
+	gd.bars = nil
+}
+
+// pulseColor is the color the autoscaler pulses towards while it cannot scale, or while it is pinned at maxReplicas
+func (gd *GHorizontalPodAutoscaler) pulseColor() (mgl.Vec3, bool) {
+	if len(gd.status.Inactive) > 0 {
+		return hpaInactiveColor, true
+	}
+	if gd.status.Limited == "TooManyReplicas" {
+		return hpaLimitedColor, true
+	}
+	return mgl.Vec3{}, false
+}
+
+// scaleTarget is the slot of the workload or custom resource the autoscaler scales
+func (gd *GHorizontalPodAutoscaler) scaleTarget() (SlotResource, bool) {
+	if len(gd.status.CustomScaleTarget.Name) > 0 {
+		return SlotResource{name: gd.status.CustomScaleTarget.Name, namespace: gd.status.CustomScaleTarget.Namespace, resource: GCUSTOMRESOURCE}, true
+	}
+	if resource, found := OWNER_KIND_RESOURCES[gd.status.ScaleTargetType]; found {
+		return SlotResource{name: gd.status.ScaleTargetName, namespace: gd.namespace, resource: resource}, true
+	}
+	return SlotResource{}, false
+}
+
+// UpdateLinks wires the autoscaler to its scale target, and lines up the bars of its history above it, each as tall as
+// the replicas it scaled to
+func (gd *GHorizontalPodAutoscaler) UpdateLinks() {
+	targets := []GLinkTarget{}
+	if target, found := gd.scaleTarget(); found {
+		targets = append(targets, GLinkTarget{slot: target, color: hpaColor, info: fmt.Sprintf("scales %s %s", strings.ToLower(gd.status.ScaleTargetType), gd.status.ScaleTargetName)})
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+
+	gd.syncBars()
+	origin := glinkEndpoint(gd)
+	maxReplicas := float32(max(gd.status.MaxReplicas, 1))
+	for i, bar := range gd.bars {
+		height := hpaHistoryHeight * min(float32(gd.history[i].To)/maxReplicas, 1)
+		position := mgl.Vec3{origin.X(), origin.Y() + 1.5 + height/2, origin.Z() - 1.2 + 0.35*float32(i)}
+		*bar.Transform.PositionAnimator.X_init = position
+		*bar.Transform.PositionAnimator.X_final = position
+		*bar.Transform.Scale = mgl.Vec3{0.25, height, 0.25}
+		bar.Color = scaleColor(gd.history[i])
+	}
+}
+
+// GetDetails lists the replicas, the metric readings against their targets and the scaling history, newest first
+func (gd *GHorizontalPodAutoscaler) GetDetails() []string {
+	lines := []string{fmt.Sprintf("scales %s %s: %d replicas, desired %d, min %d, max %d",
+		gd.status.ScaleTargetType, gd.status.ScaleTargetName, gd.status.CurrentReplicas, gd.status.DesiredReplicas, gd.status.MinReplicas, gd.status.MaxReplicas)}
+	for _, metric := range gd.status.Metrics {
+		lines = append(lines, "metric "+formatAutoscalerMetrics([]GAutoscalerMetric{metric}))
+	}
+	if len(gd.status.Limited) > 0 {
+		lines = append(lines, "limited: "+gd.status.Limited)
+	}
+	if len(gd.status.Inactive) > 0 {
+		lines = append(lines, "unable to scale: "+gd.status.Inactive)
+	}
+	for i := len(gd.history) - 1; i >= 0; i-- {
+		scale := gd.history[i]
+		direction := "up"
+		if scale.To < scale.From {
+			direction = "down"
+		}
+		lines = append(lines, fmt.Sprintf("%s scaled %s %d -> %d (%s)", scale.Time.Local().Format("Jan 2 15:04:05"), direction, scale.From, scale.To, scale.Metrics))
+	}
+	return lines
+}
+
+func (gd *GHorizontalPodAutoscaler) GetResource() GResource {
+	return GHORIZONTALPODAUTOSCALER
+}
+
+// removes the link to the scale target and the history bars from the main scene
+func (gd *GHorizontalPodAutoscaler) Delete() {
+	deleteGLinks(gd.links)
+	gd.deleteBars()
+}
+
+func (gd *GHorizontalPodAutoscaler) GetCurrentOffset() *mgl.Vec3 {
+	return gd.currentOffset
+}
+
+func (gd *GHorizontalPodAutoscaler) GetObject() *scene.SceneObject {
+	return gd.object
+}
+
+func (gd *GHorizontalPodAutoscaler) GetIdentifier() (string, string) {
+	return gd.name, gd.namespace
+}
+
+func (gd *GHorizontalPodAutoscaler) OnClick() {
+	gd.parent.SetSelected(gd)
+}
+
+func (gd *GHorizontalPodAutoscaler) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
+	for _, bar := range gd.bars {
+		bar.IsDeleting = true
+	}
+}
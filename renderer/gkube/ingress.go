@@ -1,6 +1,9 @@
 package gkube
 
 import (
+	"fmt"
+	"strings"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -9,9 +12,13 @@ import (
 )
 
 type GIngress struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GIngressStatus
+	links     map[string]*GLink
+	shaderID  uint32
 
 	name      string
 	namespace string
@@ -24,12 +31,15 @@ func (gd *GIngress) Create(parent *GCluster, name string, namespace string, offs
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GIngressStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 
 	gingress := &entity.WavefrontOBJ{FileName: "ingress.obj"}
 	gingress.Init(font, "")
 	t := &camera.Transform3D{}
 	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
-	gd.object.Init(gingress, t, shaderID, mgl.Vec3{float32(50) / 255, float32(211) / 255, float32(229) / 255}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gingress, t, shaderID, ingressLinkColor, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -42,7 +52,61 @@ func (gd *GIngress) GetResource() GResource {
 	return GINGRESS
 }
 
+var ingressLinkColor = mgl.Vec3{float32(50) / 255, float32(211) / 255, float32(229) / 255}
+
+func (gd *GIngress) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the label and the routes that UpdateLinks draws wires for
+func (gd *GIngress) SetStatus(status *GIngressStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		if len(status.ClassName) > 0 {
+			obj.SetText(fmt.Sprintf("%s (%s) %d routes", gd.name, status.ClassName, len(status.Routes)))
+		} else {
+			obj.SetText(fmt.Sprintf("%s %d routes", gd.name, len(status.Routes)))
+		}
+	}
+}
+
+// describes a route as host/path -> service:port, with the TLS secret terminating it if any
+func ingressRouteInfo(route GIngressRoute) string {
+	host := route.Host
+	if len(host) == 0 {
+		host = "*"
+	}
+	info := fmt.Sprintf("%s%s -> %s:%s", host, route.Path, route.ServiceName, route.ServicePort)
+	if len(route.TLSSecret) > 0 {
+		info += fmt.Sprintf(" (tls: %s)", route.TLSSecret)
+	}
+	return info
+}
+
+// UpdateLinks wires the ingress to every backend service. Routes sharing a service share a wire, which lists all of them
+// when hovered.
+func (gd *GIngress) UpdateLinks() {
+	targets := []GLinkTarget{}
+	routes := map[string][]string{}
+	for _, route := range gd.status.Routes {
+		if len(route.ServiceName) == 0 {
+			continue // resource backends are not drawn
+		}
+		if _, found := routes[route.ServiceName]; !found {
+			targets = append(targets, GLinkTarget{slot: SlotResource{name: route.ServiceName, namespace: gd.namespace, resource: GSERVICE}, color: ingressLinkColor})
+		}
+		routes[route.ServiceName] = append(routes[route.ServiceName], ingressRouteInfo(route))
+	}
+	for i := range targets {
+		targets[i].info = strings.Join(routes[targets[i].slot.name], ", ")
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
+
+// removes the links to the backend services from the main scene
 func (gd *GIngress) Delete() {
+	deleteGLinks(gd.links)
 }
 
 func (gd *GIngress) GetCurrentOffset() *mgl.Vec3 {
@@ -62,4 +126,8 @@ func (gd *GIngress) OnClick() {
 }
 
 func (gd *GIngress) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
 }
@@ -28,9 +28,12 @@ const (
 )
 
 type GJob struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GJobStatus
+	progress  []*scene.SceneObject // track and fill of the completions bar
 
 	name      string
 	namespace string
@@ -43,25 +46,94 @@ func (gd *GJob) Create(parent *GCluster, name string, namespace string, offset *
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
-	gdeploymentCube := &entity.Cube{}
-	gdeploymentCube.Init(font, fmt.Sprintf("%s", name))
+	gd.status = &GJobStatus{}
+	gjob := &entity.WavefrontOBJ{FileName: "job.obj"}
+	gjob.Init(font, "")
 	t := &camera.Transform3D{}
-	t.Init(offset, &mgl.Vec3{3, 3, 3}, nil, true)
-	gd.object.Init(gdeploymentCube, t, shaderID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(gjob, t, shaderID, mgl.Vec3{float32(229) / 255, float32(175) / 255, float32(50) / 255}, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
+	for _, color := range []mgl.Vec3{{0.5, 0.5, 0.5}, jobProgressColors[Running]} {
+		barCube := &entity.Cube{}
+		barCube.Init(font, "")
+		barTransform := &camera.Transform3D{}
+		barTransform.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0, 0, 0}, nil, false)
+		bar := &scene.SceneObject{}
+		bar.Init(barCube, barTransform, shaderID, color, mgl.Vec3{1, 1, 1})
+		bar.AddOnClickHandler(gd.OnClick)
+		gd.parent.mainScene.AddObject(bar)
+		gd.progress = append(gd.progress, bar)
+	}
+	gd.progress[0].Wireframe = true
+
 	gd.currentOffset = offset
 
 	gd.parent.mainScene.AddObject(gd.object)
 	return gd.object
 }
 
+func (gd *GJob) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+var jobProgressColors = map[State]mgl.Vec3{
+	Loading:   {0.94901960784, 0.65098039215, 0.16470588235},
+	Running:   {0.30980392156, 0.78431372549, 0.43137254902},
+	Succeeded: {0.30980392156, 0.78431372549, 0.43137254902},
+	Failed:    {0.89803921568, 0.19607843137, 0.19607843137},
+}
+
+// SetStatus refreshes the completions/parallelism label and the color of the progress bar
+func (gd *GJob) SetStatus(status *GJobStatus) {
+	gd.status = status
+	switch status.Condition {
+	case string(JobComplete):
+		gd.state = Succeeded
+	case string(JobFailed):
+		gd.state = Failed
+	case string(JobSuspended):
+		gd.state = Loading
+	default:
+		gd.state = Running
+	}
+	gd.progress[1].Color = jobProgressColors[gd.state]
+	label := fmt.Sprintf("%s %d/%d (%d active, parallelism %d)", gd.name, status.Succeeded, status.Completions, status.Active, status.Parallelism)
+	if status.Failed > 0 {
+		label = fmt.Sprintf("%s %d failed", label, status.Failed)
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(label)
+	}
+}
+
+// UpdateLinks keeps the progress bar above the job, filled by the share of completions that succeeded
+func (gd *GJob) UpdateLinks() {
+	origin := glinkEndpoint(gd)
+	width := float32(2.4)
+	fill := float32(0)
+	if gd.status.Completions > 0 {
+		fill = width * float32(min(gd.status.Succeeded, gd.status.Completions)) / float32(gd.status.Completions)
+	}
+	track := mgl.Vec3{origin.X(), origin.Y() + 1.8, origin.Z()}
+	filled := mgl.Vec3{origin.X(), origin.Y() + 1.8, origin.Z() - width/2 + fill/2}
+	*gd.progress[0].Transform.PositionAnimator.X_init = track
+	*gd.progress[0].Transform.PositionAnimator.X_final = track
+	*gd.progress[0].Transform.Scale = mgl.Vec3{0.3, 0.3, width}
+	*gd.progress[1].Transform.PositionAnimator.X_init = filled
+	*gd.progress[1].Transform.PositionAnimator.X_final = filled
+	*gd.progress[1].Transform.Scale = mgl.Vec3{0.25, 0.25, fill}
+}
+
 func (gd *GJob) GetResource() GResource {
 	return GJOB
 }
 
+// removes the progress bar from the main scene
 func (gd *GJob) Delete() {
-
+	for _, bar := range gd.progress {
+		gd.parent.mainScene.DeleteObject(bar)
+	}
 }
 
 func (gd *GJob) GetCurrentOffset() *mgl.Vec3 {
@@ -81,5 +153,8 @@ func (gd *GJob) OnClick() {
 }
 
 func (gd *GJob) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, bar := range gd.progress {
+		bar.IsDeleting = true
+	}
 }
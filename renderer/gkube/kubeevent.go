@@ -0,0 +1,202 @@
+package gkube
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+const (
+	kubeEventDetailLines   = 5               // events listed above the selected GOBJECT
+	kubeEventMessageLength = 80              // messages are cut to fit a detail line
+	kubeEventWarningWindow = 5 * time.Minute // a GOBJECT pulses while its last warning is more recent than this
+)
+
+var kubeEventPulseColor = mgl.Vec3{1, 0.55, 0}
+
+// GKubeEventIndex indexes events by the slot signature of their involved object. Events are recorded as the watcher
+// pushes them rather than through the GObjectEvent queue, which a burst of events would hold up for minutes.
+type GKubeEventIndex struct {
+	mutex    sync.Mutex
+	events   map[string]map[string]*GKubeEventStatus // involved signature -> event key -> event
+	involved map[string]string                       // event key -> involved signature
+	changed  bool                                    // whether events were recorded or forgotten since the last TakeChanged
+}
+
+func (gi *GKubeEventIndex) Init() {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	gi.events = make(map[string]map[string]*GKubeEventStatus)
+	gi.involved = make(map[string]string)
+	gi.changed = false
+}
+
+func kubeEventInvolvedSignature(event *GKubeEventStatus) string {
+	sr := SlotResource{name: event.InvolvedName, namespace: event.InvolvedNamespace, resource: event.InvolvedResource}
+	return sr.GetSignature()
+}
+
+// Record adds or replaces the event stored under key
+func (gi *GKubeEventIndex) Record(key string, event *GKubeEventStatus) {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	gi.forget(key)
+	signature := kubeEventInvolvedSignature(event)
+	if _, found := gi.events[signature]; !found {
+		gi.events[signature] = make(map[string]*GKubeEventStatus)
+	}
+	gi.events[signature][key] = event
+	gi.involved[key] = signature
+	gi.changed = true
+}
+
+func (gi *GKubeEventIndex) Forget(key string) {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	gi.forget(key)
+}
+
+// pre-condition: already has lock on the index
+func (gi *GKubeEventIndex) forget(key string) {
+	signature, found := gi.involved[key]
+	if !found {
+		return
+	}
+	delete(gi.events[signature], key)
+	if len(gi.events[signature]) == 0 {
+		delete(gi.events, signature)
+	}
+	delete(gi.involved, key)
+	gi.changed = true
+}
+
+// TakeChanged reports whether events were recorded or forgotten since it was last called
+func (gi *GKubeEventIndex) TakeChanged() bool {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	changed := gi.changed
+	gi.changed = false
+	return changed
+}
+
+// Recent returns the events of the involved object, newest first
+func (gi *GKubeEventIndex) Recent(signature string) []*GKubeEventStatus {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	events := []*GKubeEventStatus{}
+	for _, event := range gi.events[signature] {
+		events = append(events, event)
+	}
+	slices.SortFunc(events, func(a, b *GKubeEventStatus) int {
+		if c := b.LastTimestamp.Compare(a.LastTimestamp); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Reason, b.Reason)
+	})
+	return events
+}
+
+// HasRecentWarning reports whether the involved object had a warning within kubeEventWarningWindow of now
+func (gi *GKubeEventIndex) HasRecentWarning(signature string, now time.Time) bool {
+	gi.mutex.Lock()
+	defer gi.mutex.Unlock()
+	for _, event := range gi.events[signature] {
+		if event.Type == "Warning" && now.Sub(event.LastTimestamp) < kubeEventWarningWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// formatKubeEvent describes an event on a single detail line, i.e. "Warning BackOff x12 (3m ago): Back-off restarting..."
+func formatKubeEvent(event *GKubeEventStatus, now time.Time) string {
+	line := fmt.Sprintf("%s %s", event.Type, event.Reason)
+	if event.Count > 1 {
+		line += fmt.Sprintf(" x%d", event.Count)
+	}
+	if !event.LastTimestamp.IsZero() {
+		line += fmt.Sprintf(" (%s ago)", now.Sub(event.LastTimestamp).Truncate(time.Second))
+	}
+	message := event.Message
+	if len(message) > kubeEventMessageLength {
+		message = message[:kubeEventMessageLength-3] + "..."
+	}
+	if len(message) > 0 {
+		line += ": " + message
+	}
+	return line
+}
+
+// pushKubeEvent records or forgets the event in the index as soon as the watcher pushes it, the GOBJECTs it is about
+// pulse and their details list it on the next UpdateKubeEventPulses
+func (gc *GCluster) pushKubeEvent(eventType GEventStatus, name, namespace string, event *GKubeEventStatus) {
+	if eventType == GDELETE {
+		gc.kubeEvents.Forget(kubeEventKey(namespace, name))
+		return
+	}
+	gc.kubeEvents.Record(kubeEventKey(namespace, name), event)
+}
+
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) kubeEventDetails(gob GObject) []string {
+	sr := gc.getSlotContainingGObject(gob)
+	events := gc.kubeEvents.Recent(sr.GetSignature())
+	if len(events) == 0 {
+		return []string{}
+	}
+	now := time.Now()
+	lines := []string{"events:"}
+	for i, event := range events {
+		if i == kubeEventDetailLines {
+			lines = append(lines, fmt.Sprintf("... %d older", len(events)-kubeEventDetailLines))
+			break
+		}
+		lines = append(lines, formatKubeEvent(event, now))
+	}
+	return lines
+}
+
+// gstatusPulser is a GOBJECT that pulses as its own status says, i.e. a pod by its lifecycle or a stalled deployment
+type gstatusPulser interface {
+	pulseColor() (mgl.Vec3, bool)
+}
+
+// UpdateKubeEventPulses pulses the GOBJECTs with recent warnings and stops the pulse once their warnings grow old. Pods
+// and deployments pulse as their status says instead, while it does. The details of the selected GOBJECT are refreshed
+// once events arrived.
+func (gc *GCluster) UpdateKubeEventPulses() {
+	gc.gobjectMutex.Lock()
+	defer gc.gobjectMutex.Unlock()
+
+	if gc.kubeEvents.TakeChanged() && gc.details != nil && gc.details.target != nil {
+		gc.details.Show(gc.details.target, gc.detailLines(gc.details.target))
+	}
+
+	now := time.Now()
+	for _, gob := range gc.gobjects {
+		object := gob.GetObject()
+		if object == nil {
+			continue
+		}
+		sr := gc.getSlotContainingGObject(gob)
+		if pulser, ok := gob.(gstatusPulser); ok {
+			if color, found := pulser.pulseColor(); found {
+				object.Pulse(color) // the status says more than the warnings, i.e. BackOff of a crash-looping pod
+				continue
+			}
+		}
+		if gc.kubeEvents.HasRecentWarning(sr.GetSignature(), now) {
+			object.Pulse(kubeEventPulseColor)
+		} else if object.IsPulsing {
+			object.StopPulse()
+		}
+	}
+}
+
+func kubeEventKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
@@ -0,0 +1,64 @@
+package gkube
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_KubeEventIndex(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pod := SlotResource{name: "web-1", namespace: "default", resource: GPOD}
+	scheduled := &GKubeEventStatus{InvolvedResource: GPOD, InvolvedName: "web-1", InvolvedNamespace: "default", Type: "Normal", Reason: "Scheduled", LastTimestamp: now.Add(-10 * time.Minute)}
+	backOff := &GKubeEventStatus{InvolvedResource: GPOD, InvolvedName: "web-1", InvolvedNamespace: "default", Type: "Warning", Reason: "BackOff", Message: "Back-off restarting failed container", Count: 3, LastTimestamp: now.Add(-2 * time.Minute)}
+	oldBackOff := &GKubeEventStatus{InvolvedResource: GPOD, InvolvedName: "web-1", InvolvedNamespace: "default", Type: "Warning", Reason: "BackOff", Count: 3, LastTimestamp: now.Add(-6 * time.Minute)}
+
+	index := &GKubeEventIndex{}
+	index.Init()
+	index.Record("default/web-1.scheduled", scheduled)
+	index.Record("default/web-1.backoff", backOff)
+	checkTests(t, []Test{
+		{index.Recent(pod.GetSignature()), []*GKubeEventStatus{backOff, scheduled}},
+		{index.HasRecentWarning(pod.GetSignature(), now), true},
+		{formatKubeEvent(backOff, now), "Warning BackOff x3 (2m0s ago): Back-off restarting failed container"},
+	})
+
+	// the warning ages out once the event is replaced by an older one, and is gone once the event is deleted
+	index.Record("default/web-1.backoff", oldBackOff)
+	checkTests(t, []Test{
+		{index.HasRecentWarning(pod.GetSignature(), now), false},
+	})
+	index.Forget("default/web-1.backoff")
+	index.Forget("default/web-1.scheduled")
+	checkTests(t, []Test{
+		{len(index.Recent(pod.GetSignature())), 0},
+		{len(index.events), 0},
+	})
+}
+
+func Test_PushKubeEvent(t *testing.T) {
+	gc := createTestCluster()
+	gc.gobjectEventQueueMutex = &sync.Mutex{}
+	gc.kubeEvents = &GKubeEventIndex{}
+	gc.kubeEvents.Init()
+	pod := SlotResource{name: "web-1", namespace: "default", resource: GPOD}
+	backOff := &GKubeEventStatus{InvolvedResource: GPOD, InvolvedName: "web-1", InvolvedNamespace: "default", Type: "Warning", Reason: "BackOff", LastTimestamp: time.Now()}
+
+	// a burst of events is indexed as it is pushed, without holding up the GObjectEvents queued behind it
+	for i := range 100 {
+		gc.PushGObjectEvent(GCREATE, GKUBEEVENT, fmt.Sprintf("web-1.%d", i), "default", GNONE, GSETTING_NONE, nil, backOff, -1, nil)
+	}
+	gc.PushGObjectEvent(GCREATE, GPOD, "web-2", "default", GNONE, GSETTING_NONE, nil, &GPodStatus{}, -1, nil)
+	checkTests(t, []Test{
+		{len(gc.kubeEvents.Recent(pod.GetSignature())), 100},
+		{gc.kubeEvents.TakeChanged(), true},
+		{gc.kubeEvents.TakeChanged(), false},
+		{len(gc.gobjectEventQueue), 1},
+	})
+	gc.PushGObjectEvent(GDELETE, GKUBEEVENT, "web-1.0", "default", GNONE, GSETTING_NONE, nil, backOff, -1, nil)
+	checkTests(t, []Test{
+		{len(gc.kubeEvents.Recent(pod.GetSignature())), 99},
+		{gc.kubeEvents.TakeChanged(), true},
+	})
+}
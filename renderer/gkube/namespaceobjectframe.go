@@ -92,5 +92,9 @@ func (gd *GNamespaceObjectFrame) OnClick() {
 }
 
 func (gd *GNamespaceObjectFrame) SetDeleting() {
-
+	if !gd.isObjectFrameCreated {
+		gd.object.IsDeleteReady = true // never drawn, so there is nothing to animate out
+		return
+	}
+	gd.object.IsDeleting = true
 }
@@ -0,0 +1,158 @@
+package gkube
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	v41 "github.com/4ydx/gltext/v4.1"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+type GNode struct {
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GNodeStatus
+	usage     *GResourceUsage // polled from metrics.k8s.io, nil when not reported
+	links     map[string]*GLink
+	shaderID  uint32
+
+	name          string
+	namespace     string
+	currentOffset *mgl.Vec3
+}
+
+func (gd *GNode) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
+	gd.name = name
+	gd.namespace = namespace
+	gd.parent = parent
+	gd.object = &scene.SceneObject{}
+	gd.status = &GNodeStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
+
+	gnode := &entity.Cube{}
+	gnode.Init(font, name)
+	t := &camera.Transform3D{}
+	t.Init(offset, &mgl.Vec3{3, 0.5, 3}, nil, true)
+	gd.object.Init(gnode, t, shaderID, nodeStateColors[Loading], mgl.Vec3{1, 1, 1})
+	gd.object.AddOnClickHandler(gd.OnClick)
+
+	gd.currentOffset = offset
+
+	gd.parent.mainScene.AddObject(gd.object)
+	return gd.object
+}
+
+func (gd *GNode) GetResource() GResource {
+	return GNODE
+}
+
+func (gd *GNode) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+var nodeStateColors = map[State]mgl.Vec3{
+	Loading: {0.94901960784, 0.65098039215, 0.16470588235}, // under pressure
+	Running: {0.30980392156, 0.78431372549, 0.43137254902},
+	Failed:  {0.89803921568, 0.19607843137, 0.19607843137}, // NotReady
+}
+
+// SetStatus colors the node by its conditions; a cordoned node is drawn as a wireframe
+func (gd *GNode) SetStatus(status *GNodeStatus) {
+	gd.status = status
+	if !status.Ready {
+		gd.state = Failed
+	} else if len(status.Pressures) > 0 {
+		gd.state = Loading
+	} else {
+		gd.state = Running
+	}
+	gd.applyUsageColor()
+	gd.object.Wireframe = status.Unschedulable
+	if obj, ok := gd.object.Object.(*entity.Cube); ok {
+		obj.SetText(fmt.Sprintf("%s %s", gd.name, strings.Join(nodeConditionNames(status), ", ")))
+	}
+}
+
+func nodeConditionNames(status *GNodeStatus) []string {
+	names := []string{"Ready"}
+	if !status.Ready {
+		names = []string{"NotReady"}
+	}
+	if status.Unschedulable {
+		names = append(names, "cordoned")
+	}
+	return append(names, status.Pressures...)
+}
+
+// UpdateLinks wires a NotReady node to every pod scheduled on it
+func (gd *GNode) UpdateLinks() {
+	targets := []GLinkTarget{}
+	if !gd.status.Ready {
+		for _, gp := range gd.parent.nodePods(gd.name) {
+			targets = append(targets, GLinkTarget{slot: gd.parent.getSlotContainingGObject(gp), color: nodeStateColors[Failed]})
+		}
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
+
+// GetDetails lists the usage of the node and the workloads with pods on it, i.e. those affected when it goes NotReady
+func (gd *GNode) GetDetails() []string {
+	pods := gd.parent.nodePods(gd.name)
+	lines := append(gd.usageDetails(), fmt.Sprintf("node %s (%s) runs %d pods:", gd.name, strings.Join(nodeConditionNames(gd.status), ", "), len(pods)))
+	return append(lines, summarizeNodeWorkloads(pods)...)
+}
+
+// summarizeNodeWorkloads counts the pods of each owner, listing pods without an owner on their own
+func summarizeNodeWorkloads(pods []*GPod) []string {
+	counts := map[string]int{}
+	for _, gp := range pods {
+		workload := fmt.Sprintf("%s/%s", gp.namespace, gp.name)
+		if len(gp.status.OwnerReferenceName) > 0 {
+			workload = fmt.Sprintf("%s/%s (%s)", gp.namespace, gp.status.OwnerReferenceName, gp.status.OwnerReferenceType)
+		}
+		counts[workload]++
+	}
+	lines := []string{}
+	for workload, count := range counts {
+		lines = append(lines, fmt.Sprintf("%s: %d", workload, count))
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+func (gd *GNode) Delete() {
+	deleteGLinks(gd.links)
+}
+
+func (gd *GNode) GetCurrentOffset() *mgl.Vec3 {
+	return gd.currentOffset
+}
+
+func (gd *GNode) GetObject() *scene.SceneObject {
+	return gd.object
+}
+
+func (gd *GNode) GetIdentifier() (string, string) {
+	return gd.name, gd.namespace
+}
+
+func (gd *GNode) OnClick() {
+	gd.parent.SetSelected(gd)
+	if !gd.object.OnClick {
+		gd.parent.SetHighlighted(gd.parent.getPodsOnNode(gd.name))
+	}
+}
+
+func (gd *GNode) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
+}
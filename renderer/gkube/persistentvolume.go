@@ -29,9 +29,13 @@ const (
 )
 
 type GPersistentVolume struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GPersistentVolumeStatus
+	links     map[string]*GLink
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -43,11 +47,14 @@ func (gd *GPersistentVolume) Create(parent *GCluster, name string, namespace str
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GPersistentVolumeStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 	gpod := &entity.HeptagonalPrism{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
 	t.Init(offset, &mgl.Vec3{3, 3, 3}, nil, true)
-	gd.object.Init(gpod, t, shaderID, mgl.Vec3{float32(218) / 255, float32(227) / 255, float32(227) / 255}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gpod, t, shaderID, storageStateColors[Running], mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -60,8 +67,49 @@ func (gd *GPersistentVolume) GetResource() GResource {
 	return GPERSISTENTVOLUME
 }
 
-func (gd *GPersistentVolume) Delete() {
+func (gd *GPersistentVolume) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the state, color and label of the volume. Released and Failed volumes are flagged.
+func (gd *GPersistentVolume) SetStatus(status *GPersistentVolumeStatus) {
+	gd.status = status
+	switch PersistentVolumePhase(status.Phase) {
+	case VolumeBound, VolumeAvailable:
+		gd.state = Running
+	case VolumeFailed:
+		gd.state = Failed
+	default:
+		gd.state = Loading
+	}
+	gd.object.Color = storageStateColors[gd.state]
+	label := storageLabel(gd.name, status.Capacity, status.AccessModes)
+	if len(status.ReclaimPolicy) > 0 {
+		label += " " + status.ReclaimPolicy
+	}
+	if len(status.ClaimName) > 0 {
+		label += fmt.Sprintf(" <- %s/%s", status.ClaimNamespace, status.ClaimName)
+	}
+	if gd.state != Running {
+		label = fmt.Sprintf("%s (%s)", label, status.Phase)
+	}
+	if obj, ok := gd.object.Object.(*entity.HeptagonalPrism); ok {
+		obj.SetText(label)
+	}
+}
 
+// UpdateLinks wires the volume to the storage class it was provisioned from
+func (gd *GPersistentVolume) UpdateLinks() {
+	targets := []GLinkTarget{}
+	if len(gd.status.StorageClassName) > 0 {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: gd.status.StorageClassName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GSTORAGECLASS}, color: storageStateColors[gd.state]})
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
+
+// removes the link to the storage class from the main scene
+func (gd *GPersistentVolume) Delete() {
+	deleteGLinks(gd.links)
 }
 
 func (gd *GPersistentVolume) GetCurrentOffset() *mgl.Vec3 {
@@ -81,5 +129,8 @@ func (gd *GPersistentVolume) OnClick() {
 }
 
 func (gd *GPersistentVolume) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
 }
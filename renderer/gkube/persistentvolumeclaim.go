@@ -2,6 +2,7 @@ package gkube
 
 import (
 	"fmt"
+	"strings"
 
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
@@ -26,9 +27,13 @@ const (
 )
 
 type GPersistentVolumeClaim struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GPersistentVolumeClaimStatus
+	links     map[string]*GLink
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -40,12 +45,15 @@ func (gd *GPersistentVolumeClaim) Create(parent *GCluster, name string, namespac
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GPersistentVolumeClaimStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
 	tOffset := mgl.Vec3{offset.X(), offset.Y() + 0.25, offset.Z()}
 	t.Init(&tOffset, &mgl.Vec3{1.6, 1.4, 1}, nil, true)
-	gd.object.Init(gpod, t, shaderID, mgl.Vec3{float32(218) / 255, float32(227) / 255, float32(227) / 255}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gpod, t, shaderID, storageStateColors[Running], mgl.Vec3{1, 1, 1})
 
 	gd.object.AddOnClickHandler(gd.OnClick)
 
@@ -55,12 +63,68 @@ func (gd *GPersistentVolumeClaim) Create(parent *GCluster, name string, namespac
 	return gd.object
 }
 
+func (gd *GPersistentVolumeClaim) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// storage objects are flagged amber while pending or released, and red once lost or failed
+var storageStateColors = map[State]mgl.Vec3{
+	Loading: {0.94901960784, 0.65098039215, 0.16470588235},
+	Running: {float32(218) / 255, float32(227) / 255, float32(227) / 255},
+	Failed:  {0.89803921568, 0.19607843137, 0.19607843137},
+}
+
+// describes the capacity and access modes of a claim or volume, i.e. "10Gi RWO"
+func storageLabel(name, capacity string, accessModes []string) string {
+	label := name
+	if len(capacity) > 0 {
+		label += " " + capacity
+	}
+	if len(accessModes) > 0 {
+		label += " " + strings.Join(accessModes, ",")
+	}
+	return label
+}
+
+// SetStatus refreshes the state, color and label of the claim. Pending and Lost claims are flagged.
+func (gd *GPersistentVolumeClaim) SetStatus(status *GPersistentVolumeClaimStatus) {
+	gd.status = status
+	if status.Phase == string(ClaimBound) {
+		gd.state = Running
+	} else if status.Phase == string(ClaimLost) {
+		gd.state = Failed
+	} else {
+		gd.state = Loading
+	}
+	gd.object.Color = storageStateColors[gd.state]
+	label := storageLabel(gd.name, status.Capacity, status.AccessModes)
+	if gd.state != Running {
+		label = fmt.Sprintf("%s (%s)", label, status.Phase)
+	}
+	if obj, ok := gd.object.Object.(*entity.Heptagon); ok {
+		obj.SetText(label)
+	}
+}
+
+// UpdateLinks wires a bound claim to its volume. A claim still waiting on dynamic provisioning is wired to its
+// storage class as wireframe instead.
+func (gd *GPersistentVolumeClaim) UpdateLinks() {
+	targets := []GLinkTarget{}
+	if len(gd.status.VolumeName) > 0 {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: gd.status.VolumeName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GPERSISTENTVOLUME}, color: storageStateColors[gd.state]})
+	} else if len(gd.status.StorageClassName) > 0 {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: gd.status.StorageClassName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GSTORAGECLASS}, color: storageStateColors[gd.state], wireframe: true})
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
+
 func (gd *GPersistentVolumeClaim) GetResource() GResource {
 	return GPERSISTENTVOLUMECLAIM
 }
 
+// removes the link to the volume from the main scene
 func (gd *GPersistentVolumeClaim) Delete() {
-
+	deleteGLinks(gd.links)
 }
 
 func (gd *GPersistentVolumeClaim) GetCurrentOffset() *mgl.Vec3 {
@@ -80,5 +144,8 @@ func (gd *GPersistentVolumeClaim) OnClick() {
 }
 
 func (gd *GPersistentVolumeClaim) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
 }
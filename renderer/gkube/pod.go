@@ -1,6 +1,9 @@
 package gkube
 
 import (
+	"fmt"
+	"slices"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -13,6 +16,16 @@ type GPod struct {
 	object    *scene.SceneObject
 	state     State
 	kubeState map[string]interface{}
+	claims    []string
+	status    *GPodStatus
+	usage     *GResourceUsage // polled from metrics.k8s.io, nil when not reported
+	links     map[string]*GLink
+	shaderID  uint32
+
+	containerUsage map[string]GResourceUsage // by container name, polled with usage
+
+	containers      []*GContainer // drawn while the pod is expanded
+	containersShown bool
 
 	name      string
 	namespace string
@@ -25,7 +38,10 @@ func (gd *GPod) Create(parent *GCluster, name string, namespace string, offset *
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
-	color := mgl.Vec3{0.19607843137, 0.42352941176, 0.89803921568}
+	gd.status = &GPodStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
+	color := podLifecycleVisuals[PodPending].color
 	onClickColor := mgl.Vec3{0.19607843137, 0.42352941176, 0.89803921568}
 
 	gpod := &entity.WavefrontOBJ{FileName: "pod.obj"}
@@ -46,12 +62,165 @@ func (gd *GPod) SetKubeState(kubeState map[string]interface{}) {
 	gd.kubeState = kubeState
 }
 
+var podStateColors = map[State]mgl.Vec3{
+	Loading:   {0.19607843137, 0.42352941176, 0.89803921568},
+	Running:   {0.19607843137, 0.42352941176, 0.89803921568},
+	Succeeded: {0.5, 0.5, 0.5},
+	Failed:    {0.89803921568, 0.19607843137, 0.19607843137},
+}
+
+// podLifecycleVisual is how a pod is drawn while it goes through a lifecycle
+type podLifecycleVisual struct {
+	state     State
+	color     mgl.Vec3
+	pulse     *mgl.Vec3 // the color the pod pulses towards, if it pulses
+	spinning  bool
+	wireframe bool
+}
+
+var podLifecycleVisuals = map[GPodLifecycle]podLifecycleVisual{
+	PodPending:           {state: Loading, color: mgl.Vec3{0.94901960784, 0.65098039215, 0.16470588235}, wireframe: true},
+	PodContainerCreating: {state: Loading, color: mgl.Vec3{0.50980392156, 0.70588235294, 0.96078431372}, spinning: true, wireframe: true},
+	PodRunning:           {state: Running, color: podStateColors[Running], spinning: true},
+	PodNotReady:          {state: Running, color: podStateColors[Running], pulse: &mgl.Vec3{0.94901960784, 0.65098039215, 0.16470588235}, spinning: true},
+	PodCrashLoopBackOff:  {state: Failed, color: podStateColors[Failed], pulse: &mgl.Vec3{0.3, 0.05, 0.05}},
+	PodImagePullBackOff:  {state: Failed, color: mgl.Vec3{0.58039215686, 0.33333333333, 0.85098039215}, pulse: &mgl.Vec3{0.2, 0.1, 0.3}},
+	PodOOMKilled:         {state: Failed, color: mgl.Vec3{0.85098039215, 0.10196078431, 0.54901960784}, pulse: &mgl.Vec3{1, 1, 1}},
+	PodTerminating:       {state: Loading, color: mgl.Vec3{0.5, 0.5, 0.5}, spinning: true, wireframe: true},
+	PodSucceeded:         {state: Succeeded, color: podStateColors[Succeeded]},
+	PodFailed:            {state: Failed, color: podStateColors[Failed]},
+	PodUnknown:           {state: Loading, color: mgl.Vec3{0.25, 0.25, 0.25}, wireframe: true},
+}
+
+// podLifecycle is the lifecycle of the status, falling back onto its phase for statuses that do not carry one
+func podLifecycle(status *GPodStatus) GPodLifecycle {
+	if _, found := podLifecycleVisuals[status.Lifecycle]; found {
+		return status.Lifecycle
+	}
+	switch status.Phase {
+	case "Running":
+		return PodRunning
+	case "Succeeded":
+		return PodSucceeded
+	case "Failed":
+		return PodFailed
+	case "Unknown":
+		return PodUnknown
+	}
+	return PodPending
+}
+
+// podLabel names the pod with its lifecycle, the progress of its init containers while they run, and how often its
+// containers restarted, i.e. "web-0 (Init:1/3)" or "web-1 (CrashLoopBackOff, 12 restarts)"
+func podLabel(name string, status *GPodStatus) string {
+	lifecycle := podLifecycle(status)
+	label := string(lifecycle)
+	if completed := initProgress(status.InitContainers); completed < len(status.InitContainers) && (lifecycle == PodPending || lifecycle == PodContainerCreating) {
+		label = fmt.Sprintf("Init:%d/%d", completed, len(status.InitContainers))
+	}
+	restarts := int32(0)
+	for _, container := range podContainerStatuses(status) {
+		restarts += container.RestartCount
+	}
+	if restarts == 1 {
+		return fmt.Sprintf("%s (%s, 1 restart)", name, label)
+	} else if restarts > 1 {
+		return fmt.Sprintf("%s (%s, %d restarts)", name, label, restarts)
+	}
+	return fmt.Sprintf("%s (%s)", name, label)
+}
+
+// SetStatus refreshes the state, color, animation and label of the pod from its lifecycle
+func (gd *GPod) SetStatus(status *GPodStatus) {
+	previous := podLifecycle(gd.status)
+	gd.status = status
+	visual := podLifecycleVisuals[podLifecycle(status)]
+	gd.state = visual.state
+	gd.claims = status.PersistentVolumeClaims
+	gd.applyUsageColor()
+	gd.object.Spinning = visual.spinning
+	gd.object.Wireframe = visual.wireframe
+	if previous != podLifecycle(status) {
+		gd.object.StopPulse() // pulse in the color of the new lifecycle
+	}
+	if color, found := gd.pulseColor(); found {
+		gd.object.Pulse(color)
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(podLabel(gd.name, status))
+	}
+	gd.refreshContainers()
+}
+
+// pulseColor is the color the pod pulses towards in its lifecycle, or else once it nears its memory limit
+func (gd *GPod) pulseColor() (mgl.Vec3, bool) {
+	if pulse := podLifecycleVisuals[podLifecycle(gd.status)].pulse; pulse != nil {
+		return *pulse, true
+	}
+	if gd.nearMemoryLimit() {
+		return memoryLimitGlowColor, true
+	}
+	return mgl.Vec3{}, false
+}
+
 func (gd *GPod) GetResource() GResource {
 	return GPOD
 }
 
+var podClaimLinkColor = mgl.Vec3{float32(218) / 255, float32(227) / 255, float32(227) / 255}
+
+// UpdateLinks wires the pod to the claims it mounts, i.e. the claim generated from a StatefulSet's volumeClaimTemplates,
+// to the ConfigMaps and Secrets it consumes and to the service account it runs as. The containers of an expanded pod
+// follow it.
+func (gd *GPod) UpdateLinks() {
+	targets := []GLinkTarget{}
+	for _, claimName := range gd.claims {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: claimName, namespace: gd.namespace, resource: GPERSISTENTVOLUMECLAIM}, color: podClaimLinkColor})
+	}
+	for _, configMapName := range gd.status.ConfigMaps {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: configMapName, namespace: gd.namespace, resource: GCONFIGMAP}, color: configMapColor, info: "configmap " + configMapName})
+	}
+	for _, secretName := range gd.status.Secrets {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: secretName, namespace: gd.namespace, resource: GSECRET}, color: secretColor, info: "secret " + secretName})
+	}
+	if len(gd.status.ServiceAccountName) > 0 {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: gd.status.ServiceAccountName, namespace: gd.namespace, resource: GSERVICEACCOUNT}, color: rbacSubjectColor, wireframe: true, info: "serviceaccount " + gd.status.ServiceAccountName})
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+	if gd.expanded() != gd.containersShown {
+		gd.refreshContainers()
+	}
+	gd.placeContainers()
+}
+
+// GetDetails lists the usage of the pod, then answers "what can this pod do" with the effective permissions of its
+// service account
+func (gd *GPod) GetDetails() []string {
+	return append(gd.usageDetails(), gd.parent.permissionDetails(gd.namespace, gd.status.ServiceAccountName)...)
+}
+
+// consumes reports whether the pod consumes the ConfigMap or Secret, including image pull secrets of its service account
+// pre-condition: already has lock on gobjects
+func (gd *GPod) consumes(resource GResource, name string) bool {
+	if resource == GCONFIGMAP {
+		return slices.Contains(gd.status.ConfigMaps, name)
+	}
+	if resource == GSECRET {
+		if slices.Contains(gd.status.Secrets, name) {
+			return true
+		}
+		serviceAccount := SlotResource{name: gd.status.ServiceAccountName, namespace: gd.namespace, resource: GSERVICEACCOUNT}
+		if gsa, ok := gd.parent.getGObjectFromSlot(serviceAccount).(*GServiceAccount); ok {
+			return slices.Contains(gsa.status.ImagePullSecrets, name)
+		}
+	}
+	return false
+}
+
 // removes self from the main scene
 func (gd *GPod) Delete() {
+	deleteGLinks(gd.links)
+	gd.deleteContainers()
 	// gd.parent.mainScene.DeleteObject(gd.object) // remove from the main scene - stops drawing
 	// gd.parent.DeleteGObject(gd)                 // remove from the Cluster's memory
 }
@@ -74,4 +243,10 @@ func (gd *GPod) OnClick() {
 
 func (gd *GPod) SetDeleting() {
 	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
+	for _, container := range gd.containers {
+		container.object.IsDeleting = true
+	}
 }
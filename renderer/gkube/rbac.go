@@ -0,0 +1,202 @@
+package gkube
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+// GScopedPolicyRule is a rule granted to a subject, either within a namespace or cluster-wide if Namespace is empty
+type GScopedPolicyRule struct {
+	GPolicyRule
+	Namespace string
+}
+
+// serviceAccountMatchesSubject reports whether a binding subject refers to the service account, either by name or through
+// one of the groups every service account belongs to. Service account subjects without a namespace default to the
+// namespace of the binding.
+func serviceAccountMatchesSubject(subject GRoleSubject, bindingNamespace, namespace, name string) bool {
+	switch subject.Kind {
+	case "ServiceAccount":
+		subjectNamespace := subject.Namespace
+		if len(subjectNamespace) == 0 {
+			subjectNamespace = bindingNamespace
+		}
+		return subject.Name == name && subjectNamespace == namespace
+	case "User":
+		return subject.Name == fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+	case "Group":
+		return subject.Name == "system:serviceaccounts" || subject.Name == "system:serviceaccounts:"+namespace || subject.Name == "system:authenticated"
+	}
+	return false
+}
+
+func scopePolicyRules(rules []GPolicyRule, namespace string) []GScopedPolicyRule {
+	scoped := []GScopedPolicyRule{}
+	for _, rule := range rules {
+		scoped = append(scoped, GScopedPolicyRule{GPolicyRule: rule, Namespace: namespace})
+	}
+	return scoped
+}
+
+// getServiceAccountRules collects the rules granted to the service account by every RoleBinding in its namespace and
+// every ClusterRoleBinding. Bindings to roles that have not been drawn yet grant nothing.
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) getServiceAccountRules(namespace, name string) []GScopedPolicyRule {
+	rules := []GScopedPolicyRule{}
+	for _, gob := range gc.gobjects {
+		if binding, ok := gob.(*GRoleBinding); ok && binding.namespace == namespace {
+			if !slices.ContainsFunc(binding.status.Subjects, func(subject GRoleSubject) bool {
+				return serviceAccountMatchesSubject(subject, binding.namespace, namespace, name)
+			}) {
+				continue
+			}
+			if binding.status.RoleKind == "ClusterRole" {
+				if role, ok := gc.getGObjectFromSlot(SlotResource{name: binding.status.RoleName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GCLUSTERROLE}).(*GClusterRole); ok {
+					rules = append(rules, scopePolicyRules(role.status.Rules, namespace)...)
+				}
+			} else if role, ok := gc.getGObjectFromSlot(SlotResource{name: binding.status.RoleName, namespace: namespace, resource: GROLE}).(*GRole); ok {
+				rules = append(rules, scopePolicyRules(role.status.Rules, namespace)...)
+			}
+		}
+		if binding, ok := gob.(*GClusterRoleBinding); ok {
+			if !slices.ContainsFunc(binding.status.Subjects, func(subject GRoleSubject) bool {
+				return serviceAccountMatchesSubject(subject, "", namespace, name)
+			}) {
+				continue
+			}
+			if role, ok := gc.getGObjectFromSlot(SlotResource{name: binding.status.RoleName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GCLUSTERROLE}).(*GClusterRole); ok {
+				rules = append(rules, scopePolicyRules(role.status.Rules, CLUSTER_SCOPED_NAMESPACE)...)
+			}
+		}
+	}
+	return rules
+}
+
+// SummarizePermissions merges the verbs granted on each resource into one line per resource and scope, i.e.
+// "pods: get, list, watch (in default)". Resources of named API groups are qualified like kubectl does, i.e.
+// "deployments.apps" and "deployments.apps/scale" for subresources, and rules limited to resource names list them in
+// brackets.
+func SummarizePermissions(rules []GScopedPolicyRule) []string {
+	type permission struct {
+		target string
+		scope  string
+	}
+	verbs := map[permission][]string{}
+	for _, rule := range rules {
+		scope := "cluster-wide"
+		if len(rule.Namespace) > 0 {
+			scope = "in " + rule.Namespace
+		}
+		targets := []string{}
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				target := resource
+				if len(group) > 0 {
+					name, subresource, found := strings.Cut(resource, "/")
+					target = name + "." + group
+					if found {
+						target += "/" + subresource
+					}
+				}
+				if len(rule.ResourceNames) > 0 {
+					target = fmt.Sprintf("%s [%s]", target, strings.Join(rule.ResourceNames, ", "))
+				}
+				targets = append(targets, target)
+			}
+		}
+		targets = append(targets, rule.NonResourceURLs...)
+		for _, target := range targets {
+			key := permission{target: target, scope: scope}
+			verbs[key] = append(verbs[key], rule.Verbs...)
+		}
+	}
+	lines := []string{}
+	for key, keyVerbs := range verbs {
+		slices.Sort(keyVerbs)
+		lines = append(lines, fmt.Sprintf("%s: %s (%s)", key.target, strings.Join(slices.Compact(keyVerbs), ", "), key.scope))
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// permissionDetails lists what the service account can do, for display next to the selected pod or service account
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) permissionDetails(namespace, serviceAccountName string) []string {
+	if len(serviceAccountName) == 0 {
+		serviceAccountName = "default"
+	}
+	details := []string{fmt.Sprintf("serviceaccount %s/%s can:", namespace, serviceAccountName)}
+	permissions := SummarizePermissions(gc.getServiceAccountRules(namespace, serviceAccountName))
+	if len(permissions) == 0 {
+		return append(details, "nothing")
+	}
+	return append(details, permissions...)
+}
+
+var (
+	rbacRoleColor    = mgl.Vec3{float32(229) / 255, float32(50) / 255, float32(59) / 255}
+	rbacSubjectColor = mgl.Vec3{float32(229) / 255, float32(145) / 255, float32(50) / 255}
+)
+
+// bindingLinkTargets wires a binding to its role and to every service account among its subjects
+func bindingLinkTargets(role SlotResource, subjects []GRoleSubject, bindingNamespace string) []GLinkTarget {
+	targets := []GLinkTarget{{slot: role, color: rbacRoleColor}}
+	for _, subject := range subjects {
+		if subject.Kind != "ServiceAccount" {
+			continue
+		}
+		namespace := subject.Namespace
+		if len(namespace) == 0 {
+			namespace = bindingNamespace
+		}
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: subject.Name, namespace: namespace, resource: GSERVICEACCOUNT}, color: rbacSubjectColor, info: fmt.Sprintf("subject %s/%s", namespace, subject.Name)})
+	}
+	return targets
+}
+
+// createSubjectMarkers draws the User and Group subjects of a binding, which are not kubernetes objects of their own
+func (gc *GCluster) createSubjectMarkers(subjects []GRoleSubject, shaderID uint32, onClick func()) []*scene.SceneObject {
+	markers := []*scene.SceneObject{}
+	for _, subject := range subjects {
+		fileName := ""
+		if subject.Kind == "User" {
+			fileName = "user.obj"
+		} else if subject.Kind == "Group" {
+			fileName = "group.obj"
+		} else {
+			continue
+		}
+		markerOBJ := &entity.WavefrontOBJ{FileName: fileName}
+		markerOBJ.Init(gc.font, subject.Name)
+		t := &camera.Transform3D{}
+		t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{0.5, 0.5, 0.5}, nil, false)
+		marker := &scene.SceneObject{}
+		marker.Init(markerOBJ, t, shaderID, rbacSubjectColor, mgl.Vec3{1, 1, 1})
+		marker.AddOnClickHandler(onClick)
+		gc.mainScene.AddObject(marker)
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// placeSubjectMarkers lines the subject markers up above the binding
+func placeSubjectMarkers(binding GObject, markers []*scene.SceneObject) {
+	origin := glinkEndpoint(binding)
+	for i, marker := range markers {
+		position := mgl.Vec3{origin.X() + 1.5*float32(i) - 0.75*float32(len(markers)-1), origin.Y() + 2.5, origin.Z()}
+		*marker.Transform.PositionAnimator.X_init = position
+		*marker.Transform.PositionAnimator.X_final = position
+	}
+}
+
+func (gc *GCluster) deleteSubjectMarkers(markers []*scene.SceneObject) {
+	for _, marker := range markers {
+		gc.mainScene.DeleteObject(marker)
+	}
+}
@@ -0,0 +1,31 @@
+package gkube
+
+import "testing"
+
+func Test_SummarizePermissions(t *testing.T) {
+	readPods := GPolicyRule{Verbs: []string{"list", "get"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	watchPods := GPolicyRule{Verbs: []string{"watch", "get"}, APIGroups: []string{""}, Resources: []string{"pods"}}
+	scaleDeployment := GPolicyRule{Verbs: []string{"update"}, APIGroups: []string{"apps"}, Resources: []string{"deployments/scale"}, ResourceNames: []string{"web"}}
+	healthz := GPolicyRule{Verbs: []string{"get"}, NonResourceURLs: []string{"/healthz"}}
+	checkTests(t, []Test{
+		{SummarizePermissions([]GScopedPolicyRule{}), []string{}},
+		{SummarizePermissions(scopePolicyRules([]GPolicyRule{readPods, watchPods}, "default")), []string{"pods: get, list, watch (in default)"}},
+		{SummarizePermissions(append(scopePolicyRules([]GPolicyRule{readPods}, "default"), scopePolicyRules([]GPolicyRule{readPods, healthz}, CLUSTER_SCOPED_NAMESPACE)...)), []string{
+			"/healthz: get (cluster-wide)",
+			"pods: get, list (cluster-wide)",
+			"pods: get, list (in default)",
+		}},
+		{SummarizePermissions(scopePolicyRules([]GPolicyRule{scaleDeployment}, "prod")), []string{"deployments.apps/scale [web]: update (in prod)"}},
+	})
+}
+
+func Test_ServiceAccountMatchesSubject(t *testing.T) {
+	checkTests(t, []Test{
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "ServiceAccount", Name: "builder"}, "ci", "ci", "builder"), true},
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "ServiceAccount", Name: "builder", Namespace: "ci"}, "default", "default", "builder"), false},
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "User", Name: "system:serviceaccount:ci:builder"}, "", "ci", "builder"), true},
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "Group", Name: "system:serviceaccounts:ci"}, "", "ci", "builder"), true},
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "Group", Name: "system:serviceaccounts:prod"}, "", "ci", "builder"), false},
+		{serviceAccountMatchesSubject(GRoleSubject{Kind: "User", Name: "jane"}, "", "ci", "builder"), false},
+	})
+}
@@ -1,6 +1,8 @@
 package gkube
 
 import (
+	"fmt"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -19,10 +21,18 @@ const (
 	ReplicaSetReplicaFailure ReplicaSetConditionType = "ReplicaFailure"
 )
 
+var (
+	replicaSetColor           = mgl.Vec3{1, 1, 0}
+	replicaSetSupersededColor = mgl.Vec3{0.45, 0.45, 0.25} // faded once a newer revision rolled out
+)
+
 type GReplicaSet struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent     *GCluster
+	object     *scene.SceneObject
+	state      State
+	kubeState  map[string]interface{}
+	status     *GReplicaSetStatus
+	superseded bool
 
 	name      string
 	namespace string
@@ -35,13 +45,14 @@ func (gd *GReplicaSet) Create(parent *GCluster, name string, namespace string, o
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GReplicaSetStatus{}
 	// gd.object.Wireframe = true
 
 	greplicaset := &entity.WavefrontOBJ{FileName: "replicaset.obj"}
 	greplicaset.Init(font, "")
 	t := &camera.Transform3D{}
 	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
-	gd.object.Init(greplicaset, t, shaderID, mgl.Vec3{1, 1, 0}, mgl.Vec3{1, 1, 0})
+	gd.object.Init(greplicaset, t, shaderID, replicaSetColor, mgl.Vec3{1, 1, 0})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -50,6 +61,97 @@ func (gd *GReplicaSet) Create(parent *GCluster, name string, namespace string, o
 	return gd.object
 }
 
+func (gd *GReplicaSet) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the state and the revision and ready/desired label, i.e. "web-7d4b9 rev 3 2/3"
+func (gd *GReplicaSet) SetStatus(status *GReplicaSetStatus) {
+	gd.status = status
+	if status.ReadyReplicas == status.Replicas {
+		gd.state = Running
+	} else {
+		gd.state = Loading
+	}
+	label := gd.name
+	if status.Revision > 0 {
+		label = fmt.Sprintf("%s rev %d", label, status.Revision)
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(fmt.Sprintf("%s %d/%d", label, status.ReadyReplicas, status.Replicas))
+	}
+}
+
+// SetSuperseded fades the replicaset into a wireframe once a newer revision of its deployment rolled out
+func (gd *GReplicaSet) SetSuperseded(superseded bool) {
+	gd.superseded = superseded
+	gd.object.Wireframe = superseded
+	if superseded {
+		gd.object.Color = replicaSetSupersededColor
+	} else {
+		gd.object.Color = replicaSetColor
+	}
+}
+
+// revisionOrdinal orders the slots of newer revisions first
+func revisionOrdinal(revision int64) int32 {
+	return -int32(revision)
+}
+
+// refreshRollout supersedes the replicasets of the deployment older than its latest revision, and orders them and
+// their pods newest revision first. As the rollout progresses the pods of the new replicaset line up ahead of the old
+// ones being scaled down.
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) refreshRollout(namespace, deploymentName string) {
+	if len(deploymentName) == 0 {
+		return // not rolled out by a deployment
+	}
+	latest := int64(0)
+	replicaSets := []*GReplicaSet{}
+	podsByReplicaSet := map[string][]*GPod{}
+	for _, gob := range gc.gobjects {
+		switch gd := gob.(type) {
+		case *GDeployment:
+			if gd.namespace == namespace && gd.name == deploymentName {
+				latest = max(latest, gd.status.Revision)
+			}
+		case *GReplicaSet:
+			if gd.namespace == namespace && gd.status.OwnerReferenceName == deploymentName {
+				latest = max(latest, gd.status.Revision)
+				replicaSets = append(replicaSets, gd)
+			}
+		case *GPod:
+			if gd.namespace == namespace && gd.status.OwnerReferenceType == "ReplicaSet" {
+				podsByReplicaSet[gd.status.OwnerReferenceName] = append(podsByReplicaSet[gd.status.OwnerReferenceName], gd)
+			}
+		}
+	}
+	moved := false
+	for _, grs := range replicaSets {
+		grs.SetSuperseded(grs.status.Revision < latest)
+		ordinal := revisionOrdinal(grs.status.Revision)
+		moved = gc.setSlotOrdinal(SlotResource{name: grs.name, namespace: namespace, resource: GREPLICASET}, ordinal) || moved
+		for _, gp := range podsByReplicaSet[grs.name] {
+			moved = gc.setSlotOrdinal(SlotResource{name: gp.name, namespace: namespace, resource: GPOD}, ordinal) || moved
+		}
+	}
+	if moved {
+		gc.applyLayout()
+	}
+}
+
+// podOrdinal orders the pods of a StatefulSet by their ordinal and the pods of a ReplicaSet by its revision
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) podOrdinal(namespace string, status *GPodStatus) int32 {
+	if status.OwnerReferenceType == "ReplicaSet" {
+		owner := SlotResource{name: status.OwnerReferenceName, namespace: namespace, resource: GREPLICASET}
+		if grs, ok := gc.getGObjectFromSlot(owner).(*GReplicaSet); ok {
+			return revisionOrdinal(grs.status.Revision)
+		}
+	}
+	return status.Index
+}
+
 func (gd *GReplicaSet) GetResource() GResource {
 	return GREPLICASET
 }
@@ -74,4 +176,5 @@ func (gd *GReplicaSet) OnClick() {
 }
 
 func (gd *GReplicaSet) SetDeleting() {
+	gd.object.IsDeleting = true
 }
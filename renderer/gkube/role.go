@@ -11,9 +11,11 @@ import (
 )
 
 type GRole struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GRoleStatus
 
 	name      string
 	namespace string
@@ -26,6 +28,7 @@ func (gd *GRole) Create(parent *GCluster, name string, namespace string, offset
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GRoleStatus{}
 
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
@@ -44,6 +47,23 @@ func (gd *GRole) GetResource() GResource {
 	return GROLE
 }
 
+func (gd *GRole) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GRole) SetStatus(status *GRoleStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Heptagon); ok {
+		obj.SetText(fmt.Sprintf("%s (%d rules)", gd.name, len(status.Rules)))
+	}
+}
+
+// GetDetails summarizes the rules of the role
+func (gd *GRole) GetDetails() []string {
+	return SummarizePermissions(scopePolicyRules(gd.status.Rules, gd.namespace))
+}
+
 func (gd *GRole) Delete() {
 
 }
@@ -65,5 +85,5 @@ func (gd *GRole) OnClick() {
 }
 
 func (gd *GRole) SetDeleting() {
-
+	gd.object.IsDeleting = true
 }
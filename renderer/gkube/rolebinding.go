@@ -11,9 +11,14 @@ import (
 )
 
 type GRoleBinding struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GRoleBindingStatus
+	links     map[string]*GLink
+	subjects  []*scene.SceneObject // markers of User and Group subjects
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -25,6 +30,9 @@ func (gd *GRoleBinding) Create(parent *GCluster, name string, namespace string,
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GRoleBindingStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 
 	gpod := &entity.Cube{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
@@ -43,8 +51,35 @@ func (gd *GRoleBinding) GetResource() GResource {
 	return GROLEBINDING
 }
 
-func (gd *GRoleBinding) Delete() {
+func (gd *GRoleBinding) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the label and redraws the markers of the User and Group subjects
+func (gd *GRoleBinding) SetStatus(status *GRoleBindingStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Cube); ok {
+		obj.SetText(fmt.Sprintf("%s -> %s/%s", gd.name, status.RoleKind, status.RoleName))
+	}
+	gd.parent.deleteSubjectMarkers(gd.subjects)
+	gd.subjects = gd.parent.createSubjectMarkers(status.Subjects, gd.shaderID, gd.OnClick)
+}
 
+// UpdateLinks wires the binding to its role and service account subjects, and keeps the other subjects above it
+func (gd *GRoleBinding) UpdateLinks() {
+	role := SlotResource{name: gd.status.RoleName, namespace: gd.namespace, resource: GROLE}
+	if gd.status.RoleKind == "ClusterRole" {
+		role = SlotResource{name: gd.status.RoleName, namespace: CLUSTER_SCOPED_NAMESPACE, resource: GCLUSTERROLE}
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, bindingLinkTargets(role, gd.status.Subjects, gd.namespace))
+	placeSubjectMarkers(gd, gd.subjects)
+}
+
+// removes the links and subject markers from the main scene
+func (gd *GRoleBinding) Delete() {
+	deleteGLinks(gd.links)
+	gd.parent.deleteSubjectMarkers(gd.subjects)
 }
 
 func (gd *GRoleBinding) GetCurrentOffset() *mgl.Vec3 {
@@ -64,5 +99,11 @@ func (gd *GRoleBinding) OnClick() {
 }
 
 func (gd *GRoleBinding) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
+	for _, subject := range gd.subjects {
+		subject.IsDeleting = true
+	}
 }
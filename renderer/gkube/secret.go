@@ -11,25 +11,30 @@ import (
 )
 
 type GSecret struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GSecretStatus
 
 	name          string
 	namespace     string
 	currentOffset *mgl.Vec3
 }
 
+var secretColor = mgl.Vec3{float32(196) / 255, float32(102) / 255, float32(160) / 255}
+
 func (gd *GSecret) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
 	gd.name = name
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GSecretStatus{}
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
 	t := &camera.Transform3D{}
 	t.Init(offset, &mgl.Vec3{1.6, 0.5, 1}, nil, true)
-	gd.object.Init(gpod, t, shaderID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
+	gd.object.Init(gpod, t, shaderID, secretColor, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -42,6 +47,18 @@ func (gd *GSecret) GetResource() GResource {
 	return GSECRET
 }
 
+func (gd *GSecret) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GSecret) SetStatus(status *GSecretStatus) {
+	gd.status = status
+	gd.state = Running
+	if obj, ok := gd.object.Object.(*entity.Heptagon); ok {
+		obj.SetText(fmt.Sprintf("%s (%s, %d keys)", gd.name, status.Type, status.Keys))
+	}
+}
+
 func (gd *GSecret) Delete() {
 
 }
@@ -58,10 +75,15 @@ func (gd *GSecret) GetIdentifier() (string, string) {
 	return gd.name, gd.namespace
 }
 
+// OnClick selects the Secret and highlights every pod consuming it. Click handlers run before the scene toggles
+// the selection, so a Secret that is already selected is being deselected.
 func (gd *GSecret) OnClick() {
 	gd.parent.SetSelected(gd)
+	if !gd.object.OnClick {
+		gd.parent.SetHighlighted(gd.parent.getConsumingPods(GSECRET, gd.name, gd.namespace))
+	}
 }
 
 func (gd *GSecret) SetDeleting() {
-
+	gd.object.IsDeleting = true
 }
@@ -1,6 +1,8 @@
 package gkube
 
 import (
+	"fmt"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -9,9 +11,13 @@ import (
 )
 
 type GService struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GServiceStatus
+	links     map[string]*GLink
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -23,6 +29,9 @@ func (gd *GService) Create(parent *GCluster, name string, namespace string, offs
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GServiceStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 
 	gservice := &entity.WavefrontOBJ{FileName: "service.obj"}
 	gservice.Init(font, "")
@@ -42,7 +51,51 @@ func (gd *GService) GetResource() GResource {
 	return GSERVICE
 }
 
+var (
+	serviceReadyLinkColor    = mgl.Vec3{0.30980392156, 0.78431372549, 0.43137254902}
+	serviceNotReadyLinkColor = mgl.Vec3{0.94901960784, 0.65098039215, 0.16470588235}
+)
+
+func (gd *GService) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetStatus refreshes the label and the endpoints that UpdateLinks draws wires to
+func (gd *GService) SetStatus(status *GServiceStatus) {
+	gd.status = status
+	ready := 0
+	for _, endpoint := range status.Endpoints {
+		if endpoint.Ready {
+			ready++
+		}
+	}
+	if ready == len(status.Endpoints) {
+		gd.state = Running
+	} else {
+		gd.state = Loading
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(fmt.Sprintf("%s (%s) %d/%d", gd.name, status.Type, ready, len(status.Endpoints)))
+	}
+}
+
+// UpdateLinks wires the service to every pod backing it. Ready endpoints are drawn solid, not-ready endpoints as wireframe.
+func (gd *GService) UpdateLinks() {
+	targets := []GLinkTarget{}
+	for _, endpoint := range gd.status.Endpoints {
+		target := GLinkTarget{slot: SlotResource{name: endpoint.PodName, namespace: gd.namespace, resource: GPOD}, color: serviceReadyLinkColor}
+		if !endpoint.Ready {
+			target.color = serviceNotReadyLinkColor
+			target.wireframe = true
+		}
+		targets = append(targets, target)
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
+
+// removes the links to the backing pods from the main scene
 func (gd *GService) Delete() {
+	deleteGLinks(gd.links)
 }
 
 func (gd *GService) GetCurrentOffset() *mgl.Vec3 {
@@ -62,4 +115,8 @@ func (gd *GService) OnClick() {
 }
 
 func (gd *GService) SetDeleting() {
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
 }
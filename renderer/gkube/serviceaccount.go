@@ -11,9 +11,13 @@ import (
 )
 
 type GServiceAccount struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GServiceAccountStatus
+	links     map[string]*GLink
+	shaderID  uint32
 
 	name          string
 	namespace     string
@@ -25,6 +29,9 @@ func (gd *GServiceAccount) Create(parent *GCluster, name string, namespace strin
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
+	gd.status = &GServiceAccountStatus{}
+	gd.links = map[string]*GLink{}
+	gd.shaderID = shaderID
 
 	gpod := &entity.Heptagon{}
 	gpod.Init(font, fmt.Sprintf("%s", name))
@@ -43,8 +50,32 @@ func (gd *GServiceAccount) GetResource() GResource {
 	return GSERVICEACCOUNT
 }
 
-func (gd *GServiceAccount) Delete() {
+func (gd *GServiceAccount) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GServiceAccount) SetStatus(status *GServiceAccountStatus) {
+	gd.status = status
+	gd.state = Running
+}
+
+// UpdateLinks wires the service account to its image pull secrets
+func (gd *GServiceAccount) UpdateLinks() {
+	targets := []GLinkTarget{}
+	for _, secretName := range gd.status.ImagePullSecrets {
+		targets = append(targets, GLinkTarget{slot: SlotResource{name: secretName, namespace: gd.namespace, resource: GSECRET}, color: secretColor, info: "imagePullSecret " + secretName})
+	}
+	gd.parent.syncGLinks(gd, gd.shaderID, gd.links, targets)
+}
 
+// GetDetails lists the effective permissions of the service account
+func (gd *GServiceAccount) GetDetails() []string {
+	return gd.parent.permissionDetails(gd.namespace, gd.name)
+}
+
+// removes the links to the image pull secrets from the main scene
+func (gd *GServiceAccount) Delete() {
+	deleteGLinks(gd.links)
 }
 
 func (gd *GServiceAccount) GetCurrentOffset() *mgl.Vec3 {
@@ -64,5 +95,8 @@ func (gd *GServiceAccount) OnClick() {
 }
 
 func (gd *GServiceAccount) SetDeleting() {
-
+	gd.object.IsDeleting = true
+	for _, link := range gd.links {
+		link.SetDeleting()
+	}
 }
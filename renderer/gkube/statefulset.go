@@ -29,9 +29,10 @@ const (
 )
 
 type GStatefulSet struct {
-	parent *GCluster
-	object *scene.SceneObject
-	state  State
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
 
 	name          string
 	namespace     string
@@ -43,11 +44,11 @@ func (gd *GStatefulSet) Create(parent *GCluster, name string, namespace string,
 	gd.namespace = namespace
 	gd.parent = parent
 	gd.object = &scene.SceneObject{}
-	gstatefulsetCube := &entity.Cube{}
-	gstatefulsetCube.Init(font, fmt.Sprintf("%s", name))
+	gstatefulset := &entity.WavefrontOBJ{FileName: "statefulset.obj"}
+	gstatefulset.Init(font, "")
 	t := &camera.Transform3D{}
-	t.Init(offset, &mgl.Vec3{3, 3, 3}, nil, true)
-	gd.object.Init(gstatefulsetCube, t, shaderID, mgl.Vec3{111, 111, 111}, mgl.Vec3{1, 1, 1})
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(gstatefulset, t, shaderID, mgl.Vec3{float32(153) / 255, float32(115) / 255, float32(229) / 255}, mgl.Vec3{1, 1, 1})
 	gd.object.AddOnClickHandler(gd.OnClick)
 
 	gd.currentOffset = offset
@@ -56,6 +57,22 @@ func (gd *GStatefulSet) Create(parent *GCluster, name string, namespace string,
 	return gd.object
 }
 
+func (gd *GStatefulSet) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+// SetReplicas refreshes the state and the ready/desired label
+func (gd *GStatefulSet) SetReplicas(readyReplicas, replicas int32) {
+	if readyReplicas == replicas {
+		gd.state = Running
+	} else {
+		gd.state = Loading
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(fmt.Sprintf("%s %d/%d", gd.name, readyReplicas, replicas))
+	}
+}
+
 func (gd *GStatefulSet) GetResource() GResource {
 	return GSTATEFULSET
 }
@@ -81,5 +98,5 @@ func (gd *GStatefulSet) OnClick() {
 }
 
 func (gd *GStatefulSet) SetDeleting() {
-
+	gd.object.IsDeleting = true
 }
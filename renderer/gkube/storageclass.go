@@ -0,0 +1,88 @@
+package gkube
+
+import (
+	"fmt"
+
+	v41 "github.com/4ydx/gltext/v4.1"
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/camera"
+	"github.com/kabicin/kubechaser/renderer/entity"
+	"github.com/kabicin/kubechaser/renderer/scene"
+)
+
+type GStorageClass struct {
+	parent    *GCluster
+	object    *scene.SceneObject
+	state     State
+	kubeState map[string]interface{}
+	status    *GStorageClassStatus
+
+	name          string
+	namespace     string
+	currentOffset *mgl.Vec3
+}
+
+func (gd *GStorageClass) Create(parent *GCluster, name string, namespace string, offset *mgl.Vec3, font *v41.Font, shaderID uint32, settings GSettings, hideText bool) *scene.SceneObject {
+	gd.name = name
+	gd.namespace = namespace
+	gd.parent = parent
+	gd.object = &scene.SceneObject{}
+	gd.status = &GStorageClassStatus{}
+
+	gstorageclass := &entity.WavefrontOBJ{FileName: "storageclass.obj"}
+	gstorageclass.Init(font, "")
+
+	t := &camera.Transform3D{}
+	t.Init(offset, &mgl.Vec3{1, 1, 1}, nil, true)
+	gd.object.Init(gstorageclass, t, shaderID, mgl.Vec3{float32(160) / 255, float32(170) / 255, float32(229) / 255}, mgl.Vec3{1, 1, 1})
+	gd.object.AddOnClickHandler(gd.OnClick)
+
+	gd.currentOffset = offset
+
+	gd.parent.mainScene.AddObject(gd.object)
+	return gd.object
+}
+
+func (gd *GStorageClass) GetResource() GResource {
+	return GSTORAGECLASS
+}
+
+func (gd *GStorageClass) SetKubeState(kubeState map[string]interface{}) {
+	gd.kubeState = kubeState
+}
+
+func (gd *GStorageClass) SetStatus(status *GStorageClassStatus) {
+	gd.status = status
+	gd.state = Running
+	label := fmt.Sprintf("%s (%s) %s %s", gd.name, status.Provisioner, status.ReclaimPolicy, status.VolumeBindingMode)
+	if status.IsDefault {
+		label += " [default]"
+	}
+	if obj, ok := gd.object.Object.(*entity.WavefrontOBJ); ok {
+		obj.SetText(label)
+	}
+}
+
+func (gd *GStorageClass) Delete() {
+
+}
+
+func (gd *GStorageClass) GetCurrentOffset() *mgl.Vec3 {
+	return gd.currentOffset
+}
+
+func (gd *GStorageClass) GetObject() *scene.SceneObject {
+	return gd.object
+}
+
+func (gd *GStorageClass) GetIdentifier() (string, string) {
+	return gd.name, gd.namespace
+}
+
+func (gd *GStorageClass) OnClick() {
+	gd.parent.SetSelected(gd)
+}
+
+func (gd *GStorageClass) SetDeleting() {
+	gd.object.IsDeleting = true
+}
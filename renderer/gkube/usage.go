@@ -0,0 +1,169 @@
+package gkube
+
+import (
+	"fmt"
+	"strings"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+)
+
+// pods glow once the memory usage of one of their containers reaches this share of its memory limit, before it is
+// OOM-killed
+const memoryLimitGlowRatio = 0.9
+
+var (
+	usageHeatColor       = mgl.Vec3{0.89803921568, 0.19607843137, 0.19607843137}
+	memoryLimitGlowColor = mgl.Vec3{1, 0.2, 0.8}
+)
+
+func resourceUsageKey(namespace, name string) string {
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// usageHeat is how close used runs to the limit, or to the request when there is no limit, from 0 to 1. Usage without
+// either is not heated.
+func usageHeat(used, request, limit int64) float32 {
+	reference := limit
+	if reference == 0 {
+		reference = request
+	}
+	if reference <= 0 {
+		return 0
+	}
+	return mgl.Clamp(float32(used)/float32(reference), 0, 1)
+}
+
+// heatColor shades color towards usageHeatColor by heat
+func heatColor(color mgl.Vec3, heat float32) mgl.Vec3 {
+	return color.Add(usageHeatColor.Sub(color).Mul(heat))
+}
+
+func formatCPU(millicores int64) string {
+	return fmt.Sprintf("%dm", millicores)
+}
+
+func formatMemory(bytes int64) string {
+	return fmt.Sprintf("%dMi", bytes/(1024*1024))
+}
+
+// formatUsage describes used against its request and limit, i.e. "memory 96Mi (request 64Mi, limit 128Mi)"
+func formatUsage(name string, used, request, limit int64, format func(int64) string) string {
+	bounds := []string{}
+	if request > 0 {
+		bounds = append(bounds, "request "+format(request))
+	}
+	if limit > 0 {
+		bounds = append(bounds, "limit "+format(limit))
+	}
+	if len(bounds) == 0 {
+		return fmt.Sprintf("%s %s", name, format(used))
+	}
+	return fmt.Sprintf("%s %s (%s)", name, format(used), strings.Join(bounds, ", "))
+}
+
+// SetUsage attaches the polled usage of the pod and of its containers, nil when metrics.k8s.io no longer reports it
+func (gd *GPod) SetUsage(usage *GResourceUsage, containers map[string]GResourceUsage) {
+	gd.usage = usage
+	gd.containerUsage = containers
+	gd.applyUsageColor()
+	if color, found := gd.pulseColor(); found {
+		gd.object.Pulse(color)
+	}
+}
+
+// applyUsageColor heats a running pod towards usageHeatColor as it runs close to its limits
+func (gd *GPod) applyUsageColor() {
+	gd.object.Color = podLifecycleVisuals[podLifecycle(gd.status)].color
+	if gd.state != Running || gd.usage == nil {
+		return
+	}
+	heat := max(
+		usageHeat(gd.usage.CPU, gd.status.Requests.CPU, gd.status.Limits.CPU),
+		usageHeat(gd.usage.Memory, gd.status.Requests.Memory, gd.status.Limits.Memory),
+	)
+	gd.object.Color = heatColor(gd.object.Color, heat)
+}
+
+// containersNearMemoryLimit lists the containers using close to their own memory limit, which is what they are
+// OOM-killed by
+func (gd *GPod) containersNearMemoryLimit() []GContainerStatus {
+	near := []GContainerStatus{}
+	if gd.usage == nil {
+		return near
+	}
+	for _, container := range podContainerStatuses(gd.status) {
+		used, found := gd.containerUsage[container.Name]
+		if found && container.Limits.Memory > 0 && float64(used.Memory) >= memoryLimitGlowRatio*float64(container.Limits.Memory) {
+			near = append(near, container)
+		}
+	}
+	return near
+}
+
+func (gd *GPod) nearMemoryLimit() bool {
+	return len(gd.containersNearMemoryLimit()) > 0
+}
+
+func (gd *GPod) usageDetails() []string {
+	if gd.usage == nil {
+		return []string{}
+	}
+	details := []string{"usage: " + strings.Join([]string{
+		formatUsage("cpu", gd.usage.CPU, gd.status.Requests.CPU, gd.status.Limits.CPU, formatCPU),
+		formatUsage("memory", gd.usage.Memory, gd.status.Requests.Memory, gd.status.Limits.Memory, formatMemory),
+	}, ", ")}
+	for _, container := range gd.containersNearMemoryLimit() {
+		details = append(details, fmt.Sprintf("container %s near its memory limit: %s of %s",
+			container.Name, formatMemory(gd.containerUsage[container.Name].Memory), formatMemory(container.Limits.Memory)))
+	}
+	return details
+}
+
+// SetUsage attaches the polled usage of the node, nil when metrics.k8s.io no longer reports it
+func (gd *GNode) SetUsage(usage *GResourceUsage) {
+	gd.usage = usage
+	gd.applyUsageColor()
+}
+
+// applyUsageColor heats a healthy node towards usageHeatColor as its pods use up what is allocatable
+func (gd *GNode) applyUsageColor() {
+	gd.object.Color = nodeStateColors[gd.state]
+	if gd.state != Running || gd.usage == nil {
+		return
+	}
+	heat := max(
+		usageHeat(gd.usage.CPU, 0, gd.status.Allocatable.CPU),
+		usageHeat(gd.usage.Memory, 0, gd.status.Allocatable.Memory),
+	)
+	gd.object.Color = heatColor(gd.object.Color, heat)
+}
+
+func (gd *GNode) usageDetails() []string {
+	if gd.usage == nil {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("usage: cpu %s of %s, memory %s of %s allocatable",
+		formatCPU(gd.usage.CPU), formatCPU(gd.status.Allocatable.CPU), formatMemory(gd.usage.Memory), formatMemory(gd.status.Allocatable.Memory))}
+}
+
+// setResourceUsage hands every pod and node its usage from the poll
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) setResourceUsage(status *GResourceUsageStatus) {
+	for _, gob := range gc.gobjects {
+		switch gd := gob.(type) {
+		case *GPod:
+			key := resourceUsageKey(gd.namespace, gd.name)
+			if usage, found := status.Pods[key]; found {
+				gd.SetUsage(&usage, status.Containers[key])
+			} else {
+				gd.SetUsage(nil, nil)
+			}
+		case *GNode:
+			if usage, found := status.Nodes[gd.name]; found {
+				gd.SetUsage(&usage)
+			} else {
+				gd.SetUsage(nil)
+			}
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package gkube
 
 import (
+	"math"
+
 	v41 "github.com/4ydx/gltext/v4.1"
 	mgl "github.com/go-gl/mathgl/mgl32"
 	"github.com/kabicin/kubechaser/renderer/camera"
@@ -111,3 +113,165 @@ func (gd *GWire) OnClick() {
 func (gd *GWire) SetDeleting() {
 
 }
+
+// GLinkedObject is implemented by GOBJECTs that draw GLinks to other GOBJECTs, or other scene objects that follow them.
+// UpdateLinks is polled from the main loop while the cluster holds its gobject lock.
+type GLinkedObject interface {
+	GObject
+	UpdateLinks()
+}
+
+const glinkThickness = float32(0.15)
+
+var glinkAxes = []int{0, 2, 1} // x, z, y
+
+// GLink is a wire between two GOBJECTs, routed as three axis-aligned segments (x, then z, then y) so that it
+// follows both ends while they are animated between slots
+type GLink struct {
+	parent   *GCluster
+	from     GObject
+	to       GObject
+	segments []*scene.SceneObject
+	info     string
+}
+
+func (gl *GLink) Create(parent *GCluster, from, to GObject, font *v41.Font, shaderID uint32, color mgl.Vec3, wireframe bool) {
+	gl.parent = parent
+	gl.from = from
+	gl.to = to
+	gl.segments = make([]*scene.SceneObject, 3)
+	for i := range gl.segments {
+		segment := &entity.Cube{}
+		segment.Init(font, "")
+		t := &camera.Transform3D{}
+		t.Init(&mgl.Vec3{0, 0, 0}, &mgl.Vec3{glinkThickness, glinkThickness, glinkThickness}, nil, false)
+		gl.segments[i] = &scene.SceneObject{}
+		gl.segments[i].Init(segment, t, shaderID, color, mgl.Vec3{1, 1, 1})
+		gl.segments[i].AddOnClickHandler(from.OnClick)
+		gl.segments[i].AddOnHoverHandler(gl.OnHover)
+		gl.parent.mainScene.AddObject(gl.segments[i])
+	}
+	gl.SetStyle(color, wireframe)
+	gl.Route()
+}
+
+func (gl *GLink) GetTo() GObject {
+	return gl.to
+}
+
+func (gl *GLink) SetStyle(color mgl.Vec3, wireframe bool) {
+	for _, segment := range gl.segments {
+		segment.Color = color
+		segment.Wireframe = wireframe
+	}
+}
+
+// SetInfo sets the text shown while the link is hovered
+func (gl *GLink) SetInfo(info string) {
+	gl.info = info
+}
+
+// OnHover shows the link info above the middle segment while any segment is hovered
+func (gl *GLink) OnHover(hovering bool) {
+	segment, ok := gl.segments[1].Object.(*entity.Cube)
+	if !ok {
+		return
+	}
+	if hovering {
+		segment.SetText(gl.info)
+	} else {
+		segment.SetText("")
+	}
+}
+
+// current position of a GOBJECT, including any slot animation in progress
+func glinkEndpoint(gob GObject) mgl.Vec3 {
+	if object := gob.GetObject(); object != nil && object.Transform != nil && object.Transform.PositionAnimator.X_init != nil {
+		return *object.Transform.PositionAnimator.X_init
+	}
+	return *gob.GetCurrentOffset()
+}
+
+// Route moves the segments onto the current positions of both ends
+func (gl *GLink) Route() {
+	a := glinkEndpoint(gl.from)
+	b := glinkEndpoint(gl.to)
+	corners := []mgl.Vec3{
+		a,
+		{b.X(), a.Y(), a.Z()},
+		{b.X(), a.Y(), b.Z()},
+		b,
+	}
+	for i, segment := range gl.segments {
+		start := corners[i]
+		end := corners[i+1]
+		center := start.Add(end).Mul(0.5)
+		axis := glinkAxes[i]
+		scale := mgl.Vec3{glinkThickness, glinkThickness, glinkThickness}
+		scale[axis] += float32(math.Abs(float64(end[axis] - start[axis])))
+		*segment.Transform.PositionAnimator.X_init = center
+		*segment.Transform.PositionAnimator.X_final = center
+		*segment.Transform.Scale = scale
+	}
+}
+
+func (gl *GLink) SetDeleting() {
+	for _, segment := range gl.segments {
+		segment.IsDeleting = true
+	}
+}
+
+// Delete removes every segment from the main scene
+func (gl *GLink) Delete() {
+	for _, segment := range gl.segments {
+		gl.parent.mainScene.DeleteObject(segment)
+	}
+}
+
+// GLinkTarget describes a link that should exist from a GOBJECT to the GOBJECT occupying slot
+type GLinkTarget struct {
+	slot      SlotResource
+	color     mgl.Vec3
+	wireframe bool
+	info      string // shown while the link is hovered
+}
+
+// syncGLinks creates, restyles and re-routes the links from a GOBJECT to its targets, and deletes links to targets that
+// are gone. Targets that have not been drawn yet are skipped until a later call.
+// pre-condition: already has lock on gobjects
+func (gc *GCluster) syncGLinks(from GObject, shaderID uint32, links map[string]*GLink, targets []GLinkTarget) {
+	seen := map[string]bool{}
+	for _, target := range targets {
+		sig := target.slot.GetSignature()
+		to := gc.getGObjectFromSlot(target.slot)
+		if to == nil {
+			continue
+		}
+		seen[sig] = true
+		link, found := links[sig]
+		if !found || link.GetTo() != to {
+			if found {
+				link.Delete()
+			}
+			link = &GLink{}
+			link.Create(gc, from, to, gc.font, shaderID, target.color, target.wireframe)
+			links[sig] = link
+		}
+		link.SetStyle(target.color, target.wireframe)
+		link.SetInfo(target.info)
+		link.Route()
+	}
+	for sig, link := range links {
+		if !seen[sig] {
+			link.Delete()
+			delete(links, sig)
+		}
+	}
+}
+
+func deleteGLinks(links map[string]*GLink) {
+	for sig, link := range links {
+		link.Delete()
+		delete(links, sig)
+	}
+}
@@ -15,6 +15,8 @@ type Scene struct {
 	Objects    []*SceneObject
 	Shaders    []*shader.Program
 	MainCamera *camera.Camera
+
+	hovered *SceneObject
 }
 
 func (s *Scene) Init(shaders []*shader.Program, objects []*SceneObject, mainCamera *camera.Camera) error {
@@ -41,12 +43,8 @@ func (s *Scene) bindObjectsToShaderPrograms(objects ...*SceneObject) {
 	}
 }
 
-func (s *Scene) Click(r *camera.Ray) {
-	debug := false
-	if debug {
-		log.Printf("Clicking in the scene at E(%f,%f,%f) and D(%f,%f,%f)\n", r.Eye.X(), r.Eye.Y(), r.Eye.Z(), r.Direction.X(), r.Direction.Y(), r.Direction.Z())
-	}
-
+// intersect returns the index of the closest object hit by the ray, or -1
+func (s *Scene) intersect(r *camera.Ray, debug bool) (int, float64) {
 	minT := float64(0)
 	minNormSquared := float32(math.MaxFloat32)
 	minObjectIndex := -1
@@ -65,13 +63,40 @@ func (s *Scene) Click(r *camera.Ray) {
 			minNormSquared = normSquared
 		}
 	}
+	return minObjectIndex, minT
+}
+
+func (s *Scene) Click(r *camera.Ray) {
+	debug := false
+	if debug {
+		log.Printf("Clicking in the scene at E(%f,%f,%f) and D(%f,%f,%f)\n", r.Eye.X(), r.Eye.Y(), r.Eye.Z(), r.Direction.X(), r.Direction.Y(), r.Direction.Z())
+	}
 
+	minObjectIndex, minT := s.intersect(r, debug)
 	if minObjectIndex != -1 {
 		log.Printf("Intersect with %s t=%f\n", s.Objects[minObjectIndex].Object.GetName(), minT)
 		s.Objects[minObjectIndex].NotifyOnClickHandlers()
 	}
 }
 
+// Hover notifies the object under the ray that it is hovered, and the previously hovered object that it no longer is
+func (s *Scene) Hover(r *camera.Ray) {
+	var hovered *SceneObject
+	if minObjectIndex, _ := s.intersect(r, false); minObjectIndex != -1 {
+		hovered = s.Objects[minObjectIndex]
+	}
+	if hovered == s.hovered {
+		return
+	}
+	if s.hovered != nil {
+		s.hovered.NotifyOnHoverHandlers(false)
+	}
+	if hovered != nil {
+		hovered.NotifyOnHoverHandlers(true)
+	}
+	s.hovered = hovered
+}
+
 func (s *Scene) Draw(deltaT float32) {
 	for _, object := range s.Objects {
 		if !object.RenderReady {
@@ -128,6 +153,9 @@ func (s *Scene) Draw(deltaT float32) {
 		if obj.Object.GetName() == "Pyramid" {
 			(obj.Object.(*entity.Pyramid)).DrawText(&model, cameraRay, s.MainCamera)
 		}
+		if obj.Object.GetName() == "WavefrontOBJ" {
+			(obj.Object.(*entity.WavefrontOBJ)).DrawText(&model, cameraRay, s.MainCamera)
+		}
 	}
 }
 
@@ -158,6 +186,9 @@ func (s *Scene) DeleteObject(object *SceneObject) error {
 	}
 	if deleteIndex != -1 {
 		s.Objects = append(s.Objects[:deleteIndex], s.Objects[deleteIndex+1:]...)
+		if s.hovered == object {
+			s.hovered = nil
+		}
 		return nil
 	}
 	return fmt.Errorf("could not delete scene object; pointer to object was not found")
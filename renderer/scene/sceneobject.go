@@ -18,11 +18,14 @@ type SceneObject struct {
 	Object              entity.Entity
 	Transform           *camera.Transform3D
 	DeleteColorAnimator camera.ColorAnimator
+	PulseColorAnimator  camera.ColorAnimator
 
 	// Scene state
 	RenderReady   bool
 	IsDeleting    bool
 	IsDeleteReady bool
+	IsPulsing     bool
+	PulseColor    mgl.Vec3
 
 	// Shader
 	ShaderProgramID          *uint32
@@ -30,6 +33,7 @@ type SceneObject struct {
 	IgnoreLights             bool
 	IsLightObject            bool
 	ClickHandlers            []func()
+	HoverHandlers            []func(hovering bool)
 
 	// Draw attribs
 	Color        mgl.Vec3
@@ -84,6 +88,20 @@ func (so *SceneObject) Init(ent entity.Entity, transform *camera.Transform3D, sh
 	}()
 }
 
+// Pulse swings the color back and forth to pulseColor until StopPulse is called, or until it pulses to another color
+func (so *SceneObject) Pulse(pulseColor mgl.Vec3) {
+	if so.IsPulsing && so.PulseColor == pulseColor {
+		return
+	}
+	so.PulseColor = pulseColor
+	so.PulseColorAnimator = camera.InitColorAnimator(&so.Color, &pulseColor, -1)
+	so.IsPulsing = true
+}
+
+func (so *SceneObject) StopPulse() {
+	so.IsPulsing = false
+}
+
 func (so *SceneObject) SetLightObject(isLightObject bool) {
 	so.IsLightObject = isLightObject
 }
@@ -96,6 +114,16 @@ func (so *SceneObject) AddOnClickHandler(f func()) {
 	so.ClickHandlers = append(so.ClickHandlers, f)
 }
 
+func (so *SceneObject) AddOnHoverHandler(f func(hovering bool)) {
+	so.HoverHandlers = append(so.HoverHandlers, f)
+}
+
+func (so *SceneObject) NotifyOnHoverHandlers(hovering bool) {
+	for _, hoverHandler := range so.HoverHandlers {
+		hoverHandler(hovering)
+	}
+}
+
 func (so *SceneObject) NotifyOnClickHandlers() {
 	for _, clickHandler := range so.ClickHandlers {
 		clickHandler()
@@ -141,6 +169,8 @@ func (s *SceneObject) Draw(deltaT float32, transform *camera.Transform3D, progra
 	} else {
 		if transform.PositionAnimator.InMotion {
 			color = mgl.Vec3{240, 230, 140}
+		} else if s.IsPulsing {
+			color = *s.PulseColorAnimator.Animate(deltaT, func() {})
 		} else {
 			color = s.Color
 		}
@@ -0,0 +1,30 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// NextScheduledRuns returns the next n times after from at which the standard cron schedule fires, evaluated in timeZone
+// when it is set (i.e. the spec.schedule and spec.timeZone of a CronJob)
+func NextScheduledRuns(schedule, timeZone string, from time.Time, n int) ([]time.Time, error) {
+	if len(timeZone) > 0 {
+		schedule = fmt.Sprintf("CRON_TZ=%s %s", timeZone, schedule)
+	}
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, err
+	}
+	runs := []time.Time{}
+	next := from
+	for range n {
+		next = parsed.Next(next)
+		if next.IsZero() {
+			break // the schedule never fires again
+		}
+		runs = append(runs, next)
+	}
+	return runs, nil
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -74,3 +75,26 @@ func Test_DuplicateOrderedMap(t *testing.T) {
 		{obj1hash, obj2hash},
 	})
 }
+
+func Test_NextScheduledRuns(t *testing.T) {
+	from := time.Date(2024, time.March, 1, 10, 7, 0, 0, time.UTC)
+	every15, err := NextScheduledRuns("*/15 * * * *", "", from, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	daily, err := NextScheduledRuns("@daily", "", from, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zoned, err := NextScheduledRuns("0 9 * * *", "Asia/Tokyo", from, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, invalidErr := NextScheduledRuns("not a schedule", "", from, 1)
+	checkTests(t, []Test{
+		{every15, []time.Time{from.Add(8 * time.Minute), from.Add(23 * time.Minute), from.Add(38 * time.Minute)}},
+		{daily, []time.Time{time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC), time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC)}},
+		{zoned[0].UTC(), time.Date(2024, time.March, 2, 0, 0, 0, 0, time.UTC)},
+		{invalidErr != nil, true},
+	})
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ClusterRolePoint struct {
+	WatchPoint
+}
+
+func (p *ClusterRolePoint) String() string {
+	return fmt.Sprintf("ClusterRole %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *ClusterRolePoint) Init(obj *rbacv1.ClusterRole) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseClusterRolePoint(d *rbacv1.ClusterRole) *ClusterRolePoint {
+	p := &ClusterRolePoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseClusterRole(rawClusterRole map[string]interface{}) (*rbacv1.ClusterRole, error) {
+	ns := &rbacv1.ClusterRole{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawClusterRole, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateClusterRoleStatus copies the policy rules granted by the cluster role, including the rules aggregated into it
+func CreateClusterRoleStatus(clusterRole *rbacv1.ClusterRole) *gkube.GClusterRoleStatus {
+	return &gkube.GClusterRoleStatus{
+		Rules: convertPolicyRules(clusterRole.Rules),
+	}
+}
+
+func (watcher *Watcher) WatchClusterRoles(ctx context.Context) {
+	informer := rbacinformers.NewClusterRoleInformer(watcher.Client, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "clusterroles", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onClusterRoleAdded,
+		UpdateFunc: watcher.onClusterRoleModified,
+		DeleteFunc: watcher.onClusterRoleDeleted,
+	})
+}
+
+func (watcher *Watcher) onClusterRoleAdded(obj interface{}) {
+	clusterRole, ok := obj.(*rbacv1.ClusterRole)
+	if !ok {
+		return
+	}
+	rawClusterRole, err := watcher.ToUnstructuredSync(clusterRole)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	clusterRoleName := clusterRole.GetName()
+	key := pointKey(clusterRole.Namespace, clusterRoleName)
+	_, found := watcher.ClusterRolePoints.Load(key)
+	if !found {
+		// add cluster role point
+		watcher.ClusterRolePoints.Store(key, ParseClusterRolePoint(clusterRole))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCLUSTERROLE, clusterRoleName, clusterRole.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleStatus(clusterRole), -1, rawClusterRole)
+		log.Println("ADDED cluster role " + clusterRoleName)
+	}
+}
+
+func (watcher *Watcher) onClusterRoleModified(oldObj, newObj interface{}) {
+	clusterRole, ok := newObj.(*rbacv1.ClusterRole)
+	if !ok {
+		return
+	}
+
+	clusterRoleName := clusterRole.GetName()
+	key := pointKey(clusterRole.Namespace, clusterRoleName)
+	point, found := watcher.ClusterRolePoints.Load(key)
+	if !found {
+		watcher.onClusterRoleAdded(clusterRole)
+		return
+	}
+	if point.(*ClusterRolePoint).ResourceVersion == clusterRole.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawClusterRole, err := watcher.ToUnstructuredSync(clusterRole)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify cluster role point
+	watcher.ClusterRolePoints.Store(key, ParseClusterRolePoint(clusterRole))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GCLUSTERROLE, clusterRoleName, clusterRole.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleStatus(clusterRole), -1, rawClusterRole)
+	log.Println("MODIFIED cluster role " + clusterRoleName)
+}
+
+func (watcher *Watcher) onClusterRoleDeleted(obj interface{}) {
+	clusterRole, ok := unwrapTombstone(obj).(*rbacv1.ClusterRole)
+	if !ok {
+		return
+	}
+	clusterRoleName := clusterRole.GetName()
+	key := pointKey(clusterRole.Namespace, clusterRoleName)
+	_, found := watcher.ClusterRolePoints.Load(key)
+	if found {
+		// delete cluster role point
+		watcher.ClusterRolePoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GCLUSTERROLE, clusterRoleName, clusterRole.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleStatus(clusterRole), -1, nil)
+		log.Println("DELETED cluster role " + clusterRoleName)
+	}
+}
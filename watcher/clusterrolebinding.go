@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ClusterRoleBindingPoint struct {
+	WatchPoint
+}
+
+func (p *ClusterRoleBindingPoint) String() string {
+	return fmt.Sprintf("ClusterRoleBinding %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *ClusterRoleBindingPoint) Init(obj *rbacv1.ClusterRoleBinding) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseClusterRoleBindingPoint(d *rbacv1.ClusterRoleBinding) *ClusterRoleBindingPoint {
+	p := &ClusterRoleBindingPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseClusterRoleBinding(rawClusterRoleBinding map[string]interface{}) (*rbacv1.ClusterRoleBinding, error) {
+	ns := &rbacv1.ClusterRoleBinding{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawClusterRoleBinding, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func CreateClusterRoleBindingStatus(clusterRoleBinding *rbacv1.ClusterRoleBinding) *gkube.GClusterRoleBindingStatus {
+	return &gkube.GClusterRoleBindingStatus{
+		RoleName: clusterRoleBinding.RoleRef.Name,
+		Subjects: convertSubjects(clusterRoleBinding.Subjects),
+	}
+}
+
+func (watcher *Watcher) WatchClusterRoleBindings(ctx context.Context) {
+	informer := rbacinformers.NewClusterRoleBindingInformer(watcher.Client, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "clusterrolebindings", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onClusterRoleBindingAdded,
+		UpdateFunc: watcher.onClusterRoleBindingModified,
+		DeleteFunc: watcher.onClusterRoleBindingDeleted,
+	})
+}
+
+func (watcher *Watcher) onClusterRoleBindingAdded(obj interface{}) {
+	clusterRoleBinding, ok := obj.(*rbacv1.ClusterRoleBinding)
+	if !ok {
+		return
+	}
+	rawClusterRoleBinding, err := watcher.ToUnstructuredSync(clusterRoleBinding)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	clusterRoleBindingName := clusterRoleBinding.GetName()
+	key := pointKey(clusterRoleBinding.Namespace, clusterRoleBindingName)
+	_, found := watcher.ClusterRoleBindingPoints.Load(key)
+	if !found {
+		// add cluster role binding point
+		watcher.ClusterRoleBindingPoints.Store(key, ParseClusterRoleBindingPoint(clusterRoleBinding))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCLUSTERROLEBINDING, clusterRoleBindingName, clusterRoleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleBindingStatus(clusterRoleBinding), -1, rawClusterRoleBinding)
+		log.Println("ADDED cluster role binding " + clusterRoleBindingName)
+	}
+}
+
+func (watcher *Watcher) onClusterRoleBindingModified(oldObj, newObj interface{}) {
+	clusterRoleBinding, ok := newObj.(*rbacv1.ClusterRoleBinding)
+	if !ok {
+		return
+	}
+
+	clusterRoleBindingName := clusterRoleBinding.GetName()
+	key := pointKey(clusterRoleBinding.Namespace, clusterRoleBindingName)
+	point, found := watcher.ClusterRoleBindingPoints.Load(key)
+	if !found {
+		watcher.onClusterRoleBindingAdded(clusterRoleBinding)
+		return
+	}
+	if point.(*ClusterRoleBindingPoint).ResourceVersion == clusterRoleBinding.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawClusterRoleBinding, err := watcher.ToUnstructuredSync(clusterRoleBinding)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify cluster role binding point
+	watcher.ClusterRoleBindingPoints.Store(key, ParseClusterRoleBindingPoint(clusterRoleBinding))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GCLUSTERROLEBINDING, clusterRoleBindingName, clusterRoleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleBindingStatus(clusterRoleBinding), -1, rawClusterRoleBinding)
+	log.Println("MODIFIED cluster role binding " + clusterRoleBindingName)
+}
+
+func (watcher *Watcher) onClusterRoleBindingDeleted(obj interface{}) {
+	clusterRoleBinding, ok := unwrapTombstone(obj).(*rbacv1.ClusterRoleBinding)
+	if !ok {
+		return
+	}
+	clusterRoleBindingName := clusterRoleBinding.GetName()
+	key := pointKey(clusterRoleBinding.Namespace, clusterRoleBindingName)
+	_, found := watcher.ClusterRoleBindingPoints.Load(key)
+	if found {
+		// delete cluster role binding point
+		watcher.ClusterRoleBindingPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GCLUSTERROLEBINDING, clusterRoleBindingName, clusterRoleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateClusterRoleBindingStatus(clusterRoleBinding), -1, nil)
+		log.Println("DELETED cluster role binding " + clusterRoleBindingName)
+	}
+}
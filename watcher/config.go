@@ -0,0 +1,59 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultConfigPath is read at startup unless another file is passed with -config
+const DefaultConfigPath = "kubechaser.yaml"
+
+// WatchConfig configures what a Watcher watches beyond the built-in resources, and where, i.e.
+//
+//	customResources:
+//	  - kafkas.v1beta2.kafka.strimzi.io
+//	  - certificates.v1.cert-manager.io
+//	scope:
+//	  namespaces: ["team-*"]
+//	contexts: ["staging", "production"]
+type WatchConfig struct {
+	// resources watched through the dynamic client, as resource.version.group
+	CustomResources []string `yaml:"customResources"`
+	// namespaces and workloads to watch, everything when empty
+	Scope WatchScope `yaml:"scope"`
+	// kubeconfig contexts drawn side by side, the current context when empty
+	Contexts []string `yaml:"contexts"`
+}
+
+// LoadWatchConfig reads the config at path; a missing file yields an empty config
+func LoadWatchConfig(path string) (*WatchConfig, error) {
+	config := &WatchConfig{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// CustomResourceGVRs parses the configured custom resources
+func (config *WatchConfig) CustomResourceGVRs() ([]schema.GroupVersionResource, error) {
+	gvrs := []schema.GroupVersionResource{}
+	for _, arg := range config.CustomResources {
+		gvr, _ := schema.ParseResourceArg(arg)
+		if gvr == nil {
+			return nil, fmt.Errorf("custom resource %q must be given as resource.version.group", arg)
+		}
+		gvrs = append(gvrs, *gvr)
+	}
+	return gvrs, nil
+}
@@ -0,0 +1,125 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ConfigMapPoint struct {
+	WatchPoint
+}
+
+func (p *ConfigMapPoint) String() string {
+	return fmt.Sprintf("ConfigMap %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *ConfigMapPoint) Init(obj *v1.ConfigMap) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseConfigMapPoint(d *v1.ConfigMap) *ConfigMapPoint {
+	p := &ConfigMapPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseConfigMap(rawConfigMap map[string]interface{}) (*v1.ConfigMap, error) {
+	ns := &v1.ConfigMap{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawConfigMap, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func (watcher *Watcher) CreateConfigMapStatus(configMap *v1.ConfigMap) *gkube.GConfigMapStatus {
+	return &gkube.GConfigMapStatus{
+		Keys:        len(configMap.Data) + len(configMap.BinaryData),
+		CustomOwner: watcher.customOwner(configMap.Namespace, configMap.OwnerReferences),
+	}
+}
+
+func (watcher *Watcher) WatchConfigMaps(ctx context.Context, nsName string) {
+	informer := coreinformers.NewConfigMapInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "configmaps", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onConfigMapAdded,
+		UpdateFunc: watcher.onConfigMapModified,
+		DeleteFunc: watcher.onConfigMapDeleted,
+	})
+}
+
+func (watcher *Watcher) onConfigMapAdded(obj interface{}) {
+	configMap, ok := obj.(*v1.ConfigMap)
+	if !ok {
+		return
+	}
+	rawConfigMap, err := watcher.ToUnstructuredSync(configMap)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	configMapName := configMap.GetName()
+	key := pointKey(configMap.Namespace, configMapName)
+	_, found := watcher.ConfigMapPoints.Load(key)
+	if !found {
+		// add configmap point
+		watcher.ConfigMapPoints.Store(key, ParseConfigMapPoint(configMap))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCONFIGMAP, configMapName, configMap.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateConfigMapStatus(configMap), -1, rawConfigMap)
+		log.Println("ADDED configmap " + configMapName)
+	}
+}
+
+func (watcher *Watcher) onConfigMapModified(oldObj, newObj interface{}) {
+	configMap, ok := newObj.(*v1.ConfigMap)
+	if !ok {
+		return
+	}
+
+	configMapName := configMap.GetName()
+	key := pointKey(configMap.Namespace, configMapName)
+	point, found := watcher.ConfigMapPoints.Load(key)
+	if !found {
+		watcher.onConfigMapAdded(configMap)
+		return
+	}
+	if point.(*ConfigMapPoint).ResourceVersion == configMap.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawConfigMap, err := watcher.ToUnstructuredSync(configMap)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify configmap point
+	watcher.ConfigMapPoints.Store(key, ParseConfigMapPoint(configMap))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GCONFIGMAP, configMapName, configMap.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateConfigMapStatus(configMap), -1, rawConfigMap)
+	log.Println("MODIFIED configmap " + configMapName)
+}
+
+func (watcher *Watcher) onConfigMapDeleted(obj interface{}) {
+	configMap, ok := unwrapTombstone(obj).(*v1.ConfigMap)
+	if !ok {
+		return
+	}
+	configMapName := configMap.GetName()
+	key := pointKey(configMap.Namespace, configMapName)
+	_, found := watcher.ConfigMapPoints.Load(key)
+	if found {
+		// delete configmap point
+		watcher.ConfigMapPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GCONFIGMAP, configMapName, configMap.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateConfigMapStatus(configMap), -1, nil)
+		log.Println("DELETED configmap " + configMapName)
+	}
+}
@@ -0,0 +1,133 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type CronJobPoint struct {
+	WatchPoint
+	Schedule string
+}
+
+func (p *CronJobPoint) String() string {
+	return fmt.Sprintf("CronJob %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *CronJobPoint) Init(obj *batchv1.CronJob) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Schedule = obj.Spec.Schedule
+}
+
+func ParseCronJobPoint(d *batchv1.CronJob) *CronJobPoint {
+	p := &CronJobPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseCronJob(rawCronJob map[string]interface{}) (*batchv1.CronJob, error) {
+	ns := &batchv1.CronJob{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawCronJob, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func CreateCronJobStatus(cronjob *batchv1.CronJob) *gkube.GCronJobStatus {
+	timeZone := ""
+	if cronjob.Spec.TimeZone != nil {
+		timeZone = *cronjob.Spec.TimeZone
+	}
+	suspend := cronjob.Spec.Suspend != nil && *cronjob.Spec.Suspend
+	return &gkube.GCronJobStatus{
+		Schedule: cronjob.Spec.Schedule,
+		TimeZone: timeZone,
+		Suspend:  suspend,
+		Active:   int32(len(cronjob.Status.Active)),
+	}
+}
+
+func (watcher *Watcher) WatchCronJobs(ctx context.Context, nsName string) {
+	informer := batchinformers.NewFilteredCronJobInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "cronjobs", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onCronJobAdded,
+		UpdateFunc: watcher.onCronJobModified,
+		DeleteFunc: watcher.onCronJobDeleted,
+	})
+}
+
+func (watcher *Watcher) onCronJobAdded(obj interface{}) {
+	cronjob, ok := obj.(*batchv1.CronJob)
+	if !ok {
+		return
+	}
+	rawCronJob, err := watcher.ToUnstructuredSync(cronjob)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	cronjobName := cronjob.GetName()
+	key := pointKey(cronjob.Namespace, cronjobName)
+	_, found := watcher.CronJobPoints.Load(key)
+	if !found {
+		// add cronjob point
+		watcher.CronJobPoints.Store(key, ParseCronJobPoint(cronjob))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCRONJOB, cronjobName, cronjob.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateCronJobStatus(cronjob), -1, rawCronJob)
+		log.Println("ADDED cronjob " + cronjobName)
+	}
+}
+
+func (watcher *Watcher) onCronJobModified(oldObj, newObj interface{}) {
+	cronjob, ok := newObj.(*batchv1.CronJob)
+	if !ok {
+		return
+	}
+
+	cronjobName := cronjob.GetName()
+	key := pointKey(cronjob.Namespace, cronjobName)
+	point, found := watcher.CronJobPoints.Load(key)
+	if !found {
+		watcher.onCronJobAdded(cronjob)
+		return
+	}
+	if point.(*CronJobPoint).ResourceVersion == cronjob.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawCronJob, err := watcher.ToUnstructuredSync(cronjob)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify cronjob point
+	watcher.CronJobPoints.Store(key, ParseCronJobPoint(cronjob))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GCRONJOB, cronjobName, cronjob.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateCronJobStatus(cronjob), -1, rawCronJob)
+	log.Println("MODIFIED cronjob " + cronjobName)
+}
+
+func (watcher *Watcher) onCronJobDeleted(obj interface{}) {
+	cronjob, ok := unwrapTombstone(obj).(*batchv1.CronJob)
+	if !ok {
+		return
+	}
+	cronjobName := cronjob.GetName()
+	key := pointKey(cronjob.Namespace, cronjobName)
+	_, found := watcher.CronJobPoints.Load(key)
+	if found {
+		// delete cronjob point
+		watcher.CronJobPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GCRONJOB, cronjobName, cronjob.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateCronJobStatus(cronjob), -1, nil)
+		log.Println("DELETED cronjob " + cronjobName)
+	}
+}
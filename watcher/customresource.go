@@ -0,0 +1,213 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CustomResourceKind is a configured GroupVersionResource resolved through discovery
+type CustomResourceKind struct {
+	schema.GroupVersionResource
+	Kind       string
+	Namespaced bool
+}
+
+type CustomResourcePoint struct {
+	WatchPoint
+	Kind  string
+	Group string
+}
+
+func (p *CustomResourcePoint) String() string {
+	return fmt.Sprintf("%s %s (%s) - %s", p.Kind, p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *CustomResourcePoint) Init(obj *unstructured.Unstructured) {
+	p.Name = obj.GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Kind = obj.GetKind()
+	p.Group = obj.GroupVersionKind().Group
+}
+
+func ParseCustomResourcePoint(d *unstructured.Unstructured) *CustomResourcePoint {
+	p := &CustomResourcePoint{}
+	p.Init(d)
+	return p
+}
+
+// customResourceObjectName names a custom resource the way kubectl does, i.e. kafka.kafka.strimzi.io/my-cluster, so that
+// custom resources of different kinds sharing a name do not share a slot
+func customResourceObjectName(kind, group, name string) string {
+	if len(group) == 0 {
+		return fmt.Sprintf("%s/%s", strings.ToLower(kind), name)
+	}
+	return fmt.Sprintf("%s.%s/%s", strings.ToLower(kind), group, name)
+}
+
+// resolveCustomResources looks up the kind and scope of every configured custom resource, skipping those the API
+// server does not serve
+func (watcher *Watcher) resolveCustomResources() {
+	watcher.customResourceKinds = []CustomResourceKind{}
+	if watcher.DynamicClient == nil {
+		return
+	}
+	for _, gvr := range watcher.CustomResources {
+		resources, err := watcher.Client.Discovery().ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+		if err != nil {
+			log.Printf("custom resource %s is not served: %v\n", gvr.String(), err)
+			continue
+		}
+		found := false
+		for _, resource := range resources.APIResources {
+			if resource.Name == gvr.Resource {
+				watcher.customResourceKinds = append(watcher.customResourceKinds, CustomResourceKind{GroupVersionResource: gvr, Kind: resource.Kind, Namespaced: resource.Namespaced})
+				found = true
+			}
+		}
+		if !found {
+			log.Printf("custom resource %s is not served\n", gvr.String())
+		}
+	}
+}
+
+// customOwner returns the watched custom resource among the owner references of an object in namespace, preferring the
+// controller. Cluster-scoped owners are looked up in CLUSTER_SCOPED_NAMESPACE.
+func (watcher *Watcher) customOwner(namespace string, ownerRefs []metav1.OwnerReference) gkube.GCustomOwner {
+	owner := gkube.GCustomOwner{}
+	for _, ownerRef := range ownerRefs {
+		gv, err := schema.ParseGroupVersion(ownerRef.APIVersion)
+		if err != nil {
+			continue
+		}
+		for _, kind := range watcher.customResourceKinds {
+			if kind.Group == gv.Group && kind.Kind == ownerRef.Kind && (len(owner.Name) == 0 || (ownerRef.Controller != nil && *ownerRef.Controller)) {
+				owner = gkube.GCustomOwner{Name: customResourceObjectName(ownerRef.Kind, gv.Group, ownerRef.Name), Namespace: namespace}
+				if !kind.Namespaced {
+					owner.Namespace = gkube.CLUSTER_SCOPED_NAMESPACE
+				}
+			}
+		}
+	}
+	return owner
+}
+
+// CreateCustomResourceStatus reads the conventional status fields of a custom resource, and its owner
+func (watcher *Watcher) CreateCustomResourceStatus(obj *unstructured.Unstructured) *gkube.GCustomResourceStatus {
+	ownerName := ""
+	ownerType := ""
+	for _, ownerRef := range obj.GetOwnerReferences() {
+		if _, found := gkube.OWNER_KIND_RESOURCES[ownerRef.Kind]; found {
+			ownerName = ownerRef.Name
+			ownerType = ownerRef.Kind
+		}
+	}
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	ready := ""
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, rawCondition := range conditions {
+		if condition, ok := rawCondition.(map[string]interface{}); ok && condition["type"] == "Ready" {
+			ready, _ = condition["status"].(string)
+		}
+	}
+	return &gkube.GCustomResourceStatus{
+		APIVersion:         obj.GetAPIVersion(),
+		Kind:               obj.GetKind(),
+		Phase:              phase,
+		Ready:              ready,
+		OwnerReferenceName: ownerName,
+		OwnerReferenceType: ownerType,
+		CustomOwner:        watcher.customOwner(obj.GetNamespace(), obj.GetOwnerReferences()),
+	}
+}
+
+// WatchCustomResources starts an informer for every resolved custom resource of the given scope. Cluster-scoped
+// custom resources are watched once with an empty nsName.
+func (watcher *Watcher) WatchCustomResources(ctx context.Context, nsName string, namespaced bool) {
+	for _, kind := range watcher.customResourceKinds {
+		if kind.Namespaced == namespaced {
+			go watcher.WatchCustomResource(ctx, kind, nsName)
+		}
+	}
+}
+
+func (watcher *Watcher) WatchCustomResource(ctx context.Context, kind CustomResourceKind, nsName string) {
+	informer := dynamicinformer.NewFilteredDynamicInformer(watcher.DynamicClient, kind.GroupVersionResource, nsName, ResyncPeriod, cache.Indexers{}, nil)
+	watcher.runInformer(ctx, kind.GroupVersionResource.GroupResource().String(), informer.Informer(), cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onCustomResourceAdded,
+		UpdateFunc: watcher.onCustomResourceModified,
+		DeleteFunc: watcher.onCustomResourceDeleted,
+	})
+}
+
+func customResourcePointKey(obj *unstructured.Unstructured) string {
+	return pointKey(obj.GetNamespace(), customResourceObjectName(obj.GetKind(), obj.GroupVersionKind().Group, obj.GetName()))
+}
+
+func (watcher *Watcher) onCustomResourceAdded(obj interface{}) {
+	customResource, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	rawCustomResource := runtime.DeepCopyJSON(customResource.Object)
+
+	customResourceName := customResourceObjectName(customResource.GetKind(), customResource.GroupVersionKind().Group, customResource.GetName())
+	key := customResourcePointKey(customResource)
+	_, found := watcher.CustomResourcePoints.Load(key)
+	if !found {
+		// add custom resource point
+		watcher.CustomResourcePoints.Store(key, ParseCustomResourcePoint(customResource))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCUSTOMRESOURCE, customResourceName, customResource.GetNamespace(), gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateCustomResourceStatus(customResource), -1, rawCustomResource)
+		log.Println("ADDED custom resource " + customResourceName)
+	}
+}
+
+func (watcher *Watcher) onCustomResourceModified(oldObj, newObj interface{}) {
+	customResource, ok := newObj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	customResourceName := customResourceObjectName(customResource.GetKind(), customResource.GroupVersionKind().Group, customResource.GetName())
+	key := customResourcePointKey(customResource)
+	point, found := watcher.CustomResourcePoints.Load(key)
+	if !found {
+		watcher.onCustomResourceAdded(customResource)
+		return
+	}
+	if point.(*CustomResourcePoint).ResourceVersion == customResource.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawCustomResource := runtime.DeepCopyJSON(customResource.Object)
+	// modify custom resource point
+	watcher.CustomResourcePoints.Store(key, ParseCustomResourcePoint(customResource))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GCUSTOMRESOURCE, customResourceName, customResource.GetNamespace(), gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateCustomResourceStatus(customResource), -1, rawCustomResource)
+	log.Println("MODIFIED custom resource " + customResourceName)
+}
+
+func (watcher *Watcher) onCustomResourceDeleted(obj interface{}) {
+	customResource, ok := unwrapTombstone(obj).(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	customResourceName := customResourceObjectName(customResource.GetKind(), customResource.GroupVersionKind().Group, customResource.GetName())
+	key := customResourcePointKey(customResource)
+	_, found := watcher.CustomResourcePoints.Load(key)
+	if found {
+		// delete custom resource point
+		watcher.CustomResourcePoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GCUSTOMRESOURCE, customResourceName, customResource.GetNamespace(), gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateCustomResourceStatus(customResource), -1, nil)
+		log.Println("DELETED custom resource " + customResourceName)
+	}
+}
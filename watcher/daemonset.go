@@ -0,0 +1,245 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	corev1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type DaemonSetPoint struct {
+	WatchPoint
+	NodeSelector           map[string]string
+	Affinity               *v1.Affinity
+	Tolerations            []v1.Toleration
+	DesiredNumberScheduled int32
+	NumberReady            int32
+	OwnerReferences        []metav1.OwnerReference
+}
+
+func (p *DaemonSetPoint) String() string {
+	return fmt.Sprintf("DaemonSet %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *DaemonSetPoint) Init(obj *corev1.DaemonSet) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.NodeSelector = obj.Spec.Template.Spec.NodeSelector
+	p.Affinity = obj.Spec.Template.Spec.Affinity
+	p.Tolerations = obj.Spec.Template.Spec.Tolerations
+	p.DesiredNumberScheduled = obj.Status.DesiredNumberScheduled
+	p.NumberReady = obj.Status.NumberReady
+	p.OwnerReferences = obj.OwnerReferences
+}
+
+func ParseDaemonSetPoint(d *corev1.DaemonSet) *DaemonSetPoint {
+	p := &DaemonSetPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseDaemonSet(rawDaemonSet map[string]interface{}) (*corev1.DaemonSet, error) {
+	ns := &corev1.DaemonSet{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawDaemonSet, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// taints the DaemonSet controller tolerates on behalf of every daemon pod
+var daemonSetDefaultTolerations = []string{
+	v1.TaintNodeNotReady,
+	v1.TaintNodeUnreachable,
+	v1.TaintNodeDiskPressure,
+	v1.TaintNodeMemoryPressure,
+	v1.TaintNodePIDPressure,
+	v1.TaintNodeUnschedulable,
+	v1.TaintNodeNetworkUnavailable,
+}
+
+func nodeSelectorTermMatches(term v1.NodeSelectorTerm, nodeLabels map[string]string) bool {
+	operators := map[v1.NodeSelectorOperator]selection.Operator{
+		v1.NodeSelectorOpIn:           selection.In,
+		v1.NodeSelectorOpNotIn:        selection.NotIn,
+		v1.NodeSelectorOpExists:       selection.Exists,
+		v1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+		v1.NodeSelectorOpGt:           selection.GreaterThan,
+		v1.NodeSelectorOpLt:           selection.LessThan,
+	}
+	selector := labels.NewSelector()
+	for _, expression := range term.MatchExpressions {
+		requirement, err := labels.NewRequirement(expression.Key, operators[expression.Operator], expression.Values)
+		if err != nil {
+			return false
+		}
+		selector = selector.Add(*requirement)
+	}
+	return selector.Matches(labels.Set(nodeLabels))
+}
+
+// isNodeEligible reports whether the DaemonSet should run a pod on the node, following its node selector, required node
+// affinity and the taints its pods tolerate
+func (p *DaemonSetPoint) isNodeEligible(node *NodePoint) bool {
+	if !labels.SelectorFromSet(p.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false
+	}
+	if p.Affinity != nil && p.Affinity.NodeAffinity != nil && p.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		terms := p.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		if !slices.ContainsFunc(terms, func(term v1.NodeSelectorTerm) bool { return nodeSelectorTermMatches(term, node.Labels) }) {
+			return false
+		}
+	}
+	for _, taint := range node.Taints {
+		if taint.Effect == v1.TaintEffectPreferNoSchedule || slices.Contains(daemonSetDefaultTolerations, taint.Key) {
+			continue
+		}
+		if !slices.ContainsFunc(p.Tolerations, func(toleration v1.Toleration) bool { return toleration.ToleratesTaint(&taint) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateDaemonSetStatus derives the GDaemonSetStatus of a daemonset, including its coverage across every known node
+func (watcher *Watcher) CreateDaemonSetStatus(p *DaemonSetPoint) *gkube.GDaemonSetStatus {
+	// ready state of the daemon pods by node
+	podsByNode := map[string]bool{}
+	watcher.PodPoints.Range(func(_, value any) bool {
+		pod := value.(*PodPoint)
+		if pod.Namespace == p.Namespace && pod.OwnerKind == "DaemonSet" && pod.OwnerName == p.Name && len(pod.NodeName) > 0 {
+			podsByNode[pod.NodeName] = podsByNode[pod.NodeName] || pod.Ready
+		}
+		return true
+	})
+	coverage := []gkube.GNodeCoverage{}
+	watcher.NodePoints.Range(func(_, value any) bool {
+		node := value.(*NodePoint)
+		state := gkube.NodeCoverageExcluded
+		if ready, found := podsByNode[node.Name]; found {
+			state = gkube.NodeCoverageUnhealthy
+			if ready {
+				state = gkube.NodeCoverageHealthy
+			}
+		} else if p.isNodeEligible(node) {
+			state = gkube.NodeCoverageMissing
+		}
+		coverage = append(coverage, gkube.GNodeCoverage{NodeName: node.Name, State: state})
+		return true
+	})
+	slices.SortFunc(coverage, func(a, b gkube.GNodeCoverage) int {
+		return strings.Compare(a.NodeName, b.NodeName)
+	})
+	return &gkube.GDaemonSetStatus{
+		DesiredNumberScheduled: p.DesiredNumberScheduled,
+		NumberReady:            p.NumberReady,
+		Coverage:               coverage,
+		CustomOwner:            watcher.customOwner(p.Namespace, p.OwnerReferences),
+	}
+}
+
+func (watcher *Watcher) pushDaemonSetCoverage(namespace, daemonsetName string) {
+	point, found := watcher.DaemonSetPoints.Load(pointKey(namespace, daemonsetName))
+	if !found {
+		return
+	}
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GDAEMONSET, daemonsetName, namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDaemonSetStatus(point.(*DaemonSetPoint)), -1, nil)
+}
+
+func (watcher *Watcher) pushAllDaemonSetCoverage() {
+	watcher.DaemonSetPoints.Range(func(_, value any) bool {
+		point := value.(*DaemonSetPoint)
+		watcher.pushDaemonSetCoverage(point.Namespace, point.Name)
+		return true
+	})
+}
+
+func (watcher *Watcher) WatchDaemonSets(ctx context.Context, nsName string) {
+	informer := appsinformers.NewFilteredDaemonSetInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "daemonsets", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onDaemonSetAdded,
+		UpdateFunc: watcher.onDaemonSetModified,
+		DeleteFunc: watcher.onDaemonSetDeleted,
+	})
+}
+
+func (watcher *Watcher) onDaemonSetAdded(obj interface{}) {
+	daemonset, ok := obj.(*corev1.DaemonSet)
+	if !ok {
+		return
+	}
+	rawDaemonSet, err := watcher.ToUnstructuredSync(daemonset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	daemonsetName := daemonset.GetName()
+	key := pointKey(daemonset.Namespace, daemonsetName)
+	_, found := watcher.DaemonSetPoints.Load(key)
+	if !found {
+		// add daemonset point
+		point := ParseDaemonSetPoint(daemonset)
+		watcher.DaemonSetPoints.Store(key, point)
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GDAEMONSET, daemonsetName, daemonset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDaemonSetStatus(point), -1, rawDaemonSet)
+		log.Println("ADDED daemonset " + daemonsetName)
+	}
+}
+
+func (watcher *Watcher) onDaemonSetModified(oldObj, newObj interface{}) {
+	daemonset, ok := newObj.(*corev1.DaemonSet)
+	if !ok {
+		return
+	}
+
+	daemonsetName := daemonset.GetName()
+	key := pointKey(daemonset.Namespace, daemonsetName)
+	point, found := watcher.DaemonSetPoints.Load(key)
+	if !found {
+		watcher.onDaemonSetAdded(daemonset)
+		return
+	}
+	if point.(*DaemonSetPoint).ResourceVersion == daemonset.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawDaemonSet, err := watcher.ToUnstructuredSync(daemonset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify daemonset point
+	newPoint := ParseDaemonSetPoint(daemonset)
+	watcher.DaemonSetPoints.Store(key, newPoint)
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GDAEMONSET, daemonsetName, daemonset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDaemonSetStatus(newPoint), -1, rawDaemonSet)
+	log.Println("MODIFIED daemonset " + daemonsetName)
+}
+
+func (watcher *Watcher) onDaemonSetDeleted(obj interface{}) {
+	daemonset, ok := unwrapTombstone(obj).(*corev1.DaemonSet)
+	if !ok {
+		return
+	}
+	daemonsetName := daemonset.GetName()
+	key := pointKey(daemonset.Namespace, daemonsetName)
+	point, found := watcher.DaemonSetPoints.Load(key)
+	if found {
+		// delete daemonset point
+		watcher.DaemonSetPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GDAEMONSET, daemonsetName, daemonset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDaemonSetStatus(point.(*DaemonSetPoint)), -1, nil)
+		log.Println("DELETED daemonset " + daemonsetName)
+	}
+}
@@ -4,13 +4,12 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
 
 	"github.com/kabicin/kubechaser/renderer/gkube"
 	corev1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type DeploymentPoint struct {
@@ -47,75 +46,106 @@ func (watcher *Watcher) ParseDeployment(rawDeployment map[string]interface{}) (*
 	return ns, nil
 }
 
-func (watcher *Watcher) WatchDeployments(nsName string) {
-	watcher.ClientMutex.Lock()
-	watchInterface, _ := watcher.Client.AppsV1().Deployments(nsName).Watch(context.TODO(), metav1.ListOptions{Watch: true})
-	ch := watchInterface.ResultChan()
-	watcher.ClientMutex.Unlock()
-	for {
-		select {
-		case e, ok := <-ch:
-			if !ok {
-				return
-			}
-			if e.Type == watch.Added {
-				rawDeployment, _ := watcher.ToUnstructuredSync(e.Object)
-				deploy, err := watcher.ParseDeployment(rawDeployment)
-				if err != nil {
-					return
-				}
-
-				deployName := deploy.GetName()
-				_, found := watcher.DeploymentPoints.Load(deployName)
-				if !found {
-					deploy, err := watcher.ParseDeployment(rawDeployment)
-					if err != nil {
-						continue
-					}
-					// add deployment point
-					watcher.DeploymentPoints.Store(deployName, ParseDeploymentPoint(deploy))
-					watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GDEPLOYMENT, deployName, deploy.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GDeploymentStatus{
-						ReadyReplicas: deploy.Status.ReadyReplicas,
-						Replicas:      deploy.Status.Replicas,
-					}, -1, rawDeployment)
-					log.Println("ADDED deployment " + deployName)
-				}
-			} else if e.Type == watch.Modified {
-				rawDeployment, _ := watcher.ToUnstructuredSync(e.Object)
-				deploy, err := watcher.ParseDeployment(rawDeployment)
-				if err != nil {
-					return
-				}
-
-				deployName := deploy.GetName()
-				_, found := watcher.DeploymentPoints.Load(deployName)
-				if found {
-					deploy, err := watcher.ParseDeployment(rawDeployment)
-					if err != nil {
-						continue
-					}
-					// modify deployment point
-					watcher.DeploymentPoints.Store(deployName, ParseDeploymentPoint(deploy))
-					log.Println("MODIFIED deployment " + deployName)
-				} else {
-
-				}
-			} else if e.Type == watch.Deleted {
-				rawDeployment, _ := watcher.ToUnstructuredSync(e.Object)
-				deploy, err := watcher.ParseDeployment(rawDeployment)
-				if err != nil {
-					return
-				}
-				deployName := deploy.GetName()
-				_, found := watcher.DeploymentPoints.Load(deployName)
-				if found {
-					// delete deployment point
-					watcher.DeploymentPoints.Delete(deployName)
-					log.Println("DELETED deployment " + deployName)
-				}
-			}
-		case <-time.After(30 * time.Minute):
-			return
+// the reason of the Progressing condition once a rollout made no progress within progressDeadlineSeconds
+const progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+
+// deploymentStalled reports whether the rollout of the deployment exceeded its progress deadline
+func deploymentStalled(deploy *corev1.Deployment) bool {
+	for _, condition := range deploy.Status.Conditions {
+		if condition.Type == corev1.DeploymentProgressing {
+			return condition.Reason == progressDeadlineExceededReason
 		}
 	}
+	return false
+}
+
+func (watcher *Watcher) CreateDeploymentStatus(deploy *corev1.Deployment) *gkube.GDeploymentStatus {
+	desiredReplicas := int32(1) // defaulted by the API server when unset
+	if deploy.Spec.Replicas != nil {
+		desiredReplicas = *deploy.Spec.Replicas
+	}
+	return &gkube.GDeploymentStatus{
+		ReadyReplicas:     deploy.Status.ReadyReplicas,
+		Replicas:          deploy.Status.Replicas,
+		DesiredReplicas:   desiredReplicas,
+		UpdatedReplicas:   deploy.Status.UpdatedReplicas,
+		AvailableReplicas: deploy.Status.AvailableReplicas,
+		Revision:          revision(deploy.Annotations),
+		Stalled:           deploymentStalled(deploy),
+		CustomOwner:       watcher.customOwner(deploy.Namespace, deploy.OwnerReferences),
+	}
+}
+
+func (watcher *Watcher) WatchDeployments(ctx context.Context, nsName string) {
+	informer := appsinformers.NewFilteredDeploymentInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "deployments", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onDeploymentAdded,
+		UpdateFunc: watcher.onDeploymentModified,
+		DeleteFunc: watcher.onDeploymentDeleted,
+	})
+}
+
+func (watcher *Watcher) onDeploymentAdded(obj interface{}) {
+	deploy, ok := obj.(*corev1.Deployment)
+	if !ok {
+		return
+	}
+	rawDeployment, err := watcher.ToUnstructuredSync(deploy)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	deployName := deploy.GetName()
+	key := pointKey(deploy.Namespace, deployName)
+	_, found := watcher.DeploymentPoints.Load(key)
+	if !found {
+		// add deployment point
+		watcher.DeploymentPoints.Store(key, ParseDeploymentPoint(deploy))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GDEPLOYMENT, deployName, deploy.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDeploymentStatus(deploy), -1, rawDeployment)
+		log.Println("ADDED deployment " + deployName)
+	}
+}
+
+func (watcher *Watcher) onDeploymentModified(oldObj, newObj interface{}) {
+	deploy, ok := newObj.(*corev1.Deployment)
+	if !ok {
+		return
+	}
+
+	deployName := deploy.GetName()
+	key := pointKey(deploy.Namespace, deployName)
+	point, found := watcher.DeploymentPoints.Load(key)
+	if !found {
+		watcher.onDeploymentAdded(deploy)
+		return
+	}
+	if point.(*DeploymentPoint).ResourceVersion == deploy.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawDeployment, err := watcher.ToUnstructuredSync(deploy)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify deployment point
+	watcher.DeploymentPoints.Store(key, ParseDeploymentPoint(deploy))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GDEPLOYMENT, deployName, deploy.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDeploymentStatus(deploy), -1, rawDeployment)
+	log.Println("MODIFIED deployment " + deployName)
+}
+
+func (watcher *Watcher) onDeploymentDeleted(obj interface{}) {
+	deploy, ok := unwrapTombstone(obj).(*corev1.Deployment)
+	if !ok {
+		return
+	}
+	deployName := deploy.GetName()
+	key := pointKey(deploy.Namespace, deployName)
+	_, found := watcher.DeploymentPoints.Load(key)
+	if found {
+		// delete deployment point
+		watcher.DeploymentPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GDEPLOYMENT, deployName, deploy.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateDeploymentStatus(deploy), -1, nil)
+		log.Println("DELETED deployment " + deployName)
+	}
 }
@@ -0,0 +1,90 @@
+package watcher
+
+import (
+	"context"
+	"log"
+	"slices"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// EndpointSlices are not drawn on their own. Each change is folded into the GServiceStatus of the owning service,
+// which is identified by the kubernetes.io/service-name label, and pushed as a GMODIFIED event for that service.
+func (watcher *Watcher) WatchEndpointSlices(ctx context.Context, nsName string) {
+	informer := discoveryinformers.NewEndpointSliceInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "endpointslices", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc: watcher.onEndpointSliceChanged,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSlice, oldOk := oldObj.(*discoveryv1.EndpointSlice)
+			newSlice, newOk := newObj.(*discoveryv1.EndpointSlice)
+			if oldOk && newOk && oldSlice.ResourceVersion == newSlice.ResourceVersion {
+				return // periodic resync, nothing changed
+			}
+			watcher.onEndpointSliceChanged(newObj)
+		},
+		DeleteFunc: watcher.onEndpointSliceDeleted,
+	})
+}
+
+func (watcher *Watcher) onEndpointSliceChanged(obj interface{}) {
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		return
+	}
+	watcher.EndpointSlices.Store(pointKey(slice.Namespace, slice.Name), slice)
+	watcher.pushServiceEndpoints(slice)
+}
+
+func (watcher *Watcher) onEndpointSliceDeleted(obj interface{}) {
+	slice, ok := unwrapTombstone(obj).(*discoveryv1.EndpointSlice)
+	if !ok {
+		return
+	}
+	watcher.EndpointSlices.Delete(pointKey(slice.Namespace, slice.Name))
+	watcher.pushServiceEndpoints(slice)
+}
+
+// getServiceEndpoints collects the pod endpoints of every slice published for the service, ordered by pod name
+func (watcher *Watcher) getServiceEndpoints(namespace, serviceName string) []gkube.GServiceEndpoint {
+	endpoints := []gkube.GServiceEndpoint{}
+	watcher.EndpointSlices.Range(func(_, value any) bool {
+		slice := value.(*discoveryv1.EndpointSlice)
+		if slice.Namespace != namespace || slice.Labels[discoveryv1.LabelServiceName] != serviceName {
+			return true
+		}
+		for _, endpoint := range slice.Endpoints {
+			if endpoint.TargetRef == nil || endpoint.TargetRef.Kind != "Pod" {
+				continue
+			}
+			// a nil ready condition should be interpreted as ready
+			ready := endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+			endpoints = append(endpoints, gkube.GServiceEndpoint{PodName: endpoint.TargetRef.Name, Ready: ready})
+		}
+		return true
+	})
+	slices.SortFunc(endpoints, func(a, b gkube.GServiceEndpoint) int {
+		if a.PodName < b.PodName {
+			return -1
+		} else if a.PodName > b.PodName {
+			return 1
+		}
+		return 0
+	})
+	return endpoints
+}
+
+func (watcher *Watcher) pushServiceEndpoints(slice *discoveryv1.EndpointSlice) {
+	serviceName := slice.Labels[discoveryv1.LabelServiceName]
+	if len(serviceName) == 0 {
+		return
+	}
+	point, found := watcher.ServicePoints.Load(pointKey(slice.Namespace, serviceName))
+	if !found {
+		return // the service will pick up its endpoints once it is added
+	}
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSERVICE, serviceName, slice.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateServiceStatus(point.(*ServicePoint)), -1, nil)
+	log.Println("MODIFIED endpoints of service " + serviceName)
+}
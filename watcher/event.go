@@ -0,0 +1,189 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type EventPoint struct {
+	WatchPoint
+	InvolvedResource gkube.GResource
+	InvolvedName     string
+}
+
+func (p *EventPoint) String() string {
+	return fmt.Sprintf("Event %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *EventPoint) Init(obj *v1.Event, involvedResource gkube.GResource, involvedName string) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.InvolvedResource = involvedResource
+	p.InvolvedName = involvedName
+}
+
+func ParseEventPoint(d *v1.Event, involvedResource gkube.GResource, involvedName string) *EventPoint {
+	p := &EventPoint{}
+	p.Init(d, involvedResource, involvedName)
+	return p
+}
+
+func (watcher *Watcher) ParseEvent(rawEvent map[string]interface{}) (*v1.Event, error) {
+	ns := &v1.Event{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawEvent, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// the GOBJECTs that events can be attached to, by the kind of their involved object
+var involvedObjectResources = map[string]gkube.GResource{
+	"Pod":                     gkube.GPOD,
+	"Deployment":              gkube.GDEPLOYMENT,
+	"ReplicaSet":              gkube.GREPLICASET,
+	"StatefulSet":             gkube.GSTATEFULSET,
+	"DaemonSet":               gkube.GDAEMONSET,
+	"Job":                     gkube.GJOB,
+	"CronJob":                 gkube.GCRONJOB,
+	"HorizontalPodAutoscaler": gkube.GHORIZONTALPODAUTOSCALER,
+	"Service":                 gkube.GSERVICE,
+	"Ingress":                 gkube.GINGRESS,
+	"PersistentVolumeClaim":   gkube.GPERSISTENTVOLUMECLAIM,
+	"PersistentVolume":        gkube.GPERSISTENTVOLUME,
+	"StorageClass":            gkube.GSTORAGECLASS,
+	"ConfigMap":               gkube.GCONFIGMAP,
+	"Secret":                  gkube.GSECRET,
+	"ServiceAccount":          gkube.GSERVICEACCOUNT,
+	"Node":                    gkube.GNODE,
+}
+
+// involvedObject finds the GOBJECT an event is about, which is false for kinds that are not drawn
+func (watcher *Watcher) involvedObject(ref v1.ObjectReference) (gkube.GResource, string, bool) {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return gkube.GWIRE, "", false
+	}
+	for _, kind := range watcher.customResourceKinds {
+		if kind.Group == gv.Group && kind.Kind == ref.Kind {
+			return gkube.GCUSTOMRESOURCE, customResourceObjectName(ref.Kind, gv.Group, ref.Name), true
+		}
+	}
+	if resource, found := involvedObjectResources[ref.Kind]; found {
+		return resource, ref.Name, true
+	}
+	return gkube.GWIRE, "", false
+}
+
+// eventTimestamp is when the event was last seen. Events reported through events.k8s.io only set the event time and series.
+func eventTimestamp(event *v1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+func CreateEventStatus(event *v1.Event, involvedResource gkube.GResource, involvedName string) *gkube.GKubeEventStatus {
+	count := event.Count
+	if event.Series != nil {
+		count = event.Series.Count
+	}
+	return &gkube.GKubeEventStatus{
+		InvolvedResource:  involvedResource,
+		InvolvedName:      involvedName,
+		InvolvedNamespace: event.InvolvedObject.Namespace,
+		Type:              event.Type,
+		Reason:            event.Reason,
+		Message:           event.Message,
+		Count:             count,
+		LastTimestamp:     eventTimestamp(event),
+	}
+}
+
+func (watcher *Watcher) WatchEvents(ctx context.Context, nsName string) {
+	informer := coreinformers.NewEventInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "events", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onEventAdded,
+		UpdateFunc: watcher.onEventModified,
+		DeleteFunc: watcher.onEventDeleted,
+	})
+}
+
+func (watcher *Watcher) onEventAdded(obj interface{}) {
+	event, ok := obj.(*v1.Event)
+	if !ok {
+		return
+	}
+	involvedResource, involvedName, drawn := watcher.involvedObject(event.InvolvedObject)
+	if !drawn {
+		return
+	}
+
+	eventName := event.GetName()
+	key := pointKey(event.Namespace, eventName)
+	_, found := watcher.EventPoints.Load(key)
+	if !found {
+		// add event point
+		watcher.EventPoints.Store(key, ParseEventPoint(event, involvedResource, involvedName))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GKUBEEVENT, eventName, event.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateEventStatus(event, involvedResource, involvedName), -1, nil)
+		log.Printf("ADDED event %s %s: %s\n", eventName, event.Type, event.Reason)
+	}
+}
+
+func (watcher *Watcher) onEventModified(oldObj, newObj interface{}) {
+	event, ok := newObj.(*v1.Event)
+	if !ok {
+		return
+	}
+
+	eventName := event.GetName()
+	key := pointKey(event.Namespace, eventName)
+	point, found := watcher.EventPoints.Load(key)
+	if !found {
+		watcher.onEventAdded(event)
+		return
+	}
+	eventPoint := point.(*EventPoint)
+	if eventPoint.ResourceVersion == event.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	// modify event point, the involved object of an event never changes
+	watcher.EventPoints.Store(key, ParseEventPoint(event, eventPoint.InvolvedResource, eventPoint.InvolvedName))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GKUBEEVENT, eventName, event.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateEventStatus(event, eventPoint.InvolvedResource, eventPoint.InvolvedName), -1, nil)
+	log.Printf("MODIFIED event %s %s: %s\n", eventName, event.Type, event.Reason)
+}
+
+func (watcher *Watcher) onEventDeleted(obj interface{}) {
+	event, ok := unwrapTombstone(obj).(*v1.Event)
+	if !ok {
+		return
+	}
+	eventName := event.GetName()
+	key := pointKey(event.Namespace, eventName)
+	point, found := watcher.EventPoints.Load(key)
+	if found {
+		// delete event point
+		eventPoint := point.(*EventPoint)
+		watcher.EventPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GKUBEEVENT, eventName, event.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateEventStatus(event, eventPoint.InvolvedResource, eventPoint.InvolvedName), -1, nil)
+		log.Println("DELETED event " + eventName)
+	}
+}
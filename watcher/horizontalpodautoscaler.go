@@ -0,0 +1,233 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	autoscalinginformers "k8s.io/client-go/informers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+)
+
+type HorizontalPodAutoscalerPoint struct {
+	WatchPoint
+}
+
+func (p *HorizontalPodAutoscalerPoint) String() string {
+	return fmt.Sprintf("HorizontalPodAutoscaler %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *HorizontalPodAutoscalerPoint) Init(obj *autoscalingv2.HorizontalPodAutoscaler) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseHorizontalPodAutoscalerPoint(d *autoscalingv2.HorizontalPodAutoscaler) *HorizontalPodAutoscalerPoint {
+	p := &HorizontalPodAutoscalerPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseHorizontalPodAutoscaler(rawHorizontalPodAutoscaler map[string]interface{}) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	ns := &autoscalingv2.HorizontalPodAutoscaler{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawHorizontalPodAutoscaler, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// metricAmount formats the part of a metric target or reading that the target type compares, i.e. 70% or 500m, with
+// its amount in percent or in thousandths of a unit. The amount is 0 when it is not set.
+func metricAmount(targetType autoscalingv2.MetricTargetType, averageUtilization *int32, averageValue, value *resource.Quantity) (string, float64) {
+	switch targetType {
+	case autoscalingv2.UtilizationMetricType:
+		if averageUtilization != nil {
+			return fmt.Sprintf("%d%%", *averageUtilization), float64(*averageUtilization)
+		}
+	case autoscalingv2.AverageValueMetricType:
+		if averageValue != nil {
+			return averageValue.String(), float64(averageValue.MilliValue())
+		}
+	case autoscalingv2.ValueMetricType:
+		if value != nil {
+			return value.String(), float64(value.MilliValue())
+		}
+	}
+	return "", 0
+}
+
+// autoscalerMetric pairs a metric of the spec with its reading in the status, which the autoscaler reports in the same
+// order. current is nil until the metric was read.
+func autoscalerMetric(spec autoscalingv2.MetricSpec, current *autoscalingv2.MetricStatus) gkube.GAutoscalerMetric {
+	metric := gkube.GAutoscalerMetric{}
+	var target autoscalingv2.MetricTarget
+	var reading *autoscalingv2.MetricValueStatus
+	switch {
+	case spec.Type == autoscalingv2.ResourceMetricSourceType && spec.Resource != nil:
+		metric.Name, target = string(spec.Resource.Name), spec.Resource.Target
+		if current != nil && current.Resource != nil {
+			reading = &current.Resource.Current
+		}
+	case spec.Type == autoscalingv2.ContainerResourceMetricSourceType && spec.ContainerResource != nil:
+		metric.Name = fmt.Sprintf("%s of %s", spec.ContainerResource.Name, spec.ContainerResource.Container)
+		target = spec.ContainerResource.Target
+		if current != nil && current.ContainerResource != nil {
+			reading = &current.ContainerResource.Current
+		}
+	case spec.Type == autoscalingv2.PodsMetricSourceType && spec.Pods != nil:
+		metric.Name, target = spec.Pods.Metric.Name, spec.Pods.Target
+		if current != nil && current.Pods != nil {
+			reading = &current.Pods.Current
+		}
+	case spec.Type == autoscalingv2.ObjectMetricSourceType && spec.Object != nil:
+		metric.Name = fmt.Sprintf("%s on %s/%s", spec.Object.Metric.Name, spec.Object.DescribedObject.Kind, spec.Object.DescribedObject.Name)
+		target = spec.Object.Target
+		if current != nil && current.Object != nil {
+			reading = &current.Object.Current
+		}
+	case spec.Type == autoscalingv2.ExternalMetricSourceType && spec.External != nil:
+		metric.Name, target = spec.External.Metric.Name, spec.External.Target
+		if current != nil && current.External != nil {
+			reading = &current.External.Current
+		}
+	default:
+		metric.Name = string(spec.Type)
+	}
+	targetAmount := float64(0)
+	metric.Target, targetAmount = metricAmount(target.Type, target.AverageUtilization, target.AverageValue, target.Value)
+	if reading != nil {
+		currentAmount := float64(0)
+		metric.Current, currentAmount = metricAmount(target.Type, reading.AverageUtilization, reading.AverageValue, reading.Value)
+		if len(metric.Current) > 0 && targetAmount > 0 {
+			metric.Ratio = currentAmount / targetAmount
+		}
+	}
+	return metric
+}
+
+// CreateHorizontalPodAutoscalerStatus derives the GHorizontalPodAutoscalerStatus of an autoscaler, its scale target is
+// attached to it by kind or as a custom resource
+func (watcher *Watcher) CreateHorizontalPodAutoscalerStatus(hpa *autoscalingv2.HorizontalPodAutoscaler) *gkube.GHorizontalPodAutoscalerStatus {
+	ref := hpa.Spec.ScaleTargetRef
+	minReplicas := int32(1) // defaulted by the API server when unset
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	status := &gkube.GHorizontalPodAutoscalerStatus{
+		ScaleTargetName:   ref.Name,
+		ScaleTargetType:   ref.Kind,
+		CustomScaleTarget: watcher.customOwner(hpa.Namespace, []metav1.OwnerReference{{APIVersion: ref.APIVersion, Kind: ref.Kind, Name: ref.Name}}),
+		CurrentReplicas:   hpa.Status.CurrentReplicas,
+		DesiredReplicas:   hpa.Status.DesiredReplicas,
+		MinReplicas:       minReplicas,
+		MaxReplicas:       hpa.Spec.MaxReplicas,
+		Metrics:           []gkube.GAutoscalerMetric{},
+	}
+	for i, spec := range hpa.Spec.Metrics {
+		var current *autoscalingv2.MetricStatus
+		if i < len(hpa.Status.CurrentMetrics) && hpa.Status.CurrentMetrics[i].Type == spec.Type {
+			current = &hpa.Status.CurrentMetrics[i]
+		}
+		status.Metrics = append(status.Metrics, autoscalerMetric(spec, current))
+	}
+	if hpa.Status.LastScaleTime != nil {
+		status.LastScaleTime = hpa.Status.LastScaleTime.Time
+	}
+	for _, condition := range hpa.Status.Conditions {
+		switch condition.Type {
+		case autoscalingv2.AbleToScale, autoscalingv2.ScalingActive:
+			if condition.Status == v1.ConditionFalse && len(status.Inactive) == 0 {
+				status.Inactive = condition.Reason
+			}
+		case autoscalingv2.ScalingLimited:
+			if condition.Status == v1.ConditionTrue {
+				status.Limited = condition.Reason
+			}
+		}
+	}
+	return status
+}
+
+func (watcher *Watcher) WatchHorizontalPodAutoscalers(ctx context.Context, nsName string) {
+	informer := autoscalinginformers.NewHorizontalPodAutoscalerInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "horizontalpodautoscalers", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onHorizontalPodAutoscalerAdded,
+		UpdateFunc: watcher.onHorizontalPodAutoscalerModified,
+		DeleteFunc: watcher.onHorizontalPodAutoscalerDeleted,
+	})
+}
+
+func (watcher *Watcher) onHorizontalPodAutoscalerAdded(obj interface{}) {
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return
+	}
+	rawHorizontalPodAutoscaler, err := watcher.ToUnstructuredSync(hpa)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	hpaName := hpa.GetName()
+	key := pointKey(hpa.Namespace, hpaName)
+	_, found := watcher.HorizontalPodAutoscalerPoints.Load(key)
+	if !found {
+		// add horizontal pod autoscaler point
+		watcher.HorizontalPodAutoscalerPoints.Store(key, ParseHorizontalPodAutoscalerPoint(hpa))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GHORIZONTALPODAUTOSCALER, hpaName, hpa.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateHorizontalPodAutoscalerStatus(hpa), -1, rawHorizontalPodAutoscaler)
+		log.Println("ADDED horizontal pod autoscaler " + hpaName)
+	}
+}
+
+func (watcher *Watcher) onHorizontalPodAutoscalerModified(oldObj, newObj interface{}) {
+	hpa, ok := newObj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return
+	}
+
+	hpaName := hpa.GetName()
+	key := pointKey(hpa.Namespace, hpaName)
+	point, found := watcher.HorizontalPodAutoscalerPoints.Load(key)
+	if !found {
+		watcher.onHorizontalPodAutoscalerAdded(hpa)
+		return
+	}
+	if point.(*HorizontalPodAutoscalerPoint).ResourceVersion == hpa.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawHorizontalPodAutoscaler, err := watcher.ToUnstructuredSync(hpa)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify horizontal pod autoscaler point
+	watcher.HorizontalPodAutoscalerPoints.Store(key, ParseHorizontalPodAutoscalerPoint(hpa))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GHORIZONTALPODAUTOSCALER, hpaName, hpa.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateHorizontalPodAutoscalerStatus(hpa), -1, rawHorizontalPodAutoscaler)
+	log.Println("MODIFIED horizontal pod autoscaler " + hpaName)
+}
+
+func (watcher *Watcher) onHorizontalPodAutoscalerDeleted(obj interface{}) {
+	hpa, ok := unwrapTombstone(obj).(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok {
+		return
+	}
+	hpaName := hpa.GetName()
+	key := pointKey(hpa.Namespace, hpaName)
+	_, found := watcher.HorizontalPodAutoscalerPoints.Load(key)
+	if found {
+		// delete horizontal pod autoscaler point
+		watcher.HorizontalPodAutoscalerPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GHORIZONTALPODAUTOSCALER, hpaName, hpa.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateHorizontalPodAutoscalerStatus(hpa), -1, nil)
+		log.Println("DELETED horizontal pod autoscaler " + hpaName)
+	}
+}
@@ -0,0 +1,179 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	networkinginformers "k8s.io/client-go/informers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type IngressPoint struct {
+	WatchPoint
+	ClassName string
+	Routes    []gkube.GIngressRoute
+}
+
+func (p *IngressPoint) String() string {
+	return fmt.Sprintf("Ingress %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *IngressPoint) Init(obj *networkingv1.Ingress) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	if obj.Spec.IngressClassName != nil {
+		p.ClassName = *obj.Spec.IngressClassName
+	}
+	p.Routes = parseIngressRoutes(obj)
+}
+
+func ParseIngressPoint(d *networkingv1.Ingress) *IngressPoint {
+	p := &IngressPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseIngress(rawIngress map[string]interface{}) (*networkingv1.Ingress, error) {
+	ns := &networkingv1.Ingress{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawIngress, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// formats a service backend port by name or number
+func ingressServicePort(port networkingv1.ServiceBackendPort) string {
+	if len(port.Name) > 0 {
+		return port.Name
+	}
+	return fmt.Sprintf("%d", port.Number)
+}
+
+// finds the secret terminating TLS for the host. A TLS entry without hosts applies to every host.
+func ingressTLSSecret(obj *networkingv1.Ingress, host string) string {
+	for _, tls := range obj.Spec.TLS {
+		if len(tls.Hosts) == 0 || slices.Contains(tls.Hosts, host) {
+			return tls.SecretName
+		}
+	}
+	return ""
+}
+
+// parseIngressRoutes flattens the rules of an ingress into one route per path, followed by the default backend if set
+func parseIngressRoutes(obj *networkingv1.Ingress) []gkube.GIngressRoute {
+	routes := []gkube.GIngressRoute{}
+	for _, rule := range obj.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			route := gkube.GIngressRoute{Host: rule.Host, Path: path.Path, TLSSecret: ingressTLSSecret(obj, rule.Host)}
+			if path.Backend.Service != nil {
+				route.ServiceName = path.Backend.Service.Name
+				route.ServicePort = ingressServicePort(path.Backend.Service.Port)
+			}
+			routes = append(routes, route)
+		}
+	}
+	if backend := obj.Spec.DefaultBackend; backend != nil && backend.Service != nil {
+		routes = append(routes, gkube.GIngressRoute{
+			Path:        "/*",
+			ServiceName: backend.Service.Name,
+			ServicePort: ingressServicePort(backend.Service.Port),
+			TLSSecret:   ingressTLSSecret(obj, ""),
+		})
+	}
+	return routes
+}
+
+func CreateIngressStatus(p *IngressPoint) *gkube.GIngressStatus {
+	return &gkube.GIngressStatus{
+		ClassName: p.ClassName,
+		Routes:    p.Routes,
+	}
+}
+
+func (watcher *Watcher) WatchIngresses(ctx context.Context, nsName string) {
+	informer := networkinginformers.NewIngressInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "ingresses", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onIngressAdded,
+		UpdateFunc: watcher.onIngressModified,
+		DeleteFunc: watcher.onIngressDeleted,
+	})
+}
+
+func (watcher *Watcher) onIngressAdded(obj interface{}) {
+	ingress, ok := obj.(*networkingv1.Ingress)
+	if !ok {
+		return
+	}
+	rawIngress, err := watcher.ToUnstructuredSync(ingress)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	ingressName := ingress.GetName()
+	key := pointKey(ingress.Namespace, ingressName)
+	_, found := watcher.IngressPoints.Load(key)
+	if !found {
+		// add ingress point
+		point := ParseIngressPoint(ingress)
+		watcher.IngressPoints.Store(key, point)
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GINGRESS, ingressName, ingress.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateIngressStatus(point), -1, rawIngress)
+		log.Println("ADDED ingress " + ingressName)
+	}
+}
+
+func (watcher *Watcher) onIngressModified(oldObj, newObj interface{}) {
+	ingress, ok := newObj.(*networkingv1.Ingress)
+	if !ok {
+		return
+	}
+
+	ingressName := ingress.GetName()
+	key := pointKey(ingress.Namespace, ingressName)
+	point, found := watcher.IngressPoints.Load(key)
+	if !found {
+		watcher.onIngressAdded(ingress)
+		return
+	}
+	if point.(*IngressPoint).ResourceVersion == ingress.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawIngress, err := watcher.ToUnstructuredSync(ingress)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify ingress point
+	newPoint := ParseIngressPoint(ingress)
+	watcher.IngressPoints.Store(key, newPoint)
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GINGRESS, ingressName, ingress.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateIngressStatus(newPoint), -1, rawIngress)
+	log.Println("MODIFIED ingress " + ingressName)
+}
+
+func (watcher *Watcher) onIngressDeleted(obj interface{}) {
+	ingress, ok := unwrapTombstone(obj).(*networkingv1.Ingress)
+	if !ok {
+		return
+	}
+	ingressName := ingress.GetName()
+	key := pointKey(ingress.Namespace, ingressName)
+	point, found := watcher.IngressPoints.Load(key)
+	if found {
+		// delete ingress point
+		watcher.IngressPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GINGRESS, ingressName, ingress.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateIngressStatus(point.(*IngressPoint)), -1, nil)
+		log.Println("DELETED ingress " + ingressName)
+	}
+}
@@ -0,0 +1,164 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	batchinformers "k8s.io/client-go/informers/batch/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type JobPoint struct {
+	WatchPoint
+	Completions int32
+	Parallelism int32
+}
+
+func (p *JobPoint) String() string {
+	return fmt.Sprintf("Job %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *JobPoint) Init(obj *batchv1.Job) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	if obj.Spec.Completions != nil {
+		p.Completions = *obj.Spec.Completions
+	}
+	if obj.Spec.Parallelism != nil {
+		p.Parallelism = *obj.Spec.Parallelism
+	}
+}
+
+func ParseJobPoint(d *batchv1.Job) *JobPoint {
+	p := &JobPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseJob(rawJob map[string]interface{}) (*batchv1.Job, error) {
+	ns := &batchv1.Job{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawJob, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateJobStatus derives the GJobStatus of a job; the watcher will notice any owner references to a CronJob or to a
+// custom resource.
+// A job without spec.completions completes after its first successful pod, so it is drawn as a single completion.
+func (watcher *Watcher) CreateJobStatus(job *batchv1.Job) *gkube.GJobStatus {
+	ownerName := ""
+	ownerType := ""
+	ownerRefs := job.GetObjectMeta().GetOwnerReferences()
+	if len(ownerRefs) > 0 && ownerRefs[0].Kind == "CronJob" {
+		ownerName = ownerRefs[0].Name
+		ownerType = ownerRefs[0].Kind
+	}
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	parallelism := int32(1)
+	if job.Spec.Parallelism != nil {
+		parallelism = *job.Spec.Parallelism
+	}
+	condition := ""
+	for _, c := range job.Status.Conditions {
+		if c.Status == v1.ConditionTrue && (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed || c.Type == batchv1.JobSuspended) {
+			condition = string(c.Type)
+		}
+	}
+	return &gkube.GJobStatus{
+		Completions:        completions,
+		Parallelism:        parallelism,
+		Active:             job.Status.Active,
+		Succeeded:          job.Status.Succeeded,
+		Failed:             job.Status.Failed,
+		Condition:          condition,
+		OwnerReferenceName: ownerName,
+		OwnerReferenceType: ownerType,
+		CustomOwner:        watcher.customOwner(job.Namespace, job.OwnerReferences),
+	}
+}
+
+func (watcher *Watcher) WatchJobs(ctx context.Context, nsName string) {
+	informer := batchinformers.NewFilteredJobInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "jobs", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onJobAdded,
+		UpdateFunc: watcher.onJobModified,
+		DeleteFunc: watcher.onJobDeleted,
+	})
+}
+
+func (watcher *Watcher) onJobAdded(obj interface{}) {
+	job, ok := obj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+	rawJob, err := watcher.ToUnstructuredSync(job)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	jobName := job.GetName()
+	key := pointKey(job.Namespace, jobName)
+	_, found := watcher.JobPoints.Load(key)
+	if !found {
+		// add job point
+		watcher.JobPoints.Store(key, ParseJobPoint(job))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GJOB, jobName, job.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateJobStatus(job), -1, rawJob)
+		log.Println("ADDED job " + jobName)
+	}
+}
+
+func (watcher *Watcher) onJobModified(oldObj, newObj interface{}) {
+	job, ok := newObj.(*batchv1.Job)
+	if !ok {
+		return
+	}
+
+	jobName := job.GetName()
+	key := pointKey(job.Namespace, jobName)
+	point, found := watcher.JobPoints.Load(key)
+	if !found {
+		watcher.onJobAdded(job)
+		return
+	}
+	if point.(*JobPoint).ResourceVersion == job.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawJob, err := watcher.ToUnstructuredSync(job)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify job point
+	watcher.JobPoints.Store(key, ParseJobPoint(job))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GJOB, jobName, job.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateJobStatus(job), -1, rawJob)
+	log.Println("MODIFIED job " + jobName)
+}
+
+func (watcher *Watcher) onJobDeleted(obj interface{}) {
+	job, ok := unwrapTombstone(obj).(*batchv1.Job)
+	if !ok {
+		return
+	}
+	jobName := job.GetName()
+	key := pointKey(job.Namespace, jobName)
+	_, found := watcher.JobPoints.Load(key)
+	if found {
+		// delete job point
+		watcher.JobPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GJOB, jobName, job.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateJobStatus(job), -1, nil)
+		log.Println("DELETED job " + jobName)
+	}
+}
@@ -0,0 +1,454 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// StdinManifestPath reads the manifests from standard input, i.e. piped from helm template or kustomize build
+const StdinManifestPath = "-"
+
+// built-in kinds that are not namespaced, every other built-in kind lands in the default namespace unless it names one
+var clusterScopedKinds = []schema.GroupKind{
+	{Group: "", Kind: "Namespace"},
+	{Group: "", Kind: "Node"},
+	{Group: "", Kind: "PersistentVolume"},
+	{Group: "", Kind: "ComponentStatus"},
+	{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicy"},
+	{Group: "admissionregistration.k8s.io", Kind: "ValidatingAdmissionPolicyBinding"},
+	{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	{Group: "apiregistration.k8s.io", Kind: "APIService"},
+	{Group: "apiserverinternal.k8s.io", Kind: "StorageVersion"},
+	{Group: "authentication.k8s.io", Kind: "TokenReview"},
+	{Group: "authentication.k8s.io", Kind: "SelfSubjectReview"},
+	{Group: "authorization.k8s.io", Kind: "SubjectAccessReview"},
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectAccessReview"},
+	{Group: "authorization.k8s.io", Kind: "SelfSubjectRulesReview"},
+	{Group: "certificates.k8s.io", Kind: "CertificateSigningRequest"},
+	{Group: "certificates.k8s.io", Kind: "ClusterTrustBundle"},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "FlowSchema"},
+	{Group: "flowcontrol.apiserver.k8s.io", Kind: "PriorityLevelConfiguration"},
+	{Group: "networking.k8s.io", Kind: "IngressClass"},
+	{Group: "networking.k8s.io", Kind: "IPAddress"},
+	{Group: "networking.k8s.io", Kind: "ServiceCIDR"},
+	{Group: "node.k8s.io", Kind: "RuntimeClass"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	{Group: "rbac.authorization.k8s.io", Kind: "ClusterRoleBinding"},
+	{Group: "resource.k8s.io", Kind: "ResourceClass"},
+	{Group: "scheduling.k8s.io", Kind: "PriorityClass"},
+	{Group: "storage.k8s.io", Kind: "StorageClass"},
+	{Group: "storage.k8s.io", Kind: "CSIDriver"},
+	{Group: "storage.k8s.io", Kind: "CSINode"},
+	{Group: "storage.k8s.io", Kind: "VolumeAttachment"},
+	{Group: "storage.k8s.io", Kind: "VolumeAttributesClass"},
+	{Group: "storagemigration.k8s.io", Kind: "StorageVersionMigration"},
+}
+
+var customResourceDefinitionKind = schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+
+// LoadManifests reads the objects of the YAML or JSON manifests at path, which is a multi-document file, a directory
+// searched for .yaml, .yml and .json files, or StdinManifestPath. Kinds that are not built into kubernetes are kept
+// unstructured, so that custom resources are placed by the scope their CustomResourceDefinition declares.
+func LoadManifests(path string) ([]runtime.Object, error) {
+	if path == StdinManifestPath {
+		return decodeManifests(os.Stdin, "stdin")
+	}
+	objects := []runtime.Object{}
+	err := filepath.WalkDir(path, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || !slices.Contains([]string{".yaml", ".yml", ".json"}, filepath.Ext(filePath)) {
+			return nil
+		}
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		decoded, err := decodeManifests(file, filePath)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, decoded...)
+		return nil
+	})
+	return objects, err
+}
+
+// decodeManifests decodes every document of the stream into its typed object, expanding lists
+func decodeManifests(reader io.Reader, source string) ([]runtime.Object, error) {
+	objects := []runtime.Object{}
+	decoder := yaml.NewYAMLOrJSONDecoder(reader, 4096)
+	for {
+		u := &unstructured.Unstructured{}
+		if err := decoder.Decode(&u.Object); errors.Is(err, io.EOF) {
+			return objects, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid manifest in %s: %w", source, err)
+		}
+		if len(u.Object) == 0 {
+			continue // empty document, i.e. a template that rendered nothing
+		}
+		items := []unstructured.Unstructured{*u}
+		if u.IsList() {
+			list, err := u.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("invalid list in %s: %w", source, err)
+			}
+			items = list.Items
+		}
+		for _, item := range items {
+			obj, err := scheme.Scheme.New(item.GroupVersionKind())
+			if err != nil {
+				objects = append(objects, item.DeepCopy())
+				continue
+			}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, obj); err != nil {
+				return nil, fmt.Errorf("invalid %s %s in %s: %w", item.GetKind(), item.GetName(), source, err)
+			}
+			objects = append(objects, obj)
+		}
+	}
+}
+
+// CreateManifestSource returns a Watcher over an in-memory clientset holding the manifests and the objects their
+// controllers would create from them, so that the ownership and selector graph is drawn without a cluster. Custom
+// resources whose CustomResourceDefinition is among the manifests are served through an in-memory dynamic client.
+func CreateManifestSource(watchConfig *WatchConfig, objects []runtime.Object) (*Watcher, error) {
+	definitions := customResourceDefinitions(objects)
+	builtIn := []runtime.Object{}
+	customResources := map[schema.GroupVersionResource][]*unstructured.Unstructured{}
+	for _, obj := range ExpandManifests(objects) {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			builtIn = append(builtIn, obj)
+			continue
+		}
+		gvk := u.GroupVersionKind()
+		definition, found := definitions[gvk.GroupKind()]
+		if !found {
+			if gvk.GroupKind() != customResourceDefinitionKind {
+				log.Printf("Skipping %s %s, its CustomResourceDefinition is not among the manifests\n", gvk.Kind, u.GetName())
+			}
+			continue
+		}
+		gvr := gvk.GroupVersion().WithResource(definition.Name)
+		customResources[gvr] = append(customResources[gvr], u)
+	}
+
+	clientset := fake.NewSimpleClientset(builtIn...)
+	watcher := &Watcher{}
+	watcher.Init(clientset)
+	if len(customResources) > 0 {
+		listKinds := map[schema.GroupVersionResource]string{}
+		for gvr, resources := range customResources {
+			resource := definitions[resources[0].GroupVersionKind().GroupKind()]
+			listKinds[gvr] = resource.Kind + "List"
+			clientset.Resources = append(clientset.Resources, &metav1.APIResourceList{
+				GroupVersion: gvr.GroupVersion().String(),
+				APIResources: []metav1.APIResource{resource},
+			})
+			watcher.CustomResources = append(watcher.CustomResources, gvr)
+		}
+		dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+		for gvr, resources := range customResources {
+			for _, u := range resources {
+				if err := dynamicClient.Tracker().Create(gvr, u, u.GetNamespace()); err != nil {
+					return nil, fmt.Errorf("invalid %s %s: %w", u.GetKind(), u.GetName(), err)
+				}
+			}
+		}
+		watcher.DynamicClient = dynamicClient
+	}
+	if err := watcher.SetScope(watchConfig.Scope); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// ExpandManifests places the objects in their namespaces, keeping the last of objects declared twice, and derives what a
+// cluster would add: namespaces, the ReplicaSets and pods of workloads, the claims of StatefulSets and the EndpointSlices
+// of services selecting pods. Custom resources are placed by the scope of their CustomResourceDefinition, and left
+// without a namespace when it is not among the objects.
+func ExpandManifests(objects []runtime.Object) []runtime.Object {
+	definitions := customResourceDefinitions(objects)
+	declared := map[string]int{}
+	expanded := []runtime.Object{}
+	for _, obj := range objects {
+		meta, err := metaOf(obj)
+		if err != nil {
+			continue
+		}
+		kind := kindOf(obj)
+		if len(meta.GetNamespace()) == 0 && isNamespaced(groupVersionKindOf(obj).GroupKind(), definitions) {
+			meta.SetNamespace(metav1.NamespaceDefault)
+		}
+		key := fmt.Sprintf("%s/%s/%s", kind, meta.GetNamespace(), meta.GetName())
+		if i, found := declared[key]; found {
+			expanded[i] = obj
+			continue
+		}
+		declared[key] = len(expanded)
+		expanded = append(expanded, obj)
+	}
+
+	// manifests exported from a cluster already hold what the controllers created
+	owners := map[string]bool{}
+	for _, obj := range expanded {
+		if meta, err := metaOf(obj); err == nil {
+			for _, ref := range meta.GetOwnerReferences() {
+				owners[fmt.Sprintf("%s/%s/%s", ref.Kind, meta.GetNamespace(), ref.Name)] = true
+			}
+		}
+	}
+
+	derived := []runtime.Object{}
+	namespaces := map[string]bool{}
+	for _, obj := range expanded {
+		if meta, err := metaOf(obj); err != nil || owners[fmt.Sprintf("%s/%s/%s", kindOf(obj), meta.GetNamespace(), meta.GetName())] {
+			continue
+		}
+		switch o := obj.(type) {
+		case *v1.Namespace:
+			namespaces[o.Name] = true
+		case *appsv1.Deployment:
+			derived = append(derived, deploymentReplicaSet(o)...)
+		case *appsv1.ReplicaSet:
+			derived = append(derived, templatePods(o.ObjectMeta, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"), o.Spec.Replicas, o.Spec.Template)...)
+		case *appsv1.StatefulSet:
+			derived = append(derived, statefulSetPods(o)...)
+		case *batchv1.Job:
+			derived = append(derived, templatePods(o.ObjectMeta, batchv1.SchemeGroupVersion.WithKind("Job"), o.Spec.Parallelism, o.Spec.Template)...)
+		}
+	}
+	expanded = append(expanded, derived...)
+
+	pods := []*v1.Pod{}
+	for _, obj := range expanded {
+		if pod, ok := obj.(*v1.Pod); ok {
+			pods = append(pods, pod)
+		}
+	}
+	for _, obj := range slices.Clone(expanded) {
+		if service, ok := obj.(*v1.Service); ok && len(service.Spec.Selector) > 0 {
+			expanded = append(expanded, serviceEndpointSlice(service, pods))
+		}
+		if _, custom := obj.(*unstructured.Unstructured); custom && !isDefined(obj, definitions) {
+			continue // not drawn, so it does not make a namespace of its own
+		}
+		if meta, err := metaOf(obj); err == nil && len(meta.GetNamespace()) > 0 && !namespaces[meta.GetNamespace()] {
+			namespaces[meta.GetNamespace()] = true
+			expanded = append(expanded, &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: meta.GetNamespace()}})
+		}
+	}
+	return expanded
+}
+
+// customResourceDefinitions reads the resource of each CustomResourceDefinition among the objects, by the group and
+// kind of its custom resources
+func customResourceDefinitions(objects []runtime.Object) map[schema.GroupKind]metav1.APIResource {
+	definitions := map[schema.GroupKind]metav1.APIResource{}
+	for _, obj := range objects {
+		crd, ok := obj.(*unstructured.Unstructured)
+		if !ok || crd.GroupVersionKind().GroupKind() != customResourceDefinitionKind {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+		plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+		scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+		definitions[schema.GroupKind{Group: group, Kind: kind}] = metav1.APIResource{Name: plural, Kind: kind, Namespaced: scope == "Namespaced"}
+	}
+	return definitions
+}
+
+// isDefined reports whether the object is a custom resource of a CustomResourceDefinition among the objects
+func isDefined(obj runtime.Object, definitions map[schema.GroupKind]metav1.APIResource) bool {
+	_, found := definitions[groupVersionKindOf(obj).GroupKind()]
+	return found
+}
+
+// isNamespaced reports whether objects of the kind live in a namespace. Kinds that are neither built in nor declared by
+// a CustomResourceDefinition are not, as their scope is unknown.
+func isNamespaced(groupKind schema.GroupKind, definitions map[schema.GroupKind]metav1.APIResource) bool {
+	if slices.Contains(clusterScopedKinds, groupKind) {
+		return false
+	}
+	if scheme.Scheme.IsGroupRegistered(groupKind.Group) {
+		return true
+	}
+	return definitions[groupKind].Namespaced
+}
+
+// groupVersionKindOf looks the kind up in the scheme, objects built in code and by informers do not carry it in their
+// TypeMeta
+func groupVersionKindOf(obj runtime.Object) schema.GroupVersionKind {
+	if gvks, _, err := scheme.Scheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+		return gvks[0]
+	}
+	return obj.GetObjectKind().GroupVersionKind()
+}
+
+func kindOf(obj runtime.Object) string {
+	return groupVersionKindOf(obj).Kind
+}
+
+func metaOf(obj runtime.Object) (metav1.Object, error) {
+	accessor, ok := obj.(metav1.Object)
+	if !ok {
+		return nil, fmt.Errorf("%T has no object meta", obj)
+	}
+	return accessor, nil
+}
+
+// shortHash stands in for the random suffixes controllers give the objects they create
+func shortHash(s string, length int) string {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return rand.SafeEncodeString(fmt.Sprintf("%010d", h.Sum32()))[:length]
+}
+
+func controllerRef(owner metav1.ObjectMeta, gvk schema.GroupVersionKind) []metav1.OwnerReference {
+	return []metav1.OwnerReference{*metav1.NewControllerRef(&owner, gvk)}
+}
+
+func replicasOrDefault(replicas *int32) int32 {
+	if replicas == nil {
+		return 1
+	}
+	return *replicas
+}
+
+// deploymentReplicaSet is the ReplicaSet of the current revision of the deployment, with its pods
+func deploymentReplicaSet(deploy *appsv1.Deployment) []runtime.Object {
+	spec, _ := json.Marshal(deploy.Spec.Template)
+	hash := shortHash(fmt.Sprintf("%s/%s/%s", deploy.Namespace, deploy.Name, spec), 10)
+	template := *deploy.Spec.Template.DeepCopy()
+	template.Labels = labels.Merge(template.Labels, labels.Set{appsv1.DefaultDeploymentUniqueLabelKey: hash})
+	rs := &appsv1.ReplicaSet{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apps/v1", Kind: "ReplicaSet"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%s", deploy.Name, hash),
+			Namespace:       deploy.Namespace,
+			Labels:          template.Labels,
+			Annotations:     map[string]string{RevisionAnnotation: "1"},
+			OwnerReferences: controllerRef(deploy.ObjectMeta, appsv1.SchemeGroupVersion.WithKind("Deployment")),
+		},
+		Spec: appsv1.ReplicaSetSpec{Replicas: deploy.Spec.Replicas, Selector: deploy.Spec.Selector, Template: template},
+	}
+	return append([]runtime.Object{rs}, templatePods(rs.ObjectMeta, appsv1.SchemeGroupVersion.WithKind("ReplicaSet"), rs.Spec.Replicas, template)...)
+}
+
+// templatePods are the pending pods the owner would create from its template
+func templatePods(owner metav1.ObjectMeta, ownerKind schema.GroupVersionKind, replicas *int32, template v1.PodTemplateSpec) []runtime.Object {
+	pods := []runtime.Object{}
+	for i := range replicasOrDefault(replicas) {
+		pods = append(pods, &v1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("%s-%s", owner.Name, shortHash(fmt.Sprintf("%s/%s/%d", owner.Namespace, owner.Name, i), 5)),
+				Namespace:       owner.Namespace,
+				Labels:          template.Labels,
+				OwnerReferences: controllerRef(owner, ownerKind),
+			},
+			Spec:   template.Spec,
+			Status: v1.PodStatus{Phase: v1.PodPending},
+		})
+	}
+	return pods
+}
+
+// statefulSetPods are the ordinal pods of the StatefulSet, each with a claim per volume claim template
+func statefulSetPods(sts *appsv1.StatefulSet) []runtime.Object {
+	objects := []runtime.Object{}
+	for i := range replicasOrDefault(sts.Spec.Replicas) {
+		podName := fmt.Sprintf("%s-%d", sts.Name, i)
+		spec := *sts.Spec.Template.Spec.DeepCopy()
+		for _, template := range sts.Spec.VolumeClaimTemplates {
+			claimName := fmt.Sprintf("%s-%s", template.Name, podName)
+			objects = append(objects, &v1.PersistentVolumeClaim{
+				TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+				ObjectMeta: metav1.ObjectMeta{Name: claimName, Namespace: sts.Namespace, Labels: sts.Spec.Template.Labels},
+				Spec:       template.Spec,
+				Status:     v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+			})
+			spec.Volumes = append(spec.Volumes, v1.Volume{
+				Name:         template.Name,
+				VolumeSource: v1.VolumeSource{PersistentVolumeClaim: &v1.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
+			})
+		}
+		objects = append(objects, &v1.Pod{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            podName,
+				Namespace:       sts.Namespace,
+				Labels:          sts.Spec.Template.Labels,
+				OwnerReferences: controllerRef(sts.ObjectMeta, appsv1.SchemeGroupVersion.WithKind("StatefulSet")),
+			},
+			Spec:   spec,
+			Status: v1.PodStatus{Phase: v1.PodPending},
+		})
+	}
+	return objects
+}
+
+// serviceEndpointSlice lists the pods the service selects, none of which are ready offline
+func serviceEndpointSlice(service *v1.Service, pods []*v1.Pod) *discoveryv1.EndpointSlice {
+	ready := false
+	selector := labels.SelectorFromSet(service.Spec.Selector)
+	slice := &discoveryv1.EndpointSlice{
+		TypeMeta: metav1.TypeMeta{APIVersion: "discovery.k8s.io/v1", Kind: "EndpointSlice"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", service.Name, shortHash(service.Namespace+"/"+service.Name, 5)),
+			Namespace: service.Namespace,
+			Labels:    map[string]string{discoveryv1.LabelServiceName: service.Name},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for _, pod := range pods {
+		if pod.Namespace != service.Namespace || !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready},
+			TargetRef:  &v1.ObjectReference{Kind: "Pod", Name: pod.Name, Namespace: pod.Namespace},
+		})
+	}
+	return slice
+}
+
+// ManifestClusterName names the cluster object frame after the manifests
+func ManifestClusterName(path string) string {
+	if path == StdinManifestPath {
+		return "manifests"
+	}
+	return strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+}
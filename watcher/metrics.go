@@ -0,0 +1,144 @@
+package watcher
+
+import (
+	"context"
+	"log"
+	"reflect"
+	"time"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DefaultMetricsPollPeriod matches the resolution metrics-server scrapes the kubelets at by default
+const DefaultMetricsPollPeriod = 15 * time.Second
+
+// ResourceUsageName names the usage pushed after every poll of metrics.k8s.io
+const ResourceUsageName = "metrics.k8s.io"
+
+var (
+	metricsGroupVersion = schema.GroupVersion{Group: "metrics.k8s.io", Version: "v1beta1"}
+	podMetricsResource  = metricsGroupVersion.WithResource("pods")
+	nodeMetricsResource = metricsGroupVersion.WithResource("nodes")
+)
+
+// WatchMetrics polls the CPU and memory usage of every pod and node from metrics.k8s.io until ctx is done, pushing it
+// whenever it changes. Clusters without metrics-server are drawn without usage.
+func (watcher *Watcher) WatchMetrics(ctx context.Context) {
+	if watcher.DynamicClient == nil {
+		return
+	}
+	if _, err := watcher.Client.Discovery().ServerResourcesForGroupVersion(metricsGroupVersion.String()); err != nil {
+		log.Printf("%s is not served, usage is not drawn: %v\n", metricsGroupVersion.String(), err)
+		return
+	}
+	if !watcher.beginRunning(ctx) {
+		return
+	}
+	defer watcher.running.Done()
+
+	ticker := time.NewTicker(watcher.MetricsPollPeriod)
+	defer ticker.Stop()
+	var last *gkube.GResourceUsageStatus
+	lastErr := ""
+	for {
+		usage, err := watcher.pollMetrics(ctx)
+		if err == nil {
+			lastErr = ""
+			if !reflect.DeepEqual(usage, last) {
+				last = usage
+				watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GRESOURCEUSAGE, ResourceUsageName, "", gkube.GNONE, gkube.GSETTING_NONE, nil, usage, -1, nil)
+			}
+		} else if err.Error() != lastErr && ctx.Err() == nil {
+			lastErr = err.Error() // logged once until the error changes
+			log.Printf("Could not poll %s: %v\n", metricsGroupVersion.String(), err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollMetrics lists the PodMetrics of the workloads in scope and every NodeMetrics
+func (watcher *Watcher) pollMetrics(ctx context.Context) (*gkube.GResourceUsageStatus, error) {
+	options := metav1.ListOptions{}
+	watcher.workloadListOptions(&options)
+	pods, err := watcher.DynamicClient.Resource(podMetricsResource).Namespace(metav1.NamespaceAll).List(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := watcher.DynamicClient.Resource(nodeMetricsResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	usage := &gkube.GResourceUsageStatus{Pods: map[string]gkube.GResourceUsage{}, Containers: map[string]map[string]gkube.GResourceUsage{}, Nodes: map[string]gkube.GResourceUsage{}}
+	for _, pod := range pods.Items {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "containers")
+		podUsage := gkube.GResourceUsage{}
+		containerUsages := map[string]gkube.GResourceUsage{}
+		for _, container := range containers {
+			if c, ok := container.(map[string]interface{}); ok {
+				containerUsage := parseUsage(c)
+				podUsage.CPU += containerUsage.CPU
+				podUsage.Memory += containerUsage.Memory
+				name, _, _ := unstructured.NestedString(c, "name")
+				containerUsages[name] = containerUsage
+			}
+		}
+		usage.Pods[pointKey(pod.GetNamespace(), pod.GetName())] = podUsage
+		usage.Containers[pointKey(pod.GetNamespace(), pod.GetName())] = containerUsages
+	}
+	for _, node := range nodes.Items {
+		usage.Nodes[node.GetName()] = parseUsage(node.Object)
+	}
+	return usage, nil
+}
+
+// parseUsage reads the usage of a PodMetrics container or of a NodeMetrics, ignoring quantities that do not parse
+func parseUsage(metrics map[string]interface{}) gkube.GResourceUsage {
+	quantities, _, _ := unstructured.NestedStringMap(metrics, "usage")
+	usage := gkube.GResourceUsage{}
+	if cpu, err := resource.ParseQuantity(quantities[string(v1.ResourceCPU)]); err == nil {
+		usage.CPU = cpu.MilliValue()
+	}
+	if memory, err := resource.ParseQuantity(quantities[string(v1.ResourceMemory)]); err == nil {
+		usage.Memory = memory.Value()
+	}
+	return usage
+}
+
+// resourceAmounts converts a resource list into millicores of CPU and bytes of memory
+func resourceAmounts(list v1.ResourceList) gkube.GResourceUsage {
+	return gkube.GResourceUsage{CPU: list.Cpu().MilliValue(), Memory: list.Memory().Value()}
+}
+
+// podResources sums the requests and limits of the containers of a pod. A limit is only summed when every container
+// sets it, as the pod is otherwise unbounded. Containers are OOM-killed by their own limit, which their status carries.
+func podResources(pod *v1.Pod) (gkube.GResourceUsage, gkube.GResourceUsage) {
+	requests := gkube.GResourceUsage{}
+	limits := gkube.GResourceUsage{}
+	cpuLimited, memoryLimited := true, true
+	for _, container := range pod.Spec.Containers {
+		containerRequests := resourceAmounts(container.Resources.Requests)
+		containerLimits := resourceAmounts(container.Resources.Limits)
+		requests.CPU += containerRequests.CPU
+		requests.Memory += containerRequests.Memory
+		limits.CPU += containerLimits.CPU
+		limits.Memory += containerLimits.Memory
+		cpuLimited = cpuLimited && containerLimits.CPU > 0
+		memoryLimited = memoryLimited && containerLimits.Memory > 0
+	}
+	if !cpuLimited {
+		limits.CPU = 0
+	}
+	if !memoryLimited {
+		limits.Memory = 0
+	}
+	return requests, limits
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
+	"strings"
+	"sync"
 
 	"github.com/kabicin/kubechaser/renderer/gkube"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type NamespacePoint struct {
@@ -43,59 +44,121 @@ func (watcher *Watcher) ParseNamespace(rawNamespace map[string]interface{}) (*v1
 	return ns, nil
 }
 
-func (watcher *Watcher) WatchNamespaces() {
-	watcher.ClientMutex.Lock()
-	watchInterface, _ := watcher.Client.CoreV1().Namespaces().Watch(context.TODO(), metav1.ListOptions{Watch: true})
-	ch := watchInterface.ResultChan()
-	watcher.ClientMutex.Unlock()
-	for {
-		select {
-		case e, ok := <-ch:
-			if !ok {
-				return
-			}
-			if e.Type == watch.Added {
-				rawNamespace, _ := watcher.ToUnstructuredSync(e.Object)
-				ns, err := watcher.ParseNamespace(rawNamespace)
-				if err != nil {
-					return
-				}
+func (watcher *Watcher) WatchNamespaces(ctx context.Context) {
+	informer := coreinformers.NewFilteredNamespaceInformer(watcher.Client, ResyncPeriod, cache.Indexers{}, watcher.namespaceListOptions)
+	watcher.runInformer(ctx, "namespaces", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onNamespaceAdded,
+		DeleteFunc: watcher.onNamespaceDeleted,
+	})
+}
 
-				nsName := ns.GetName()
-				_, found := watcher.NamespacePoints.Load(nsName)
-				if !found {
-					ns, err := watcher.ParseNamespace(rawNamespace)
-					if err != nil {
-						continue
-					}
-					// add namespace point
-					watcher.NamespacePoints.Store(nsName, ParseNamespacePoint(ns))
-					watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, nsName, ns.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GNamespaceObjectFrameStatus{}, -1, rawNamespace)
-					log.Println("ADDED namespace " + nsName)
+func (watcher *Watcher) onNamespaceAdded(obj interface{}) {
+	ns, ok := obj.(*v1.Namespace)
+	if !ok {
+		return
+	}
+	nsName := ns.GetName()
+	if scope := watcher.getScope(); !scope.IncludesNamespace(nsName) {
+		return
+	}
+	rawNamespace, err := watcher.ToUnstructuredSync(ns)
+	if err != nil {
+		log.Println(err)
+		return
+	}
 
-					// Add watchers for this namespace
-					go watcher.WatchDeployments(nsName)
-					go watcher.WatchReplicaSets(nsName)
-					go watcher.WatchPods(nsName)
+	_, found := watcher.NamespacePoints.Load(nsName)
+	if !found {
+		// add namespace point
+		watcher.NamespacePoints.Store(nsName, ParseNamespacePoint(ns))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, nsName, ns.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GNamespaceObjectFrameStatus{}, -1, rawNamespace)
+		log.Println("ADDED namespace " + nsName)
 
-				}
-			} else if e.Type == watch.Deleted {
-				rawNamespace, _ := watcher.ToUnstructuredSync(e.Object)
-				ns, err := watcher.ParseNamespace(rawNamespace)
-				if err != nil {
-					return
-				}
-				nsName := ns.GetName()
-				_, found := watcher.NamespacePoints.Load(nsName)
-				if found {
-					// delete namespace point
-					watcher.NamespacePoints.Delete(nsName)
-					log.Println("DELETED namespace " + nsName)
-				}
+		// Add watchers for this namespace, stopped by unwatchNamespace
+		group := watcher.newInformerGroup(nil)
+		watcher.namespaceWatches.Store(nsName, group)
+		watcher.watchNamespace(group.ctx, nsName)
+	}
+}
 
-			}
-		case <-time.After(30 * time.Minute):
-			return
+// watchNamespace starts every watcher of a namespace
+func (watcher *Watcher) watchNamespace(ctx context.Context, nsName string) {
+	go watcher.WatchDeployments(ctx, nsName)
+	go watcher.WatchReplicaSets(ctx, nsName)
+	go watcher.WatchPods(ctx, nsName)
+	go watcher.WatchServices(ctx, nsName)
+	go watcher.WatchEndpointSlices(ctx, nsName)
+	go watcher.WatchIngresses(ctx, nsName)
+	go watcher.WatchStatefulSets(ctx, nsName)
+	go watcher.WatchPersistentVolumeClaims(ctx, nsName)
+	go watcher.WatchDaemonSets(ctx, nsName)
+	go watcher.WatchJobs(ctx, nsName)
+	go watcher.WatchCronJobs(ctx, nsName)
+	go watcher.WatchHorizontalPodAutoscalers(ctx, nsName)
+	go watcher.WatchConfigMaps(ctx, nsName)
+	go watcher.WatchSecrets(ctx, nsName)
+	go watcher.WatchServiceAccounts(ctx, nsName)
+	go watcher.WatchRoles(ctx, nsName)
+	go watcher.WatchRoleBindings(ctx, nsName)
+	go watcher.WatchCustomResources(ctx, nsName, true)
+	go watcher.WatchEvents(ctx, nsName)
+}
+
+func (watcher *Watcher) onNamespaceDeleted(obj interface{}) {
+	ns, ok := unwrapTombstone(obj).(*v1.Namespace)
+	if !ok {
+		return
+	}
+	watcher.unwatchNamespace(ns.GetName())
+}
+
+// unwatchNamespace stops the watchers of a namespace that was deleted or left the scope, waits for their handlers to
+// return, then forgets what they saw and removes the namespace from the scene
+func (watcher *Watcher) unwatchNamespace(nsName string) {
+	_, found := watcher.NamespacePoints.Load(nsName)
+	if found {
+		// delete namespace point
+		watcher.NamespacePoints.Delete(nsName)
+		if group, found := watcher.namespaceWatches.LoadAndDelete(nsName); found {
+			group.(*informerGroup).stop()
 		}
+		watcher.forgetNamespacePoints(nsName)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GNAMESPACEOBJECTFRAME, nsName, "", gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GNamespaceObjectFrameStatus{}, -1, nil)
+		log.Println("DELETED namespace " + nsName)
+	}
+}
+
+// forgetNamespacePoints drops the points of an unwatched namespace, so that it is pushed again from scratch if it is
+// re-created or re-enters the scope
+func (watcher *Watcher) forgetNamespacePoints(nsName string) {
+	namespacedPoints := []*sync.Map{
+		watcher.DeploymentPoints,
+		watcher.ReplicaSetPoints,
+		watcher.PodPoints,
+		watcher.ServicePoints,
+		watcher.EndpointSlices,
+		watcher.IngressPoints,
+		watcher.StatefulSetPoints,
+		watcher.PersistentVolumeClaimPoints,
+		watcher.DaemonSetPoints,
+		watcher.JobPoints,
+		watcher.CronJobPoints,
+		watcher.HorizontalPodAutoscalerPoints,
+		watcher.ConfigMapPoints,
+		watcher.SecretPoints,
+		watcher.ServiceAccountPoints,
+		watcher.RolePoints,
+		watcher.RoleBindingPoints,
+		watcher.CustomResourcePoints,
+		watcher.EventPoints,
+	}
+	prefix := pointKey(nsName, "")
+	for _, points := range namespacedPoints {
+		points.Range(func(key, value any) bool {
+			if strings.HasPrefix(key.(string), prefix) {
+				points.Delete(key)
+			}
+			return true
+		})
 	}
 }
@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type NodePoint struct {
+	WatchPoint
+	Labels        map[string]string
+	Taints        []v1.Taint
+	Unschedulable bool
+	Ready         bool
+	Pressures     []string
+	Allocatable   gkube.GResourceUsage
+}
+
+func (p *NodePoint) String() string {
+	return fmt.Sprintf("Node %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *NodePoint) Init(obj *v1.Node) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Labels = obj.GetLabels()
+	p.Taints = obj.Spec.Taints
+	p.Unschedulable = obj.Spec.Unschedulable
+	p.Ready, p.Pressures = nodeConditions(obj)
+	p.Allocatable = resourceAmounts(obj.Status.Allocatable)
+}
+
+func ParseNodePoint(d *v1.Node) *NodePoint {
+	p := &NodePoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseNode(rawNode map[string]interface{}) (*v1.Node, error) {
+	ns := &v1.Node{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawNode, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func (p *NodePoint) sameScheduling(o *NodePoint) bool {
+	return reflect.DeepEqual(p.Labels, o.Labels) && reflect.DeepEqual(p.Taints, o.Taints) && p.Unschedulable == o.Unschedulable
+}
+
+func (p *NodePoint) sameConditions(o *NodePoint) bool {
+	return p.Ready == o.Ready && slices.Equal(p.Pressures, o.Pressures) && p.Allocatable == o.Allocatable
+}
+
+// nodeConditions reports whether the node is Ready, and which of its other conditions are True. An Unknown Ready
+// condition, i.e. when the kubelet stopped posting status, counts as NotReady.
+func nodeConditions(node *v1.Node) (bool, []string) {
+	ready := false
+	pressures := []string{}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			ready = condition.Status == v1.ConditionTrue
+		} else if condition.Status == v1.ConditionTrue {
+			pressures = append(pressures, string(condition.Type))
+		}
+	}
+	slices.Sort(pressures)
+	return ready, pressures
+}
+
+func CreateNodeStatus(p *NodePoint) *gkube.GNodeStatus {
+	return &gkube.GNodeStatus{
+		Ready:         p.Ready,
+		Unschedulable: p.Unschedulable,
+		Pressures:     p.Pressures,
+		Allocatable:   p.Allocatable,
+	}
+}
+
+// WatchNodes draws the nodes and keeps the node points that DaemonSet coverage is computed against
+func (watcher *Watcher) WatchNodes(ctx context.Context) {
+	informer := coreinformers.NewNodeInformer(watcher.Client, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "nodes", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onNodeAdded,
+		UpdateFunc: watcher.onNodeModified,
+		DeleteFunc: watcher.onNodeDeleted,
+	})
+}
+
+func (watcher *Watcher) onNodeAdded(obj interface{}) {
+	node, ok := obj.(*v1.Node)
+	if !ok {
+		return
+	}
+	rawNode, err := watcher.ToUnstructuredSync(node)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	nodeName := node.GetName()
+	_, found := watcher.NodePoints.Load(nodeName)
+	if !found {
+		// add node point
+		point := ParseNodePoint(node)
+		watcher.NodePoints.Store(nodeName, point)
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GNODE, nodeName, node.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateNodeStatus(point), -1, rawNode)
+		watcher.pushAllDaemonSetCoverage()
+		log.Println("ADDED node " + nodeName)
+	}
+}
+
+func (watcher *Watcher) onNodeModified(oldObj, newObj interface{}) {
+	node, ok := newObj.(*v1.Node)
+	if !ok {
+		return
+	}
+	nodeName := node.GetName()
+	point, found := watcher.NodePoints.Load(nodeName)
+	if !found {
+		watcher.onNodeAdded(node)
+		return
+	}
+	if point.(*NodePoint).ResourceVersion == node.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	// modify node point
+	newPoint := ParseNodePoint(node)
+	watcher.NodePoints.Store(nodeName, newPoint)
+	if newPoint.sameScheduling(point.(*NodePoint)) && newPoint.sameConditions(point.(*NodePoint)) {
+		return // skip status heartbeats, which change neither the node nor the coverage
+	}
+	if !newPoint.sameScheduling(point.(*NodePoint)) {
+		watcher.pushAllDaemonSetCoverage()
+	}
+	rawNode, err := watcher.ToUnstructuredSync(node)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GNODE, nodeName, node.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateNodeStatus(newPoint), -1, rawNode)
+	log.Println("MODIFIED node " + nodeName)
+}
+
+func (watcher *Watcher) onNodeDeleted(obj interface{}) {
+	node, ok := unwrapTombstone(obj).(*v1.Node)
+	if !ok {
+		return
+	}
+	nodeName := node.GetName()
+	point, found := watcher.NodePoints.Load(nodeName)
+	if found {
+		// delete node point
+		watcher.NodePoints.Delete(nodeName)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GNODE, nodeName, node.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateNodeStatus(point.(*NodePoint)), -1, nil)
+		watcher.pushAllDaemonSetCoverage()
+		log.Println("DELETED node " + nodeName)
+	}
+}
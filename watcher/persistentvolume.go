@@ -0,0 +1,138 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type PersistentVolumePoint struct {
+	WatchPoint
+	Phase string
+}
+
+func (p *PersistentVolumePoint) String() string {
+	return fmt.Sprintf("PersistentVolume %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *PersistentVolumePoint) Init(obj *v1.PersistentVolume) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Phase = string(obj.Status.Phase)
+}
+
+func ParsePersistentVolumePoint(d *v1.PersistentVolume) *PersistentVolumePoint {
+	p := &PersistentVolumePoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParsePersistentVolume(rawPersistentVolume map[string]interface{}) (*v1.PersistentVolume, error) {
+	ns := &v1.PersistentVolume{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawPersistentVolume, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func CreatePersistentVolumeStatus(pv *v1.PersistentVolume) *gkube.GPersistentVolumeStatus {
+	status := &gkube.GPersistentVolumeStatus{
+		Phase:            string(pv.Status.Phase),
+		StorageClassName: pv.Spec.StorageClassName,
+		AccessModes:      accessModeNames(pv.Spec.AccessModes),
+		ReclaimPolicy:    string(pv.Spec.PersistentVolumeReclaimPolicy),
+	}
+	if capacity, found := pv.Spec.Capacity[v1.ResourceStorage]; found {
+		status.Capacity = capacity.String()
+	}
+	if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+		status.ClaimName = claimRef.Name
+		status.ClaimNamespace = claimRef.Namespace
+	}
+	return status
+}
+
+// PersistentVolumes are cluster-scoped and watched once for the whole cluster
+func (watcher *Watcher) WatchPersistentVolumes(ctx context.Context) {
+	informer := coreinformers.NewPersistentVolumeInformer(watcher.Client, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "persistentvolumes", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onPersistentVolumeAdded,
+		UpdateFunc: watcher.onPersistentVolumeModified,
+		DeleteFunc: watcher.onPersistentVolumeDeleted,
+	})
+}
+
+func (watcher *Watcher) onPersistentVolumeAdded(obj interface{}) {
+	pv, ok := obj.(*v1.PersistentVolume)
+	if !ok {
+		return
+	}
+	rawPersistentVolume, err := watcher.ToUnstructuredSync(pv)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	pvName := pv.GetName()
+	key := pointKey(pv.Namespace, pvName)
+	_, found := watcher.PersistentVolumePoints.Load(key)
+	if !found {
+		// add pv point
+		watcher.PersistentVolumePoints.Store(key, ParsePersistentVolumePoint(pv))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GPERSISTENTVOLUME, pvName, pv.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreatePersistentVolumeStatus(pv), -1, rawPersistentVolume)
+		log.Println("ADDED pv " + pvName)
+	}
+}
+
+func (watcher *Watcher) onPersistentVolumeModified(oldObj, newObj interface{}) {
+	pv, ok := newObj.(*v1.PersistentVolume)
+	if !ok {
+		return
+	}
+
+	pvName := pv.GetName()
+	key := pointKey(pv.Namespace, pvName)
+	point, found := watcher.PersistentVolumePoints.Load(key)
+	if !found {
+		watcher.onPersistentVolumeAdded(pv)
+		return
+	}
+	if point.(*PersistentVolumePoint).ResourceVersion == pv.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawPersistentVolume, err := watcher.ToUnstructuredSync(pv)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify pv point
+	watcher.PersistentVolumePoints.Store(key, ParsePersistentVolumePoint(pv))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GPERSISTENTVOLUME, pvName, pv.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreatePersistentVolumeStatus(pv), -1, rawPersistentVolume)
+	log.Println("MODIFIED pv " + pvName)
+}
+
+func (watcher *Watcher) onPersistentVolumeDeleted(obj interface{}) {
+	pv, ok := unwrapTombstone(obj).(*v1.PersistentVolume)
+	if !ok {
+		return
+	}
+	pvName := pv.GetName()
+	key := pointKey(pv.Namespace, pvName)
+	_, found := watcher.PersistentVolumePoints.Load(key)
+	if found {
+		// delete pv point
+		watcher.PersistentVolumePoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GPERSISTENTVOLUME, pvName, pv.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreatePersistentVolumeStatus(pv), -1, nil)
+		log.Println("DELETED pv " + pvName)
+	}
+}
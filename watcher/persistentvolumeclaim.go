@@ -0,0 +1,165 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type PersistentVolumeClaimPoint struct {
+	WatchPoint
+	Phase string
+}
+
+func (p *PersistentVolumeClaimPoint) String() string {
+	return fmt.Sprintf("PersistentVolumeClaim %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *PersistentVolumeClaimPoint) Init(obj *v1.PersistentVolumeClaim) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Phase = string(obj.Status.Phase)
+}
+
+func ParsePersistentVolumeClaimPoint(d *v1.PersistentVolumeClaim) *PersistentVolumeClaimPoint {
+	p := &PersistentVolumeClaimPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParsePersistentVolumeClaim(rawPersistentVolumeClaim map[string]interface{}) (*v1.PersistentVolumeClaim, error) {
+	ns := &v1.PersistentVolumeClaim{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawPersistentVolumeClaim, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreatePersistentVolumeClaimStatus derives the GPersistentVolumeClaimStatus of a claim. Claims generated from the
+// volumeClaimTemplates of a StatefulSet are named <template>-<statefulset>-<ordinal>, so the ordinal is kept as Index to
+// order them in the row of the StatefulSet.
+func (watcher *Watcher) CreatePersistentVolumeClaimStatus(pvc *v1.PersistentVolumeClaim) *gkube.GPersistentVolumeClaimStatus {
+	status := &gkube.GPersistentVolumeClaimStatus{
+		Phase:       string(pvc.Status.Phase),
+		VolumeName:  pvc.Spec.VolumeName,
+		Index:       parseOrdinal(pvc.GetName()),
+		AccessModes: accessModeNames(pvc.Spec.AccessModes),
+		CustomOwner: watcher.customOwner(pvc.Namespace, pvc.OwnerReferences),
+	}
+	if pvc.Spec.StorageClassName != nil {
+		status.StorageClassName = *pvc.Spec.StorageClassName
+	}
+	if capacity, found := pvc.Status.Capacity[v1.ResourceStorage]; found {
+		status.Capacity = capacity.String()
+	} else if request, found := pvc.Spec.Resources.Requests[v1.ResourceStorage]; found {
+		status.Capacity = request.String()
+	}
+	if len(pvc.Status.AccessModes) > 0 {
+		status.AccessModes = accessModeNames(pvc.Status.AccessModes)
+	}
+	return status
+}
+
+// the abbreviations kubectl prints for access modes
+var accessModeShortNames = map[v1.PersistentVolumeAccessMode]string{
+	v1.ReadWriteOnce:    "RWO",
+	v1.ReadOnlyMany:     "ROX",
+	v1.ReadWriteMany:    "RWX",
+	v1.ReadWriteOncePod: "RWOP",
+}
+
+func accessModeNames(accessModes []v1.PersistentVolumeAccessMode) []string {
+	names := []string{}
+	for _, accessMode := range accessModes {
+		if name, found := accessModeShortNames[accessMode]; found {
+			names = append(names, name)
+		} else {
+			names = append(names, string(accessMode))
+		}
+	}
+	return names
+}
+
+func (watcher *Watcher) WatchPersistentVolumeClaims(ctx context.Context, nsName string) {
+	informer := coreinformers.NewPersistentVolumeClaimInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "persistentvolumeclaims", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onPersistentVolumeClaimAdded,
+		UpdateFunc: watcher.onPersistentVolumeClaimModified,
+		DeleteFunc: watcher.onPersistentVolumeClaimDeleted,
+	})
+}
+
+func (watcher *Watcher) onPersistentVolumeClaimAdded(obj interface{}) {
+	pvc, ok := obj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	rawPersistentVolumeClaim, err := watcher.ToUnstructuredSync(pvc)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	pvcName := pvc.GetName()
+	key := pointKey(pvc.Namespace, pvcName)
+	_, found := watcher.PersistentVolumeClaimPoints.Load(key)
+	if !found {
+		// add pvc point
+		watcher.PersistentVolumeClaimPoints.Store(key, ParsePersistentVolumeClaimPoint(pvc))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GPERSISTENTVOLUMECLAIM, pvcName, pvc.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePersistentVolumeClaimStatus(pvc), -1, rawPersistentVolumeClaim)
+		log.Println("ADDED pvc " + pvcName)
+	}
+}
+
+func (watcher *Watcher) onPersistentVolumeClaimModified(oldObj, newObj interface{}) {
+	pvc, ok := newObj.(*v1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+
+	pvcName := pvc.GetName()
+	key := pointKey(pvc.Namespace, pvcName)
+	point, found := watcher.PersistentVolumeClaimPoints.Load(key)
+	if !found {
+		watcher.onPersistentVolumeClaimAdded(pvc)
+		return
+	}
+	if point.(*PersistentVolumeClaimPoint).ResourceVersion == pvc.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawPersistentVolumeClaim, err := watcher.ToUnstructuredSync(pvc)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify pvc point
+	watcher.PersistentVolumeClaimPoints.Store(key, ParsePersistentVolumeClaimPoint(pvc))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GPERSISTENTVOLUMECLAIM, pvcName, pvc.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePersistentVolumeClaimStatus(pvc), -1, rawPersistentVolumeClaim)
+	log.Println("MODIFIED pvc " + pvcName)
+}
+
+func (watcher *Watcher) onPersistentVolumeClaimDeleted(obj interface{}) {
+	pvc, ok := unwrapTombstone(obj).(*v1.PersistentVolumeClaim)
+	if !ok {
+		return
+	}
+	pvcName := pvc.GetName()
+	key := pointKey(pvc.Namespace, pvcName)
+	_, found := watcher.PersistentVolumeClaimPoints.Load(key)
+	if found {
+		// delete pvc point
+		watcher.PersistentVolumeClaimPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GPERSISTENTVOLUMECLAIM, pvcName, pvc.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePersistentVolumeClaimStatus(pvc), -1, nil)
+		log.Println("DELETED pvc " + pvcName)
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
+	"slices"
+	"strings"
 
 	"github.com/kabicin/kubechaser/renderer/gkube"
 	v1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type PodPoint struct {
@@ -18,6 +19,10 @@ type PodPoint struct {
 	Up                int
 	NumInitContainers int
 	NumContainers     int
+	OwnerKind         string
+	OwnerName         string
+	NodeName          string
+	Ready             bool
 }
 
 func (p *PodPoint) String() string {
@@ -26,10 +31,32 @@ func (p *PodPoint) String() string {
 
 func (p *PodPoint) Init(obj *v1.Pod) {
 	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
 	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
 	p.ResourceVersion = obj.GetResourceVersion()
 	p.NumContainers = len(obj.Spec.Containers)
 	p.NumInitContainers = len(obj.Spec.InitContainers)
+	if ownerRefs := obj.GetOwnerReferences(); len(ownerRefs) > 0 {
+		p.OwnerKind = ownerRefs[0].Kind
+		p.OwnerName = ownerRefs[0].Name
+	}
+	p.NodeName = obj.Spec.NodeName
+	for _, condition := range obj.Status.Conditions {
+		if condition.Type == v1.PodReady {
+			p.Ready = condition.Status == v1.ConditionTrue
+		}
+	}
+}
+
+// onDaemonSetPodChanged refreshes the coverage of the owning DaemonSet when a daemon pod moves or changes readiness
+func (watcher *Watcher) onDaemonSetPodChanged(oldPoint, newPoint *PodPoint) {
+	if newPoint.OwnerKind != "DaemonSet" {
+		return
+	}
+	if oldPoint != nil && oldPoint.NodeName == newPoint.NodeName && oldPoint.Ready == newPoint.Ready {
+		return
+	}
+	watcher.pushDaemonSetCoverage(newPoint.Namespace, newPoint.OwnerName)
 }
 
 func ParsePodPoint(d *v1.Pod) *PodPoint {
@@ -48,91 +75,287 @@ func (watcher *Watcher) ParsePod(rawPod map[string]interface{}) (*v1.Pod, error)
 	return ns, nil
 }
 
-func (watcher *Watcher) WatchPods(nsName string) {
-	watcher.ClientMutex.Lock()
-	watchInterface, _ := watcher.Client.CoreV1().Pods(nsName).Watch(context.TODO(), metav1.ListOptions{Watch: true})
-	ch := watchInterface.ResultChan()
-	watcher.ClientMutex.Unlock()
-	for {
-		select {
-		case e, ok := <-ch:
-			if !ok {
-				return
-			}
-			if e.Type == watch.Added {
-				rawPod, _ := watcher.ToUnstructuredSync(e.Object)
-				pod, err := watcher.ParsePod(rawPod)
-				if err != nil {
-					return
-				}
+// owners that pods are slotted under
+var podOwnerKinds = []string{"ReplicaSet", "StatefulSet", "DaemonSet", "Job"}
+
+// the projected service account token volume injected into every pod, which also mounts the kube-root-ca.crt ConfigMap
+const podServiceAccountTokenVolumePrefix = "kube-api-access-"
 
-				podName := pod.GetName()
-				_, found := watcher.PodPoints.Load(podName)
-				if !found {
-					pod, err := watcher.ParsePod(rawPod)
-					if err != nil {
-						continue
-					}
-
-					// watcher will notice any owner references to a ReplicaSet
-					ownerName := ""
-					ownerType := ""
-					ownerRefs := pod.GetObjectMeta().GetOwnerReferences()
-					if len(ownerRefs) > 0 && ownerRefs[0].Kind == "ReplicaSet" {
-						ownerName = ownerRefs[0].Name
-						ownerType = ownerRefs[0].Kind
-					}
-
-					// add pod point
-					watcher.PodPoints.Store(podName, ParsePodPoint(pod))
-					watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GPOD, podName, pod.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GPodStatus{
-						OwnerReferenceName: ownerName,
-						OwnerReferenceType: ownerType,
-						Up:                 pod.Status.Phase == v1.PodRunning,
-						Index:              0,
-					}, -1, rawPod)
-					log.Println("ADDED pod " + podName)
+// podConfigurationRefs collects the ConfigMaps and Secrets consumed by a pod through volumes, projected volumes, envFrom,
+// env.valueFrom or image pull secrets, each sorted and without duplicates
+func podConfigurationRefs(pod *v1.Pod) ([]string, []string) {
+	configMaps := []string{}
+	secrets := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMaps = append(configMaps, volume.ConfigMap.Name)
+		}
+		if volume.Secret != nil {
+			secrets = append(secrets, volume.Secret.SecretName)
+		}
+		if volume.Projected != nil && !strings.HasPrefix(volume.Name, podServiceAccountTokenVolumePrefix) {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					configMaps = append(configMaps, source.ConfigMap.Name)
 				}
-			} else if e.Type == watch.Modified {
-				rawPod, _ := watcher.ToUnstructuredSync(e.Object)
-				pod, err := watcher.ParsePod(rawPod)
-				if err != nil {
-					return
+				if source.Secret != nil {
+					secrets = append(secrets, source.Secret.Name)
 				}
+			}
+		}
+	}
+	containers := append(slices.Clone(pod.Spec.InitContainers), pod.Spec.Containers...)
+	for _, container := range containers {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMaps = append(configMaps, envFrom.ConfigMapRef.Name)
+			}
+			if envFrom.SecretRef != nil {
+				secrets = append(secrets, envFrom.SecretRef.Name)
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if env.ValueFrom.ConfigMapKeyRef != nil {
+				configMaps = append(configMaps, env.ValueFrom.ConfigMapKeyRef.Name)
+			}
+			if env.ValueFrom.SecretKeyRef != nil {
+				secrets = append(secrets, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		secrets = append(secrets, ref.Name)
+	}
+	slices.Sort(configMaps)
+	slices.Sort(secrets)
+	return slices.Compact(configMaps), slices.Compact(secrets)
+}
 
-				podName := pod.GetName()
-				_, found := watcher.PodPoints.Load(podName)
-				if found {
-					pod, err := watcher.ParsePod(rawPod)
-					if err != nil {
-						continue
-					}
-					// modify pod point
-					watcher.PodPoints.Store(podName, ParsePodPoint(pod))
-					log.Println("MODIFIED pod " + podName)
-				} else {
+// failing container lifecycles of a pod, the first found of which the pod is in
+var podFailureLifecycles = []gkube.GPodLifecycle{gkube.PodOOMKilled, gkube.PodCrashLoopBackOff, gkube.PodImagePullBackOff}
 
-				}
-			} else if e.Type == watch.Deleted {
-				rawPod, _ := watcher.ToUnstructuredSync(e.Object)
-				pod, err := watcher.ParsePod(rawPod)
-				if err != nil {
-					return
-				}
-				podName := pod.GetName()
-				_, found := watcher.PodPoints.Load(podName)
-				if found {
-					// delete pod point
-					watcher.PodPoints.Delete(podName)
-					watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GPOD, podName, pod.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GPodStatus{
-						Up:    pod.Status.Phase == v1.PodRunning,
-						Index: 0,
-					}, -1, rawPod)
-					log.Println("DELETED pod " + podName)
-				}
+// containerFailure is how a container is failing, if it is. A container backing off after it ran out of memory is
+// OOMKilled rather than crash-looping, which is the more useful thing to know.
+func containerFailure(status v1.ContainerStatus) (gkube.GPodLifecycle, bool) {
+	lastTerminated := status.LastTerminationState.Terminated
+	if terminated := status.State.Terminated; terminated != nil && terminated.Reason == "OOMKilled" {
+		return gkube.PodOOMKilled, true
+	}
+	if waiting := status.State.Waiting; waiting != nil {
+		switch waiting.Reason {
+		case "CrashLoopBackOff":
+			if lastTerminated != nil && lastTerminated.Reason == "OOMKilled" {
+				return gkube.PodOOMKilled, true
 			}
-		case <-time.After(30 * time.Minute):
-			return
+			return gkube.PodCrashLoopBackOff, true
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName":
+			return gkube.PodImagePullBackOff, true
+		}
+	}
+	return "", false
+}
+
+// podLifecycle derives what the pod is going through much like kubectl does for its STATUS column
+func podLifecycle(pod *v1.Pod) gkube.GPodLifecycle {
+	if pod.DeletionTimestamp != nil {
+		if pod.Status.Reason == "NodeLost" {
+			return gkube.PodUnknown
+		}
+		return gkube.PodTerminating
+	}
+	switch pod.Status.Phase {
+	case v1.PodSucceeded:
+		return gkube.PodSucceeded
+	case v1.PodFailed:
+		return gkube.PodFailed
+	case v1.PodUnknown:
+		return gkube.PodUnknown
+	}
+	failures := []gkube.GPodLifecycle{}
+	for _, status := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if failure, failing := containerFailure(status); failing {
+			failures = append(failures, failure)
+		}
+	}
+	for _, failure := range podFailureLifecycles {
+		if slices.Contains(failures, failure) {
+			return failure
+		}
+	}
+	if pod.Status.Phase != v1.PodRunning {
+		if len(pod.Spec.NodeName) == 0 {
+			return gkube.PodPending
+		}
+		return gkube.PodContainerCreating
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodReady && condition.Status == v1.ConditionTrue {
+			return gkube.PodRunning
+		}
+	}
+	return gkube.PodNotReady
+}
+
+// createContainerStatuses pairs the containers of a pod spec with the statuses reported by the kubelet, in spec order
+func createContainerStatuses(containers []v1.Container, statuses []v1.ContainerStatus) []gkube.GContainerStatus {
+	gcontainers := []gkube.GContainerStatus{}
+	for _, container := range containers {
+		gcontainer := gkube.GContainerStatus{Name: container.Name, Image: container.Image, Limits: resourceAmounts(container.Resources.Limits)}
+		if container.RestartPolicy != nil {
+			gcontainer.RestartPolicy = string(*container.RestartPolicy)
+		}
+		index := slices.IndexFunc(statuses, func(status v1.ContainerStatus) bool { return status.Name == container.Name })
+		if index >= 0 {
+			status := statuses[index]
+			gcontainer.Ready = status.Ready
+			gcontainer.RestartCount = status.RestartCount
+			gcontainer.Started = status.Started != nil && *status.Started
+			switch {
+			case status.State.Waiting != nil:
+				gcontainer.State = "Waiting"
+				gcontainer.Reason = status.State.Waiting.Reason
+			case status.State.Running != nil:
+				gcontainer.State = "Running"
+			case status.State.Terminated != nil:
+				gcontainer.State = "Terminated"
+				gcontainer.Reason = status.State.Terminated.Reason
+			}
+			if status.LastTerminationState.Terminated != nil {
+				gcontainer.LastTerminationReason = status.LastTerminationState.Terminated.Reason
+			}
+		}
+		gcontainers = append(gcontainers, gcontainer)
+	}
+	return gcontainers
+}
+
+// CreatePodStatus derives the GPodStatus of a pod; the watcher will notice any owner references in podOwnerKinds or to a
+// custom resource. Pods of a StatefulSet carry their ordinal as Index so that they can be slotted in order.
+func (watcher *Watcher) CreatePodStatus(pod *v1.Pod) *gkube.GPodStatus {
+	ownerName := ""
+	ownerType := ""
+	index := int32(0)
+	ownerRefs := pod.GetObjectMeta().GetOwnerReferences()
+	if len(ownerRefs) > 0 && slices.Contains(podOwnerKinds, ownerRefs[0].Kind) {
+		ownerName = ownerRefs[0].Name
+		ownerType = ownerRefs[0].Kind
+	}
+	if ownerType == "StatefulSet" {
+		index = parseOrdinal(pod.GetName())
+	}
+	claims := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	configMaps, secrets := podConfigurationRefs(pod)
+	requests, limits := podResources(pod)
+	return &gkube.GPodStatus{
+		OwnerReferenceName:     ownerName,
+		OwnerReferenceType:     ownerType,
+		Up:                     pod.Status.Phase == v1.PodRunning,
+		Phase:                  string(pod.Status.Phase),
+		Lifecycle:              podLifecycle(pod),
+		Index:                  index,
+		PersistentVolumeClaims: claims,
+		ConfigMaps:             configMaps,
+		Secrets:                secrets,
+		ServiceAccountName:     pod.Spec.ServiceAccountName,
+		NodeName:               pod.Spec.NodeName,
+		InitContainers:         createContainerStatuses(pod.Spec.InitContainers, pod.Status.InitContainerStatuses),
+		Containers:             createContainerStatuses(pod.Spec.Containers, pod.Status.ContainerStatuses),
+		CustomOwner:            watcher.customOwner(pod.Namespace, pod.OwnerReferences),
+		Requests:               requests,
+		Limits:                 limits,
+	}
+}
+
+func (watcher *Watcher) WatchPods(ctx context.Context, nsName string) {
+	informer := coreinformers.NewFilteredPodInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "pods", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onPodAdded,
+		UpdateFunc: watcher.onPodModified,
+		DeleteFunc: watcher.onPodDeleted,
+	})
+}
+
+func (watcher *Watcher) onPodAdded(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return
+	}
+	rawPod, err := watcher.ToUnstructuredSync(pod)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	podName := pod.GetName()
+	key := pointKey(pod.Namespace, podName)
+	_, found := watcher.PodPoints.Load(key)
+	if !found {
+		// add pod point
+		point := ParsePodPoint(pod)
+		watcher.PodPoints.Store(key, point)
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GPOD, podName, pod.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePodStatus(pod), -1, rawPod)
+		watcher.onDaemonSetPodChanged(nil, point)
+		log.Println("ADDED pod " + podName)
+	}
+}
+
+func (watcher *Watcher) onPodModified(oldObj, newObj interface{}) {
+	pod, ok := newObj.(*v1.Pod)
+	if !ok {
+		return
+	}
+
+	podName := pod.GetName()
+	key := pointKey(pod.Namespace, podName)
+	point, found := watcher.PodPoints.Load(key)
+	if !found {
+		watcher.onPodAdded(pod)
+		return
+	}
+	if point.(*PodPoint).ResourceVersion == pod.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawPod, err := watcher.ToUnstructuredSync(pod)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify pod point
+	newPoint := ParsePodPoint(pod)
+	watcher.PodPoints.Store(key, newPoint)
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GPOD, podName, pod.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePodStatus(pod), -1, rawPod)
+	watcher.onDaemonSetPodChanged(point.(*PodPoint), newPoint)
+	log.Println("MODIFIED pod " + podName)
+}
+
+func (watcher *Watcher) onPodDeleted(obj interface{}) {
+	pod, ok := unwrapTombstone(obj).(*v1.Pod)
+	if !ok {
+		return
+	}
+	rawPod, err := watcher.ToUnstructuredSync(pod)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	podName := pod.GetName()
+	key := pointKey(pod.Namespace, podName)
+	point, found := watcher.PodPoints.Load(key)
+	if found {
+		// delete pod point
+		watcher.PodPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GPOD, podName, pod.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreatePodStatus(pod), -1, rawPod)
+		if podPoint := point.(*PodPoint); podPoint.OwnerKind == "DaemonSet" {
+			watcher.pushDaemonSetCoverage(podPoint.Namespace, podPoint.OwnerName)
 		}
+		log.Println("DELETED pod " + podName)
 	}
 }
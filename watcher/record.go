@@ -0,0 +1,145 @@
+package watcher
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RecordedEvent is a line of a recording, an object as one of the informers of the watcher received it
+type RecordedEvent struct {
+	Time       time.Time              `json:"time"`
+	Type       watch.EventType        `json:"type"` // ADDED, MODIFIED or DELETED
+	APIVersion string                 `json:"apiVersion"`
+	Kind       string                 `json:"kind"`
+	Resource   string                 `json:"resource"` // as the informer is named, i.e. deployments or kafkas.kafka.strimzi.io
+	Object     map[string]interface{} `json:"object"`
+}
+
+// GroupVersionResource is what the object is served as
+func (e *RecordedEvent) GroupVersionResource() schema.GroupVersionResource {
+	gv, _ := schema.ParseGroupVersion(e.APIVersion)
+	return gv.WithResource(schema.ParseGroupResource(e.Resource).Resource)
+}
+
+// Recorder writes every event received by the informers of a watcher to a gzip compressed JSON-lines file
+type Recorder struct {
+	mutex   sync.Mutex
+	file    *os.File
+	gzip    *gzip.Writer
+	encoder *json.Encoder
+}
+
+func CreateRecorder(path string) (*Recorder, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &Recorder{file: file, gzip: gzip.NewWriter(file)}
+	recorder.encoder = json.NewEncoder(recorder.gzip)
+	return recorder, nil
+}
+
+// Record appends the event, the recording is unreadable past the last event if the recorder is not closed
+func (recorder *Recorder) Record(e *RecordedEvent) error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return recorder.encoder.Encode(e)
+}
+
+func (recorder *Recorder) Close() error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return errors.Join(recorder.gzip.Close(), recorder.file.Close())
+}
+
+// record converts obj, as handed over by the informer of the resource, into a RecordedEvent. Secrets are recorded
+// redacted, as recordings are shared to report bugs.
+func (watcher *Watcher) record(eventType watch.EventType, resource string, obj interface{}) {
+	obj = unwrapTombstone(obj)
+	if secret, ok := obj.(*v1.Secret); ok {
+		obj = redactSecret(secret)
+	}
+	var gvk schema.GroupVersionKind
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		gvk = u.GroupVersionKind()
+	} else if typed, ok := obj.(runtime.Object); ok {
+		gvk = groupVersionKindOf(typed)
+	}
+	raw, err := watcher.ToUnstructuredSync(obj)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	apiVersion, kind := gvk.ToAPIVersionAndKind()
+	e := &RecordedEvent{Time: time.Now(), Type: eventType, APIVersion: apiVersion, Kind: kind, Resource: resource, Object: raw}
+	if err := watcher.Recorder.Record(e); err != nil {
+		log.Println(err)
+	}
+}
+
+// recordingHandler records what the informer hands over before the handler of the watcher sees it. Periodic resyncs
+// are not recorded.
+func (watcher *Watcher) recordingHandler(resource string, handler cache.ResourceEventHandler) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			watcher.record(watch.Added, resource, obj)
+			handler.OnAdd(obj, false)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldMeta, oldOk := oldObj.(metav1.Object)
+			newMeta, newOk := newObj.(metav1.Object)
+			if !oldOk || !newOk || oldMeta.GetResourceVersion() != newMeta.GetResourceVersion() {
+				watcher.record(watch.Modified, resource, newObj)
+			}
+			handler.OnUpdate(oldObj, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			watcher.record(watch.Deleted, resource, obj)
+			handler.OnDelete(obj)
+		},
+	}
+}
+
+// LoadRecording reads every event of a recording, in order. A recording cut short, i.e. by a crash, is read up to its
+// last complete event.
+func LoadRecording(path string) ([]RecordedEvent, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	reader, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+	}
+	events := []RecordedEvent{}
+	decoder := json.NewDecoder(reader)
+	for {
+		e := RecordedEvent{}
+		if err := decoder.Decode(&e); errors.Is(err, io.EOF) {
+			return events, nil
+		} else if errors.Is(err, io.ErrUnexpectedEOF) {
+			log.Printf("Recording %s is cut short after %d events\n", path, len(events))
+			return events, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+		}
+		events = append(events, e)
+	}
+}
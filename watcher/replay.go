@@ -0,0 +1,399 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// events recorded this soon after the first are the initial lists of the informers, which are in place before the
+// replay starts watching
+const replayInitialListWindow = time.Second
+
+// how long a replayed event waits for the informer of its resource to watch, i.e. that of a namespace just added
+const replayWatchTimeout = time.Second
+
+// steps and seeks queued while play is busy, further ones are dropped rather than blocking the key handlers
+const replayControlsQueued = 16
+
+// ReplaySource plays a recording back through a Watcher over in-memory clients, so that the cluster is drawn as it was
+// recorded. Playback follows the recorded timing scaled by its speed, and can be paused, stepped and seeked either way.
+type ReplaySource struct {
+	Events      []RecordedEvent
+	ClusterName string
+
+	watcher       *Watcher
+	clientset     *fake.Clientset
+	dynamicClient *dynamicfake.FakeDynamicClient
+
+	mutex    sync.Mutex
+	position int // index of the next event to apply
+	speed    float64
+	paused   bool
+	wake     chan struct{} // interrupts the wait for the next event when the controls change
+	controls chan func()   // steps and seeks, applied by play as they wait for informers to watch
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	watchMutex sync.Mutex
+	watching   map[string]bool // by watchKey, whether the informers opened the watch or gave up waiting for it
+}
+
+// CreateReplaySource loads the recording at path, the cluster is named after the file
+func CreateReplaySource(path string) (*ReplaySource, error) {
+	events, err := LoadRecording(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("recording %s is empty", path)
+	}
+	name := filepath.Base(path)
+	for _, ext := range []string{".gz", ".jsonl"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	return &ReplaySource{Events: events, ClusterName: name, speed: 1}, nil
+}
+
+// customResources are the recorded resources that are not built in, served through the dynamic client on replay
+func (source *ReplaySource) customResources() map[schema.GroupVersionResource]metav1.APIResource {
+	resources := map[schema.GroupVersionResource]metav1.APIResource{}
+	for _, e := range source.Events {
+		gvr := e.GroupVersionResource()
+		if scheme.Scheme.Recognizes(gvr.GroupVersion().WithKind(e.Kind)) {
+			continue
+		}
+		u := unstructured.Unstructured{Object: e.Object}
+		resource := resources[gvr]
+		resource.Name = gvr.Resource
+		resource.Kind = e.Kind
+		resource.Namespaced = resource.Namespaced || len(u.GetNamespace()) > 0
+		resources[gvr] = resource
+	}
+	return resources
+}
+
+func (source *ReplaySource) Start(sink EventSink) {
+	source.clientset = fake.NewSimpleClientset()
+	source.watcher = &Watcher{}
+	source.watcher.Init(source.clientset)
+	source.watcher.ClusterName = source.ClusterName
+	if resources := source.customResources(); len(resources) > 0 {
+		listKinds := map[schema.GroupVersionResource]string{}
+		for gvr, resource := range resources {
+			listKinds[gvr] = resource.Kind + "List"
+			source.clientset.Resources = append(source.clientset.Resources, &metav1.APIResourceList{
+				GroupVersion: gvr.GroupVersion().String(),
+				APIResources: []metav1.APIResource{resource},
+			})
+			source.watcher.CustomResources = append(source.watcher.CustomResources, gvr)
+		}
+		source.dynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+		source.dynamicClient.PrependWatchReactor("*", source.watchReactor(source.dynamicClient.Tracker()))
+		source.watcher.DynamicClient = source.dynamicClient
+	}
+	source.watching = map[string]bool{}
+	source.clientset.PrependWatchReactor("*", source.watchReactor(source.clientset.Tracker()))
+
+	source.wake = make(chan struct{}, 1)
+	source.controls = make(chan func(), replayControlsQueued)
+	source.done = make(chan struct{})
+	var ctx context.Context
+	ctx, source.cancel = context.WithCancel(context.Background())
+
+	source.mutex.Lock()
+	start := source.Events[0].Time
+	for source.position < len(source.Events) && source.Events[source.position].Time.Sub(start) < replayInitialListWindow {
+		source.apply(&source.Events[source.position])
+		source.position++
+	}
+	source.mutex.Unlock()
+
+	source.watcher.Start(sink)
+	go source.play(ctx)
+}
+
+func watchKey(resource schema.GroupResource, namespace string) string {
+	return fmt.Sprintf("%s/%s", resource.String(), namespace)
+}
+
+// watchReactor opens the watches of the informers on the tracker as the in-memory clients do, and notes which are open
+func (source *ReplaySource) watchReactor(tracker k8stesting.ObjectTracker) k8stesting.WatchReactionFunc {
+	return func(action k8stesting.Action) (bool, watch.Interface, error) {
+		w, err := tracker.Watch(action.GetResource(), action.GetNamespace())
+		if err == nil {
+			source.watchMutex.Lock()
+			source.watching[watchKey(action.GetResource().GroupResource(), action.GetNamespace())] = true
+			source.watchMutex.Unlock()
+		}
+		return true, w, err
+	}
+}
+
+// waitForWatch waits until an informer watches the resource of the event, as the in-memory clients do not relist what
+// was written between the list of an informer and its watch. Resources that are not watched in time, i.e. those out of
+// scope, are not waited for again.
+func (source *ReplaySource) waitForWatch(e *RecordedEvent) {
+	u := unstructured.Unstructured{Object: e.Object}
+	resource := e.GroupVersionResource().GroupResource()
+	namespaced := watchKey(resource, u.GetNamespace())
+	deadline := time.Now().Add(replayWatchTimeout)
+	for {
+		source.watchMutex.Lock()
+		watched := source.watching[watchKey(resource, metav1.NamespaceAll)] || source.watching[namespaced]
+		if !watched && time.Now().After(deadline) {
+			source.watching[namespaced] = true
+		}
+		source.watchMutex.Unlock()
+		if watched || time.Now().After(deadline) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (source *ReplaySource) Stop() {
+	source.cancel()
+	<-source.done
+	source.watcher.Stop()
+}
+
+// play applies the events in their recorded timing until stopped
+func (source *ReplaySource) play(ctx context.Context) {
+	defer close(source.done)
+	for {
+		var next <-chan time.Time
+		source.mutex.Lock()
+		if !source.paused && source.position < len(source.Events) {
+			next = time.After(source.delay())
+		}
+		source.mutex.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-source.wake:
+		case control := <-source.controls:
+			source.mutex.Lock()
+			control()
+			source.mutex.Unlock()
+		case <-next:
+			source.mutex.Lock()
+			if !source.paused && source.position < len(source.Events) {
+				source.replay(&source.Events[source.position])
+				source.position++
+			}
+			source.mutex.Unlock()
+		}
+	}
+}
+
+// delay is how long the next event waits after the previous one at the current speed
+// pre-condition: already has lock on the controls
+func (source *ReplaySource) delay() time.Duration {
+	if source.position == 0 {
+		return 0
+	}
+	recorded := source.Events[source.position].Time.Sub(source.Events[source.position-1].Time)
+	return time.Duration(float64(recorded) / source.speed)
+}
+
+// control hands a step or seek to play, so that the key handler calling it does not wait for informers to watch
+func (source *ReplaySource) control(control func()) {
+	select {
+	case source.controls <- control:
+	default:
+		log.Println("Replay is busy, ignoring the control")
+	}
+}
+
+func (source *ReplaySource) notify() {
+	select {
+	case source.wake <- struct{}{}:
+	default:
+	}
+}
+
+// SetSpeed scales the recorded timing, i.e. 2 plays twice as fast and 0.5 at half the speed
+func (source *ReplaySource) SetSpeed(speed float64) {
+	if speed <= 0 {
+		return
+	}
+	source.mutex.Lock()
+	source.speed = speed
+	source.mutex.Unlock()
+	source.notify()
+	log.Printf("Replaying at %gx\n", speed)
+}
+
+func (source *ReplaySource) GetSpeed() float64 {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.speed
+}
+
+func (source *ReplaySource) SetPaused(paused bool) {
+	source.mutex.Lock()
+	source.paused = paused
+	source.mutex.Unlock()
+	source.notify()
+}
+
+func (source *ReplaySource) TogglePause() {
+	source.mutex.Lock()
+	paused := !source.paused
+	source.mutex.Unlock()
+	source.SetPaused(paused)
+}
+
+// Step pauses the replay and applies the next event, once play gets to it
+func (source *ReplaySource) Step() {
+	source.control(func() {
+		source.paused = true
+		if source.position < len(source.Events) {
+			source.replay(&source.Events[source.position])
+			source.position++
+		}
+	})
+}
+
+// GetPosition is how far into the recording the replay is, by the time of the last applied event
+func (source *ReplaySource) GetPosition() time.Duration {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+	return source.offset()
+}
+
+// pre-condition: already has lock on the controls
+func (source *ReplaySource) offset() time.Duration {
+	if source.position == 0 {
+		return 0
+	}
+	return source.Events[source.position-1].Time.Sub(source.Events[0].Time)
+}
+
+// GetDuration is the time between the first and the last event of the recording
+func (source *ReplaySource) GetDuration() time.Duration {
+	return source.Events[len(source.Events)-1].Time.Sub(source.Events[0].Time)
+}
+
+// Seek moves the replay to offset into the recording, once play gets to it. The objects are brought to the state they
+// were recorded in at that time, so the cluster animates from where it is to there rather than being rebuilt.
+func (source *ReplaySource) Seek(offset time.Duration) {
+	source.control(func() { source.seek(offset) })
+}
+
+// SeekBy moves the replay forward, or backward for a negative delta, from where it is once play gets to it
+func (source *ReplaySource) SeekBy(delta time.Duration) {
+	source.control(func() { source.seek(source.offset() + delta) })
+}
+
+// pre-condition: already has lock on the controls
+func (source *ReplaySource) seek(offset time.Duration) {
+	offset = min(max(offset, 0), source.GetDuration())
+	target := source.Events[0].Time.Add(offset)
+	position := sort.Search(len(source.Events), func(i int) bool {
+		return source.Events[i].Time.After(target)
+	})
+	current := source.stateAt(source.position)
+	wanted := source.stateAt(position)
+	for key, i := range current {
+		if _, found := wanted[key]; !found {
+			deleted := source.Events[i]
+			deleted.Type = watch.Deleted
+			source.replay(&deleted)
+		}
+	}
+	indexes := []int{}
+	for key, i := range wanted {
+		if j, found := current[key]; !found || j != i {
+			indexes = append(indexes, i)
+		}
+	}
+	slices.Sort(indexes) // in recorded order, i.e. namespaces before what is in them
+	for _, i := range indexes {
+		added := source.Events[i]
+		added.Type = watch.Added
+		source.replay(&added)
+	}
+	source.position = position
+	log.Printf("Replay at %s of %s\n", offset, source.GetDuration())
+}
+
+// stateAt maps every object that exists before the event at position to the index of its last event
+func (source *ReplaySource) stateAt(position int) map[string]int {
+	state := map[string]int{}
+	for i, e := range source.Events[:position] {
+		key := recordedEventKey(&e)
+		if e.Type == watch.Deleted {
+			delete(state, key)
+		} else {
+			state[key] = i
+		}
+	}
+	return state
+}
+
+func recordedEventKey(e *RecordedEvent) string {
+	u := unstructured.Unstructured{Object: e.Object}
+	return fmt.Sprintf("%s/%s/%s", e.GroupVersionResource().String(), u.GetNamespace(), u.GetName())
+}
+
+// replay applies the event once the informer of its resource watches
+// pre-condition: already has lock on the controls
+func (source *ReplaySource) replay(e *RecordedEvent) {
+	source.waitForWatch(e)
+	source.apply(e)
+}
+
+// apply writes the event into the in-memory clients, where the informers of the watcher pick it up as if it was live
+// pre-condition: already has lock on the controls
+func (source *ReplaySource) apply(e *RecordedEvent) {
+	gvr := e.GroupVersionResource()
+	u := &unstructured.Unstructured{Object: runtime.DeepCopyJSON(e.Object)}
+	var tracker k8stesting.ObjectTracker
+	var obj runtime.Object = u
+	if typed, err := scheme.Scheme.New(gvr.GroupVersion().WithKind(e.Kind)); err == nil {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, typed); err != nil {
+			log.Println(err)
+			return
+		}
+		tracker, obj = source.clientset.Tracker(), typed
+	} else if source.dynamicClient != nil {
+		tracker = source.dynamicClient.Tracker()
+	} else {
+		return
+	}
+
+	var err error
+	switch e.Type {
+	case watch.Added, watch.Modified:
+		if err = tracker.Update(gvr, obj, u.GetNamespace()); apierrors.IsNotFound(err) {
+			err = tracker.Create(gvr, obj, u.GetNamespace())
+		}
+	case watch.Deleted:
+		if err = tracker.Delete(gvr, u.GetNamespace(), u.GetName()); apierrors.IsNotFound(err) {
+			err = nil
+		}
+	}
+	if err != nil {
+		log.Printf("Could not replay %s %s %s: %v\n", e.Type, e.Kind, u.GetName(), err)
+	}
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"time"
+	"strconv"
 
 	"github.com/kabicin/kubechaser/renderer/gkube"
 	corev1 "k8s.io/api/apps/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/tools/cache"
 )
 
 type ReplicaSetPoint struct {
@@ -47,86 +47,107 @@ func (watcher *Watcher) ParseReplicaSet(rawReplicaSet map[string]interface{}) (*
 	return ns, nil
 }
 
-func (watcher *Watcher) WatchReplicaSets(nsName string) {
-	watcher.ClientMutex.Lock()
-	watchInterface, _ := watcher.Client.AppsV1().ReplicaSets(nsName).Watch(context.TODO(), metav1.ListOptions{Watch: true})
-	ch := watchInterface.ResultChan()
-	watcher.ClientMutex.Unlock()
-	for {
-		select {
-		case e, ok := <-ch:
-			if !ok {
-				return
-			}
-			if e.Type == watch.Added {
-				rawReplicaSet, _ := watcher.ToUnstructuredSync(e.Object)
-				replicaset, err := watcher.ParseReplicaSet(rawReplicaSet)
-				if err != nil {
-					return
-				}
-
-				replicasetName := replicaset.GetName()
-				_, found := watcher.ReplicaSetPoints.Load(replicasetName)
-				if !found {
-					replicaset, err := watcher.ParseReplicaSet(rawReplicaSet)
-					if err != nil {
-						continue
-					}
-
-					// watcher will notice any owner references to a Deployment
-					ownerName := ""
-					ownerType := ""
-					ownerRefs := replicaset.GetObjectMeta().GetOwnerReferences()
-					if len(ownerRefs) > 0 && ownerRefs[0].Kind == "Deployment" {
-						ownerName = ownerRefs[0].Name
-						ownerType = ownerRefs[0].Kind
-					}
-					// add replicaset point
-					watcher.ReplicaSetPoints.Store(replicasetName, ParseReplicaSetPoint(replicaset))
-					watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GREPLICASET, replicasetName, replicaset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GReplicaSetStatus{
-						OwnerReferenceName: ownerName,
-						OwnerReferenceType: ownerType,
-						ReadyReplicas:      replicaset.Status.ReadyReplicas,
-						Replicas:           replicaset.Status.Replicas,
-					}, -1, rawReplicaSet)
-					log.Println("ADDED replicaset " + replicasetName)
-				}
-			} else if e.Type == watch.Modified {
-				rawReplicaSet, _ := watcher.ToUnstructuredSync(e.Object)
-				replicaset, err := watcher.ParseReplicaSet(rawReplicaSet)
-				if err != nil {
-					return
-				}
-
-				replicasetName := replicaset.GetName()
-				_, found := watcher.ReplicaSetPoints.Load(replicasetName)
-				if found {
-					replicaset, err := watcher.ParseReplicaSet(rawReplicaSet)
-					if err != nil {
-						continue
-					}
-					// modify replicaset point
-					watcher.ReplicaSetPoints.Store(replicasetName, ParseReplicaSetPoint(replicaset))
-					log.Println("MODIFIED replicaset " + replicasetName)
-				} else {
-
-				}
-			} else if e.Type == watch.Deleted {
-				rawReplicaSet, _ := watcher.ToUnstructuredSync(e.Object)
-				replicaset, err := watcher.ParseReplicaSet(rawReplicaSet)
-				if err != nil {
-					return
-				}
-				replicasetName := replicaset.GetName()
-				_, found := watcher.ReplicaSetPoints.Load(replicasetName)
-				if found {
-					// delete replicaset point
-					watcher.ReplicaSetPoints.Delete(replicasetName)
-					log.Println("DELETED replicaset " + replicasetName)
-				}
-			}
-		case <-time.After(30 * time.Minute):
-			return
-		}
+// RevisionAnnotation numbers the rollouts of a Deployment, set on the Deployment and on the ReplicaSet of each rollout
+const RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+// revision reads the RevisionAnnotation, 0 when it is missing or malformed
+func revision(annotations map[string]string) int64 {
+	revision, err := strconv.ParseInt(annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// CreateReplicaSetStatus derives the GReplicaSetStatus of a replicaset; the watcher will notice any owner references to a
+// Deployment
+func CreateReplicaSetStatus(replicaset *corev1.ReplicaSet) *gkube.GReplicaSetStatus {
+	ownerName := ""
+	ownerType := ""
+	ownerRefs := replicaset.GetObjectMeta().GetOwnerReferences()
+	if len(ownerRefs) > 0 && ownerRefs[0].Kind == "Deployment" {
+		ownerName = ownerRefs[0].Name
+		ownerType = ownerRefs[0].Kind
+	}
+	return &gkube.GReplicaSetStatus{
+		OwnerReferenceName: ownerName,
+		OwnerReferenceType: ownerType,
+		ReadyReplicas:      replicaset.Status.ReadyReplicas,
+		Replicas:           replicaset.Status.Replicas,
+		Revision:           revision(replicaset.Annotations),
+	}
+}
+
+func (watcher *Watcher) WatchReplicaSets(ctx context.Context, nsName string) {
+	informer := appsinformers.NewFilteredReplicaSetInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "replicasets", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onReplicaSetAdded,
+		UpdateFunc: watcher.onReplicaSetModified,
+		DeleteFunc: watcher.onReplicaSetDeleted,
+	})
+}
+
+func (watcher *Watcher) onReplicaSetAdded(obj interface{}) {
+	replicaset, ok := obj.(*corev1.ReplicaSet)
+	if !ok {
+		return
+	}
+	rawReplicaSet, err := watcher.ToUnstructuredSync(replicaset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	replicasetName := replicaset.GetName()
+	key := pointKey(replicaset.Namespace, replicasetName)
+	_, found := watcher.ReplicaSetPoints.Load(key)
+	if !found {
+		// add replicaset point
+		watcher.ReplicaSetPoints.Store(key, ParseReplicaSetPoint(replicaset))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GREPLICASET, replicasetName, replicaset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateReplicaSetStatus(replicaset), -1, rawReplicaSet)
+		log.Println("ADDED replicaset " + replicasetName)
+	}
+}
+
+func (watcher *Watcher) onReplicaSetModified(oldObj, newObj interface{}) {
+	replicaset, ok := newObj.(*corev1.ReplicaSet)
+	if !ok {
+		return
+	}
+
+	replicasetName := replicaset.GetName()
+	key := pointKey(replicaset.Namespace, replicasetName)
+	point, found := watcher.ReplicaSetPoints.Load(key)
+	if !found {
+		watcher.onReplicaSetAdded(replicaset)
+		return
+	}
+	if point.(*ReplicaSetPoint).ResourceVersion == replicaset.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawReplicaSet, err := watcher.ToUnstructuredSync(replicaset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify replicaset point
+	watcher.ReplicaSetPoints.Store(key, ParseReplicaSetPoint(replicaset))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GREPLICASET, replicasetName, replicaset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateReplicaSetStatus(replicaset), -1, rawReplicaSet)
+	log.Println("MODIFIED replicaset " + replicasetName)
+}
+
+func (watcher *Watcher) onReplicaSetDeleted(obj interface{}) {
+	replicaset, ok := unwrapTombstone(obj).(*corev1.ReplicaSet)
+	if !ok {
+		return
+	}
+	replicasetName := replicaset.GetName()
+	key := pointKey(replicaset.Namespace, replicasetName)
+	_, found := watcher.ReplicaSetPoints.Load(key)
+	if found {
+		// delete replicaset point
+		watcher.ReplicaSetPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GREPLICASET, replicasetName, replicaset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateReplicaSetStatus(replicaset), -1, nil)
+		log.Println("DELETED replicaset " + replicasetName)
 	}
 }
@@ -0,0 +1,140 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type RolePoint struct {
+	WatchPoint
+}
+
+func (p *RolePoint) String() string {
+	return fmt.Sprintf("Role %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *RolePoint) Init(obj *rbacv1.Role) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseRolePoint(d *rbacv1.Role) *RolePoint {
+	p := &RolePoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseRole(rawRole map[string]interface{}) (*rbacv1.Role, error) {
+	ns := &rbacv1.Role{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawRole, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateRoleStatus copies the policy rules granted by the role
+func CreateRoleStatus(role *rbacv1.Role) *gkube.GRoleStatus {
+	return &gkube.GRoleStatus{
+		Rules: convertPolicyRules(role.Rules),
+	}
+}
+
+func (watcher *Watcher) WatchRoles(ctx context.Context, nsName string) {
+	informer := rbacinformers.NewRoleInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "roles", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onRoleAdded,
+		UpdateFunc: watcher.onRoleModified,
+		DeleteFunc: watcher.onRoleDeleted,
+	})
+}
+
+func (watcher *Watcher) onRoleAdded(obj interface{}) {
+	role, ok := obj.(*rbacv1.Role)
+	if !ok {
+		return
+	}
+	rawRole, err := watcher.ToUnstructuredSync(role)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	roleName := role.GetName()
+	key := pointKey(role.Namespace, roleName)
+	_, found := watcher.RolePoints.Load(key)
+	if !found {
+		// add role point
+		watcher.RolePoints.Store(key, ParseRolePoint(role))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GROLE, roleName, role.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleStatus(role), -1, rawRole)
+		log.Println("ADDED role " + roleName)
+	}
+}
+
+func (watcher *Watcher) onRoleModified(oldObj, newObj interface{}) {
+	role, ok := newObj.(*rbacv1.Role)
+	if !ok {
+		return
+	}
+
+	roleName := role.GetName()
+	key := pointKey(role.Namespace, roleName)
+	point, found := watcher.RolePoints.Load(key)
+	if !found {
+		watcher.onRoleAdded(role)
+		return
+	}
+	if point.(*RolePoint).ResourceVersion == role.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawRole, err := watcher.ToUnstructuredSync(role)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify role point
+	watcher.RolePoints.Store(key, ParseRolePoint(role))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GROLE, roleName, role.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleStatus(role), -1, rawRole)
+	log.Println("MODIFIED role " + roleName)
+}
+
+func (watcher *Watcher) onRoleDeleted(obj interface{}) {
+	role, ok := unwrapTombstone(obj).(*rbacv1.Role)
+	if !ok {
+		return
+	}
+	roleName := role.GetName()
+	key := pointKey(role.Namespace, roleName)
+	_, found := watcher.RolePoints.Load(key)
+	if found {
+		// delete role point
+		watcher.RolePoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GROLE, roleName, role.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleStatus(role), -1, nil)
+		log.Println("DELETED role " + roleName)
+	}
+}
+
+// convertPolicyRules copies RBAC policy rules into their renderer form
+func convertPolicyRules(rules []rbacv1.PolicyRule) []gkube.GPolicyRule {
+	gRules := []gkube.GPolicyRule{}
+	for _, rule := range rules {
+		gRules = append(gRules, gkube.GPolicyRule{
+			Verbs:           rule.Verbs,
+			APIGroups:       rule.APIGroups,
+			Resources:       rule.Resources,
+			ResourceNames:   rule.ResourceNames,
+			NonResourceURLs: rule.NonResourceURLs,
+		})
+	}
+	return gRules
+}
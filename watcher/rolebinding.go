@@ -0,0 +1,135 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	rbacinformers "k8s.io/client-go/informers/rbac/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type RoleBindingPoint struct {
+	WatchPoint
+}
+
+func (p *RoleBindingPoint) String() string {
+	return fmt.Sprintf("RoleBinding %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *RoleBindingPoint) Init(obj *rbacv1.RoleBinding) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseRoleBindingPoint(d *rbacv1.RoleBinding) *RoleBindingPoint {
+	p := &RoleBindingPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseRoleBinding(rawRoleBinding map[string]interface{}) (*rbacv1.RoleBinding, error) {
+	ns := &rbacv1.RoleBinding{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawRoleBinding, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func CreateRoleBindingStatus(roleBinding *rbacv1.RoleBinding) *gkube.GRoleBindingStatus {
+	return &gkube.GRoleBindingStatus{
+		RoleKind: roleBinding.RoleRef.Kind,
+		RoleName: roleBinding.RoleRef.Name,
+		Subjects: convertSubjects(roleBinding.Subjects),
+	}
+}
+
+func (watcher *Watcher) WatchRoleBindings(ctx context.Context, nsName string) {
+	informer := rbacinformers.NewRoleBindingInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "rolebindings", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onRoleBindingAdded,
+		UpdateFunc: watcher.onRoleBindingModified,
+		DeleteFunc: watcher.onRoleBindingDeleted,
+	})
+}
+
+func (watcher *Watcher) onRoleBindingAdded(obj interface{}) {
+	roleBinding, ok := obj.(*rbacv1.RoleBinding)
+	if !ok {
+		return
+	}
+	rawRoleBinding, err := watcher.ToUnstructuredSync(roleBinding)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	roleBindingName := roleBinding.GetName()
+	key := pointKey(roleBinding.Namespace, roleBindingName)
+	_, found := watcher.RoleBindingPoints.Load(key)
+	if !found {
+		// add role binding point
+		watcher.RoleBindingPoints.Store(key, ParseRoleBindingPoint(roleBinding))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GROLEBINDING, roleBindingName, roleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleBindingStatus(roleBinding), -1, rawRoleBinding)
+		log.Println("ADDED role binding " + roleBindingName)
+	}
+}
+
+func (watcher *Watcher) onRoleBindingModified(oldObj, newObj interface{}) {
+	roleBinding, ok := newObj.(*rbacv1.RoleBinding)
+	if !ok {
+		return
+	}
+
+	roleBindingName := roleBinding.GetName()
+	key := pointKey(roleBinding.Namespace, roleBindingName)
+	point, found := watcher.RoleBindingPoints.Load(key)
+	if !found {
+		watcher.onRoleBindingAdded(roleBinding)
+		return
+	}
+	if point.(*RoleBindingPoint).ResourceVersion == roleBinding.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawRoleBinding, err := watcher.ToUnstructuredSync(roleBinding)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify role binding point
+	watcher.RoleBindingPoints.Store(key, ParseRoleBindingPoint(roleBinding))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GROLEBINDING, roleBindingName, roleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleBindingStatus(roleBinding), -1, rawRoleBinding)
+	log.Println("MODIFIED role binding " + roleBindingName)
+}
+
+func (watcher *Watcher) onRoleBindingDeleted(obj interface{}) {
+	roleBinding, ok := unwrapTombstone(obj).(*rbacv1.RoleBinding)
+	if !ok {
+		return
+	}
+	roleBindingName := roleBinding.GetName()
+	key := pointKey(roleBinding.Namespace, roleBindingName)
+	_, found := watcher.RoleBindingPoints.Load(key)
+	if found {
+		// delete role binding point
+		watcher.RoleBindingPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GROLEBINDING, roleBindingName, roleBinding.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateRoleBindingStatus(roleBinding), -1, nil)
+		log.Println("DELETED role binding " + roleBindingName)
+	}
+}
+
+// convertSubjects copies the subjects of a binding into their renderer form
+func convertSubjects(subjects []rbacv1.Subject) []gkube.GRoleSubject {
+	gSubjects := []gkube.GRoleSubject{}
+	for _, subject := range subjects {
+		gSubjects = append(gSubjects, gkube.GRoleSubject{Kind: subject.Kind, Name: subject.Name, Namespace: subject.Namespace})
+	}
+	return gSubjects
+}
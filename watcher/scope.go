@@ -0,0 +1,203 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// namespace patterns with this prefix are regular expressions, every other pattern is a glob
+const regexpPatternPrefix = "re:"
+
+// WatchScope limits the watcher to the namespaces and workloads that matter, i.e.
+//
+//	scope:
+//	  namespaces: ["team-*", "re:^ci-[0-9]+$"]
+//	  excludeNamespaces: ["*-sandbox"]
+//	  namespaceSelector: team=payments
+//	  labelSelector: app.kubernetes.io/part-of=shop
+//
+// Selectors use the kubectl syntax and are passed into the ListOptions of the namespace and workload watches.
+type WatchScope struct {
+	Namespaces        []string `yaml:"namespaces"`        // patterns of the watched namespaces, every namespace when empty
+	ExcludeNamespaces []string `yaml:"excludeNamespaces"` // patterns of namespaces left out even if they match Namespaces
+	NamespaceSelector string   `yaml:"namespaceSelector"` // labels of the watched namespaces
+	LabelSelector     string   `yaml:"labelSelector"`     // labels of the watched workloads and pods
+}
+
+// Validate checks that every pattern and selector parses
+func (scope *WatchScope) Validate() error {
+	for _, pattern := range slices.Concat(scope.Namespaces, scope.ExcludeNamespaces) {
+		if expr, isRegexp := strings.CutPrefix(pattern, regexpPatternPrefix); isRegexp {
+			if _, err := regexp.Compile(expr); err != nil {
+				return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+			}
+		} else if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid namespace pattern %q: %w", pattern, err)
+		}
+	}
+	for _, selector := range []string{scope.NamespaceSelector, scope.LabelSelector} {
+		if _, err := labels.Parse(selector); err != nil {
+			return fmt.Errorf("invalid label selector %q: %w", selector, err)
+		}
+	}
+	return nil
+}
+
+// pre-condition: the pattern was validated
+func matchNamespacePattern(pattern, name string) bool {
+	if expr, isRegexp := strings.CutPrefix(pattern, regexpPatternPrefix); isRegexp {
+		return regexp.MustCompile(expr).MatchString(name)
+	}
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+// IncludesNamespace reports whether the namespace matches the namespace patterns of the scope
+func (scope *WatchScope) IncludesNamespace(name string) bool {
+	for _, pattern := range scope.ExcludeNamespaces {
+		if matchNamespacePattern(pattern, name) {
+			return false
+		}
+	}
+	if len(scope.Namespaces) == 0 {
+		return true
+	}
+	for _, pattern := range scope.Namespaces {
+		if matchNamespacePattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (watcher *Watcher) getScope() WatchScope {
+	watcher.scopeMutex.Lock()
+	defer watcher.scopeMutex.Unlock()
+	return watcher.scope
+}
+
+func (watcher *Watcher) namespaceListOptions(options *metav1.ListOptions) {
+	options.LabelSelector = watcher.getScope().NamespaceSelector
+}
+
+func (watcher *Watcher) workloadListOptions(options *metav1.ListOptions) {
+	options.LabelSelector = watcher.getScope().LabelSelector
+}
+
+type informerGroupKey struct{}
+
+// informerGroup holds the informers that are stopped together, i.e. those of a namespace. A group restarted under a new
+// scope lists again, and each of its informers deletes what the informer of the same name saw before and no longer sees.
+type informerGroup struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	mutex    sync.Mutex
+	stores   map[string]cache.Store // by informer name
+	previous map[string]cache.Store
+	running  sync.WaitGroup // the informers of the group and their tracking, counted like those of the watcher
+}
+
+// newInformerGroup starts a group under the watcher, stopping the previous group it replaces, if any
+func (watcher *Watcher) newInformerGroup(previous *informerGroup) *informerGroup {
+	group := &informerGroup{stores: map[string]cache.Store{}, previous: map[string]cache.Store{}}
+	group.ctx, group.cancel = context.WithCancel(context.WithValue(watcher.ctx, informerGroupKey{}, group))
+	if previous != nil {
+		previous.cancel()
+		previous.mutex.Lock()
+		group.previous = previous.stores
+		previous.mutex.Unlock()
+	}
+	return group
+}
+
+// beginRunning counts an informer of the group in, unless the group is stopping
+func (group *informerGroup) beginRunning() bool {
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+	if group.ctx.Err() != nil {
+		return false
+	}
+	group.running.Add(1)
+	return true
+}
+
+// stop cancels the group and waits for its informers to return, so that none of their handlers runs afterwards
+func (group *informerGroup) stop() {
+	group.cancel()
+	group.mutex.Lock() // informers beginning from here on see the group cancelled
+	group.mutex.Unlock()
+	group.running.Wait()
+}
+
+func (group *informerGroup) store(name string) cache.Store {
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+	return group.stores[name]
+}
+
+// track registers the informer and, once it has synced, deletes the objects its predecessor saw that it does not
+func (group *informerGroup) track(name string, informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+	group.mutex.Lock()
+	group.stores[name] = informer.GetStore()
+	previous, found := group.previous[name]
+	delete(group.previous, name)
+	group.mutex.Unlock()
+	if !found || !cache.WaitForCacheSync(group.ctx.Done(), informer.HasSynced) {
+		return
+	}
+	for _, obj := range previous.List() {
+		if _, exists, err := informer.GetStore().Get(obj); err == nil && !exists {
+			handler.OnDelete(obj)
+		}
+	}
+}
+
+// SetScope changes what the watcher watches while it runs. Namespaces leaving the scope are torn down and namespaces
+// entering it are watched; a changed selector relists the affected informers, which delete whatever fell out of scope.
+func (watcher *Watcher) SetScope(scope WatchScope) error {
+	if err := scope.Validate(); err != nil {
+		return err
+	}
+	watcher.scopeMutex.Lock()
+	previous := watcher.scope
+	watcher.scope = scope
+	watcher.scopeMutex.Unlock()
+	if watcher.namespaces == nil {
+		return nil // not started yet
+	}
+
+	if previous.NamespaceSelector != scope.NamespaceSelector {
+		watcher.namespaces = watcher.newInformerGroup(watcher.namespaces)
+		go watcher.WatchNamespaces(watcher.namespaces.ctx)
+	}
+	if previous.LabelSelector != scope.LabelSelector {
+		watcher.namespaceWatches.Range(func(key, value any) bool {
+			group := watcher.newInformerGroup(value.(*informerGroup))
+			watcher.namespaceWatches.Store(key, group)
+			watcher.watchNamespace(group.ctx, key.(string))
+			return true
+		})
+	}
+
+	watcher.NamespacePoints.Range(func(key, value any) bool {
+		if !scope.IncludesNamespace(key.(string)) {
+			watcher.unwatchNamespace(key.(string))
+		}
+		return true
+	})
+	if store := watcher.namespaces.store("namespaces"); store != nil {
+		for _, obj := range store.List() {
+			watcher.onNamespaceAdded(obj) // skips namespaces that are already watched or out of scope
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package watcher
+
+import (
+	"sync"
+	"time"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+)
+
+// ScriptedEvent is a single GObjectEvent replayed by a ScriptedSource after Delay
+type ScriptedEvent struct {
+	Delay     time.Duration
+	Type      gkube.GEventStatus
+	Resource  gkube.GResource
+	Name      string
+	Namespace string
+	Status    gkube.GStatus
+	KubeState map[string]interface{}
+}
+
+// ScriptedSource pushes a fixed list of events in order, without any API server
+type ScriptedSource struct {
+	Events []ScriptedEvent
+
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+	stopOnce sync.Once
+}
+
+func (source *ScriptedSource) Start(sink EventSink) {
+	source.stopCh = make(chan struct{})
+	source.doneCh = make(chan struct{})
+	go func() {
+		defer close(source.doneCh)
+		for _, e := range source.Events {
+			select {
+			case <-time.After(e.Delay):
+				sink.PushGObjectEvent(e.Type, e.Resource, e.Name, e.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, e.Status, -1, e.KubeState)
+			case <-source.stopCh:
+				return
+			}
+		}
+	}()
+}
+
+// Stop returns once the source pushes no more events, and may be called more than once
+func (source *ScriptedSource) Stop() {
+	if source.stopCh == nil {
+		return // not started
+	}
+	source.stopOnce.Do(func() { close(source.stopCh) })
+	<-source.doneCh
+}
+
+// Done is closed once every scripted event has been pushed or the source was stopped
+func (source *ScriptedSource) Done() <-chan struct{} {
+	return source.doneCh
+}
@@ -0,0 +1,136 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type SecretPoint struct {
+	WatchPoint
+}
+
+func (p *SecretPoint) String() string {
+	return fmt.Sprintf("Secret %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *SecretPoint) Init(obj *v1.Secret) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseSecretPoint(d *v1.Secret) *SecretPoint {
+	p := &SecretPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseSecret(rawSecret map[string]interface{}) (*v1.Secret, error) {
+	ns := &v1.Secret{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawSecret, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func (watcher *Watcher) CreateSecretStatus(secret *v1.Secret) *gkube.GSecretStatus {
+	return &gkube.GSecretStatus{
+		Type:        string(secret.Type),
+		Keys:        len(secret.Data),
+		CustomOwner: watcher.customOwner(secret.Namespace, secret.OwnerReferences),
+	}
+}
+
+// redactSecret drops the secret values, including any copy kept by kubectl apply, so that they never reach the kube
+// state shown in the scene
+func redactSecret(secret *v1.Secret) *v1.Secret {
+	redacted := secret.DeepCopy()
+	redacted.Data = nil
+	redacted.StringData = nil
+	delete(redacted.Annotations, v1.LastAppliedConfigAnnotation)
+	return redacted
+}
+
+func (watcher *Watcher) WatchSecrets(ctx context.Context, nsName string) {
+	informer := coreinformers.NewSecretInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "secrets", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onSecretAdded,
+		UpdateFunc: watcher.onSecretModified,
+		DeleteFunc: watcher.onSecretDeleted,
+	})
+}
+
+func (watcher *Watcher) onSecretAdded(obj interface{}) {
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return
+	}
+	rawSecret, err := watcher.ToUnstructuredSync(redactSecret(secret))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	secretName := secret.GetName()
+	key := pointKey(secret.Namespace, secretName)
+	_, found := watcher.SecretPoints.Load(key)
+	if !found {
+		// add secret point
+		watcher.SecretPoints.Store(key, ParseSecretPoint(secret))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GSECRET, secretName, secret.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateSecretStatus(secret), -1, rawSecret)
+		log.Println("ADDED secret " + secretName)
+	}
+}
+
+func (watcher *Watcher) onSecretModified(oldObj, newObj interface{}) {
+	secret, ok := newObj.(*v1.Secret)
+	if !ok {
+		return
+	}
+
+	secretName := secret.GetName()
+	key := pointKey(secret.Namespace, secretName)
+	point, found := watcher.SecretPoints.Load(key)
+	if !found {
+		watcher.onSecretAdded(secret)
+		return
+	}
+	if point.(*SecretPoint).ResourceVersion == secret.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawSecret, err := watcher.ToUnstructuredSync(redactSecret(secret))
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify secret point
+	watcher.SecretPoints.Store(key, ParseSecretPoint(secret))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSECRET, secretName, secret.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateSecretStatus(secret), -1, rawSecret)
+	log.Println("MODIFIED secret " + secretName)
+}
+
+func (watcher *Watcher) onSecretDeleted(obj interface{}) {
+	secret, ok := unwrapTombstone(obj).(*v1.Secret)
+	if !ok {
+		return
+	}
+	secretName := secret.GetName()
+	key := pointKey(secret.Namespace, secretName)
+	_, found := watcher.SecretPoints.Load(key)
+	if found {
+		// delete secret point
+		watcher.SecretPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GSECRET, secretName, secret.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateSecretStatus(secret), -1, nil)
+		log.Println("DELETED secret " + secretName)
+	}
+}
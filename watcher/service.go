@@ -0,0 +1,137 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ServicePoint struct {
+	WatchPoint
+	Type            string
+	Selector        map[string]string
+	OwnerReferences []metav1.OwnerReference
+}
+
+func (p *ServicePoint) String() string {
+	return fmt.Sprintf("Service %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *ServicePoint) Init(obj *v1.Service) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	p.Type = string(obj.Spec.Type)
+	p.Selector = obj.Spec.Selector
+	p.OwnerReferences = obj.OwnerReferences
+}
+
+func ParseServicePoint(d *v1.Service) *ServicePoint {
+	p := &ServicePoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseService(rawService map[string]interface{}) (*v1.Service, error) {
+	ns := &v1.Service{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawService, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateServiceStatus combines the service point with the endpoints currently published for it
+func (watcher *Watcher) CreateServiceStatus(p *ServicePoint) *gkube.GServiceStatus {
+	return &gkube.GServiceStatus{
+		Type:        p.Type,
+		Selector:    p.Selector,
+		Endpoints:   watcher.getServiceEndpoints(p.Namespace, p.Name),
+		CustomOwner: watcher.customOwner(p.Namespace, p.OwnerReferences),
+	}
+}
+
+func (watcher *Watcher) WatchServices(ctx context.Context, nsName string) {
+	informer := coreinformers.NewServiceInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "services", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onServiceAdded,
+		UpdateFunc: watcher.onServiceModified,
+		DeleteFunc: watcher.onServiceDeleted,
+	})
+}
+
+func (watcher *Watcher) onServiceAdded(obj interface{}) {
+	service, ok := obj.(*v1.Service)
+	if !ok {
+		return
+	}
+	rawService, err := watcher.ToUnstructuredSync(service)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	serviceName := service.GetName()
+	key := pointKey(service.Namespace, serviceName)
+	_, found := watcher.ServicePoints.Load(key)
+	if !found {
+		// add service point
+		point := ParseServicePoint(service)
+		watcher.ServicePoints.Store(key, point)
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GSERVICE, serviceName, service.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateServiceStatus(point), -1, rawService)
+		log.Println("ADDED service " + serviceName)
+	}
+}
+
+func (watcher *Watcher) onServiceModified(oldObj, newObj interface{}) {
+	service, ok := newObj.(*v1.Service)
+	if !ok {
+		return
+	}
+
+	serviceName := service.GetName()
+	key := pointKey(service.Namespace, serviceName)
+	point, found := watcher.ServicePoints.Load(key)
+	if !found {
+		watcher.onServiceAdded(service)
+		return
+	}
+	if point.(*ServicePoint).ResourceVersion == service.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawService, err := watcher.ToUnstructuredSync(service)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify service point
+	newPoint := ParseServicePoint(service)
+	watcher.ServicePoints.Store(key, newPoint)
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSERVICE, serviceName, service.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateServiceStatus(newPoint), -1, rawService)
+	log.Println("MODIFIED service " + serviceName)
+}
+
+func (watcher *Watcher) onServiceDeleted(obj interface{}) {
+	service, ok := unwrapTombstone(obj).(*v1.Service)
+	if !ok {
+		return
+	}
+	serviceName := service.GetName()
+	key := pointKey(service.Namespace, serviceName)
+	point, found := watcher.ServicePoints.Load(key)
+	if found {
+		// delete service point
+		watcher.ServicePoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GSERVICE, serviceName, service.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateServiceStatus(point.(*ServicePoint)), -1, nil)
+		log.Println("DELETED service " + serviceName)
+	}
+}
@@ -0,0 +1,128 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type ServiceAccountPoint struct {
+	WatchPoint
+}
+
+func (p *ServiceAccountPoint) String() string {
+	return fmt.Sprintf("ServiceAccount %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *ServiceAccountPoint) Init(obj *v1.ServiceAccount) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseServiceAccountPoint(d *v1.ServiceAccount) *ServiceAccountPoint {
+	p := &ServiceAccountPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseServiceAccount(rawServiceAccount map[string]interface{}) (*v1.ServiceAccount, error) {
+	ns := &v1.ServiceAccount{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawServiceAccount, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+func CreateServiceAccountStatus(serviceAccount *v1.ServiceAccount) *gkube.GServiceAccountStatus {
+	imagePullSecrets := []string{}
+	for _, ref := range serviceAccount.ImagePullSecrets {
+		imagePullSecrets = append(imagePullSecrets, ref.Name)
+	}
+	return &gkube.GServiceAccountStatus{
+		ImagePullSecrets: imagePullSecrets,
+	}
+}
+
+func (watcher *Watcher) WatchServiceAccounts(ctx context.Context, nsName string) {
+	informer := coreinformers.NewServiceAccountInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "serviceaccounts", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onServiceAccountAdded,
+		UpdateFunc: watcher.onServiceAccountModified,
+		DeleteFunc: watcher.onServiceAccountDeleted,
+	})
+}
+
+func (watcher *Watcher) onServiceAccountAdded(obj interface{}) {
+	serviceAccount, ok := obj.(*v1.ServiceAccount)
+	if !ok {
+		return
+	}
+	rawServiceAccount, err := watcher.ToUnstructuredSync(serviceAccount)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	serviceAccountName := serviceAccount.GetName()
+	key := pointKey(serviceAccount.Namespace, serviceAccountName)
+	_, found := watcher.ServiceAccountPoints.Load(key)
+	if !found {
+		// add service account point
+		watcher.ServiceAccountPoints.Store(key, ParseServiceAccountPoint(serviceAccount))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GSERVICEACCOUNT, serviceAccountName, serviceAccount.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateServiceAccountStatus(serviceAccount), -1, rawServiceAccount)
+		log.Println("ADDED service account " + serviceAccountName)
+	}
+}
+
+func (watcher *Watcher) onServiceAccountModified(oldObj, newObj interface{}) {
+	serviceAccount, ok := newObj.(*v1.ServiceAccount)
+	if !ok {
+		return
+	}
+
+	serviceAccountName := serviceAccount.GetName()
+	key := pointKey(serviceAccount.Namespace, serviceAccountName)
+	point, found := watcher.ServiceAccountPoints.Load(key)
+	if !found {
+		watcher.onServiceAccountAdded(serviceAccount)
+		return
+	}
+	if point.(*ServiceAccountPoint).ResourceVersion == serviceAccount.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawServiceAccount, err := watcher.ToUnstructuredSync(serviceAccount)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify service account point
+	watcher.ServiceAccountPoints.Store(key, ParseServiceAccountPoint(serviceAccount))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSERVICEACCOUNT, serviceAccountName, serviceAccount.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateServiceAccountStatus(serviceAccount), -1, rawServiceAccount)
+	log.Println("MODIFIED service account " + serviceAccountName)
+}
+
+func (watcher *Watcher) onServiceAccountDeleted(obj interface{}) {
+	serviceAccount, ok := unwrapTombstone(obj).(*v1.ServiceAccount)
+	if !ok {
+		return
+	}
+	serviceAccountName := serviceAccount.GetName()
+	key := pointKey(serviceAccount.Namespace, serviceAccountName)
+	_, found := watcher.ServiceAccountPoints.Load(key)
+	if found {
+		// delete service account point
+		watcher.ServiceAccountPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GSERVICEACCOUNT, serviceAccountName, serviceAccount.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateServiceAccountStatus(serviceAccount), -1, nil)
+		log.Println("DELETED service account " + serviceAccountName)
+	}
+}
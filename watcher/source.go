@@ -0,0 +1,97 @@
+package watcher
+
+import (
+	"maps"
+	"slices"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// EventSink receives the GObjectEvents produced by a Source. *gkube.GCluster is the sink used by the renderer.
+type EventSink interface {
+	PushGObjectEvent(eventType gkube.GEventStatus, resource gkube.GResource, name, namespace string, direction gkube.GDirection, settings gkube.GSettings, overrideLastOffset *mgl.Vec3, status gkube.GStatus, slot int, kubeState map[string]interface{})
+}
+
+// Source produces GObjectEvents for a GCluster, i.e. from a live API server, a fake clientset or a script
+type Source interface {
+	Start(sink EventSink)
+	Stop()
+}
+
+// CreateKubeSource returns a Watcher bound to the API server of the kubeconfig context, or of the current context when
+// kubeContext is empty
+func CreateKubeSource(watchConfig *WatchConfig, kubeContext string) *Watcher {
+	watcher, err := NewKubeSource(watchConfig, kubeContext)
+	if err != nil {
+		panic(err.Error())
+	}
+	return watcher
+}
+
+// NewKubeSource is CreateKubeSource for contexts chosen while running, which must not bring the app down
+func NewKubeSource(watchConfig *WatchConfig, kubeContext string) (*Watcher, error) {
+	restConfig, err := config.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	customResources, err := watchConfig.CustomResourceGVRs()
+	if err != nil {
+		return nil, err
+	}
+	watcher := &Watcher{}
+	watcher.Init(clientset)
+	if len(kubeContext) > 0 {
+		watcher.ClusterName = kubeContext
+	}
+	watcher.DynamicClient = dynamicClient
+	watcher.CustomResources = customResources
+	if err := watcher.SetScope(watchConfig.Scope); err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// ListKubeContexts returns the contexts of the kubeconfig, sorted by name, and the current context
+func ListKubeContexts() ([]string, string, error) {
+	kubeconfig, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil, "", err
+	}
+	return slices.Sorted(maps.Keys(kubeconfig.Contexts)), kubeconfig.CurrentContext, nil
+}
+
+// NextKubeContext returns the context after current, wrapping around and skipping the contexts that are already drawn,
+// or an empty string when there is none
+func NextKubeContext(contexts []string, current string, drawn []string) string {
+	start := slices.Index(contexts, current)
+	for i := 1; i <= len(contexts); i++ {
+		next := contexts[(start+i+len(contexts))%len(contexts)]
+		if next != current && !slices.Contains(drawn, next) {
+			return next
+		}
+	}
+	return ""
+}
+
+// CreateFakeSource returns a Watcher backed by an in-memory clientset seeded with objects.
+// The clientset is exposed as watcher.Client so that tests can create, update and delete objects after Start.
+func CreateFakeSource(objects ...runtime.Object) *Watcher {
+	watcher := &Watcher{}
+	watcher.Init(fake.NewSimpleClientset(objects...))
+	return watcher
+}
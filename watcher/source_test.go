@@ -0,0 +1,1302 @@
+package watcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	mgl "github.com/go-gl/mathgl/mgl32"
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+type Test struct {
+	result   any
+	expected any
+}
+
+func checkTests(t *testing.T, tests []Test) {
+	for _, test := range tests {
+		if !reflect.DeepEqual(test.result, test.expected) {
+			t.Errorf("Error: expected %+v but the result was %+v\n", test.expected, test.result)
+		}
+	}
+}
+
+type recordedEvent struct {
+	eventType gkube.GEventStatus
+	resource  gkube.GResource
+	name      string
+	namespace string
+	status    gkube.GStatus
+}
+
+type recordingSink struct {
+	mutex  sync.Mutex
+	events []recordedEvent
+}
+
+func (sink *recordingSink) PushGObjectEvent(eventType gkube.GEventStatus, resource gkube.GResource, name, namespace string, direction gkube.GDirection, settings gkube.GSettings, overrideLastOffset *mgl.Vec3, status gkube.GStatus, slot int, kubeState map[string]interface{}) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.events = append(sink.events, recordedEvent{eventType: eventType, resource: resource, name: name, namespace: namespace, status: status})
+}
+
+func (sink *recordingSink) waitFor(t *testing.T, eventType gkube.GEventStatus, resource gkube.GResource, name string) recordedEvent {
+	t.Helper()
+	return sink.waitUntil(t, func(e recordedEvent) bool {
+		return e.eventType == eventType && e.resource == resource && e.name == name
+	})
+}
+
+// count is the number of recorded events of the type for the named object
+func (sink *recordingSink) count(eventType gkube.GEventStatus, name string) int {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	count := 0
+	for _, e := range sink.events {
+		if e.eventType == eventType && e.name == name {
+			count++
+		}
+	}
+	return count
+}
+
+// waitUntil returns the first recorded event accepted by match
+func (sink *recordingSink) waitUntil(t *testing.T, match func(recordedEvent) bool) recordedEvent {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		sink.mutex.Lock()
+		for _, e := range sink.events {
+			if match(e) {
+				sink.mutex.Unlock()
+				return e
+			}
+		}
+		sink.mutex.Unlock()
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for event")
+	return recordedEvent{}
+}
+
+func ownedBy(kind, name string) []metav1.OwnerReference {
+	return []metav1.OwnerReference{{Kind: kind, Name: name}}
+}
+
+func Test_FakeSource(t *testing.T) {
+	ns := "test-namespace"
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-abc", Namespace: ns, OwnerReferences: ownedBy("Deployment", "web")}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-abc-1", Namespace: ns, OwnerReferences: ownedBy("ReplicaSet", "web-abc")}, Status: v1.PodStatus{Phase: v1.PodRunning}},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	sink.waitFor(t, gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, ns)
+	sink.waitFor(t, gkube.GCREATE, gkube.GDEPLOYMENT, "web")
+	rs := sink.waitFor(t, gkube.GCREATE, gkube.GREPLICASET, "web-abc")
+	pod := sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "web-abc-1")
+
+	rsStatus := rs.status.(*gkube.GReplicaSetStatus)
+	podStatus := pod.status.(*gkube.GPodStatus)
+	checkTests(t, []Test{
+		{rs.namespace, ns},
+		{rsStatus.OwnerReferenceName, "web"},
+		{podStatus.OwnerReferenceName, "web-abc"},
+		{podStatus.Up, true},
+	})
+
+	// modifications are pushed with the new status once the resourceVersion moves
+	failed := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-abc-1", Namespace: ns, ResourceVersion: "2", OwnerReferences: ownedBy("ReplicaSet", "web-abc")}, Status: v1.PodStatus{Phase: v1.PodFailed}}
+	if _, err := source.Client.CoreV1().Pods(ns).UpdateStatus(t.Context(), failed, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := sink.waitFor(t, gkube.GMODIFIED, gkube.GPOD, "web-abc-1")
+	checkTests(t, []Test{
+		{modified.status.(*gkube.GPodStatus).Phase, "Failed"},
+	})
+
+	// deleting through the fake clientset is observed by the informer
+	if err := source.Client.CoreV1().Pods(ns).Delete(t.Context(), "web-abc-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GPOD, "web-abc-1")
+}
+
+func Test_ServiceEndpoints(t *testing.T) {
+	ns := "test-namespace"
+	ready := true
+	notReady := false
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&v1.Service{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}, Spec: v1.ServiceSpec{Type: v1.ServiceTypeClusterIP, Selector: map[string]string{"app": "web"}}},
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{Name: "web-xyz", Namespace: ns, Labels: map[string]string{discoveryv1.LabelServiceName: "web"}},
+			Endpoints: []discoveryv1.Endpoint{
+				{TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "web-abc-2"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+				{TargetRef: &v1.ObjectReference{Kind: "Pod", Name: "web-abc-1"}, Conditions: discoveryv1.EndpointConditions{Ready: &ready}},
+			},
+		},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	// depending on which informer syncs first the endpoints arrive with the create or with a later modification
+	service := sink.waitUntil(t, func(e recordedEvent) bool {
+		status, ok := e.status.(*gkube.GServiceStatus)
+		return ok && e.name == "web" && len(status.Endpoints) == 2
+	})
+	serviceStatus := service.status.(*gkube.GServiceStatus)
+	checkTests(t, []Test{
+		{serviceStatus.Type, "ClusterIP"},
+		{serviceStatus.Selector, map[string]string{"app": "web"}},
+		{serviceStatus.Endpoints, []gkube.GServiceEndpoint{{PodName: "web-abc-1", Ready: true}, {PodName: "web-abc-2", Ready: false}}},
+	})
+}
+
+func Test_IngressRoutes(t *testing.T) {
+	ns := "test-namespace"
+	className := "nginx"
+	prefix := networkingv1.PathTypePrefix
+	backend := func(name string, port networkingv1.ServiceBackendPort) networkingv1.IngressBackend {
+		return networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: name, Port: port}}
+	}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&networkingv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Name: "shop", Namespace: ns},
+			Spec: networkingv1.IngressSpec{
+				IngressClassName: &className,
+				DefaultBackend:   &networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: "fallback", Port: networkingv1.ServiceBackendPort{Number: 80}}},
+				TLS:              []networkingv1.IngressTLS{{Hosts: []string{"shop.example.com"}, SecretName: "shop-tls"}},
+				Rules: []networkingv1.IngressRule{
+					{Host: "shop.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/", PathType: &prefix, Backend: backend("web", networkingv1.ServiceBackendPort{Name: "http"})},
+						{Path: "/api", PathType: &prefix, Backend: backend("api", networkingv1.ServiceBackendPort{Number: 8080})},
+					}}}},
+					{Host: "admin.example.com", IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{Paths: []networkingv1.HTTPIngressPath{
+						{Path: "/", PathType: &prefix, Backend: backend("web", networkingv1.ServiceBackendPort{Number: 80})},
+					}}}},
+				},
+			},
+		},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	ingress := sink.waitFor(t, gkube.GCREATE, gkube.GINGRESS, "shop")
+	ingressStatus := ingress.status.(*gkube.GIngressStatus)
+	checkTests(t, []Test{
+		{ingressStatus.ClassName, "nginx"},
+		{ingressStatus.Routes, []gkube.GIngressRoute{
+			{Host: "shop.example.com", Path: "/", ServiceName: "web", ServicePort: "http", TLSSecret: "shop-tls"},
+			{Host: "shop.example.com", Path: "/api", ServiceName: "api", ServicePort: "8080", TLSSecret: "shop-tls"},
+			{Host: "admin.example.com", Path: "/", ServiceName: "web", ServicePort: "80"},
+			{Path: "/*", ServiceName: "fallback", ServicePort: "80"},
+		}},
+	})
+}
+
+func Test_PodConfigurationRefs(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-abc-1", Namespace: "test-namespace"},
+		Spec: v1.PodSpec{
+			ServiceAccountName: "web",
+			ImagePullSecrets:   []v1.LocalObjectReference{{Name: "registry"}},
+			Volumes: []v1.Volume{
+				{Name: "config", VolumeSource: v1.VolumeSource{ConfigMap: &v1.ConfigMapVolumeSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-config"}}}},
+				{Name: "certs", VolumeSource: v1.VolumeSource{Secret: &v1.SecretVolumeSource{SecretName: "web-tls"}}},
+				{Name: "bundle", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+					{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "bundle-config"}}},
+					{Secret: &v1.SecretProjection{LocalObjectReference: v1.LocalObjectReference{Name: "bundle-secret"}}},
+				}}}},
+				{Name: "kube-api-access-x7k2p", VolumeSource: v1.VolumeSource{Projected: &v1.ProjectedVolumeSource{Sources: []v1.VolumeProjection{
+					{ConfigMap: &v1.ConfigMapProjection{LocalObjectReference: v1.LocalObjectReference{Name: "kube-root-ca.crt"}}},
+				}}}},
+			},
+			InitContainers: []v1.Container{{Name: "migrate", EnvFrom: []v1.EnvFromSource{{SecretRef: &v1.SecretEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "db"}}}}}},
+			Containers: []v1.Container{{
+				Name:    "web",
+				EnvFrom: []v1.EnvFromSource{{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "web-config"}}}},
+				Env: []v1.EnvVar{
+					{Name: "LOG_LEVEL", ValueFrom: &v1.EnvVarSource{ConfigMapKeyRef: &v1.ConfigMapKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "logging"}, Key: "level"}}},
+					{Name: "DB_PASSWORD", ValueFrom: &v1.EnvVarSource{SecretKeyRef: &v1.SecretKeySelector{LocalObjectReference: v1.LocalObjectReference{Name: "db"}, Key: "password"}}},
+					{Name: "PLAIN", Value: "value"},
+				},
+			}},
+		},
+	}
+	podStatus := (&Watcher{}).CreatePodStatus(pod)
+	checkTests(t, []Test{
+		{podStatus.ConfigMaps, []string{"bundle-config", "logging", "web-config"}},
+		{podStatus.Secrets, []string{"bundle-secret", "db", "registry", "web-tls"}},
+		{podStatus.ServiceAccountName, "web"},
+	})
+}
+
+func Test_StorageStatus(t *testing.T) {
+	standard := "standard"
+	claim := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "data-db-0", Namespace: "test-namespace"},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &standard,
+			AccessModes:      []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce},
+			Resources:        v1.VolumeResourceRequirements{Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")}},
+		},
+		Status: v1.PersistentVolumeClaimStatus{Phase: v1.ClaimPending},
+	}
+	volume := &v1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "pv-1"},
+		Spec: v1.PersistentVolumeSpec{
+			StorageClassName:              standard,
+			Capacity:                      v1.ResourceList{v1.ResourceStorage: resource.MustParse("20Gi")},
+			AccessModes:                   []v1.PersistentVolumeAccessMode{v1.ReadWriteOnce, v1.ReadOnlyMany},
+			PersistentVolumeReclaimPolicy: v1.PersistentVolumeReclaimRetain,
+			ClaimRef:                      &v1.ObjectReference{Namespace: "test-namespace", Name: "data-db-1"},
+		},
+		Status: v1.PersistentVolumeStatus{Phase: v1.VolumeReleased},
+	}
+	checkTests(t, []Test{
+		{(&Watcher{}).CreatePersistentVolumeClaimStatus(claim), &gkube.GPersistentVolumeClaimStatus{Phase: "Pending", StorageClassName: "standard", Capacity: "10Gi", AccessModes: []string{"RWO"}}},
+		{CreatePersistentVolumeStatus(volume), &gkube.GPersistentVolumeStatus{Phase: "Released", ClaimName: "data-db-1", ClaimNamespace: "test-namespace", StorageClassName: "standard", Capacity: "20Gi", AccessModes: []string{"RWO", "ROX"}, ReclaimPolicy: "Retain"}},
+	})
+}
+
+func Test_NodeConditions(t *testing.T) {
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Spec:       v1.NodeSpec{Unschedulable: true},
+		Status: v1.NodeStatus{Conditions: []v1.NodeCondition{
+			{Type: v1.NodeReady, Status: v1.ConditionUnknown},
+			{Type: v1.NodePIDPressure, Status: v1.ConditionTrue},
+			{Type: v1.NodeMemoryPressure, Status: v1.ConditionTrue},
+			{Type: v1.NodeDiskPressure, Status: v1.ConditionFalse},
+		}},
+	}
+	point := ParseNodePoint(node)
+	heartbeat := ParseNodePoint(node)
+	checkTests(t, []Test{
+		{CreateNodeStatus(point), &gkube.GNodeStatus{Ready: false, Unschedulable: true, Pressures: []string{"MemoryPressure", "PIDPressure"}}},
+		{point.sameConditions(heartbeat), true},
+	})
+}
+
+func Test_CustomResources(t *testing.T) {
+	ns := "test-namespace"
+	isController := true
+	kafka := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kafka.strimzi.io/v1beta2",
+		"kind":       "Kafka",
+		"metadata":   map[string]interface{}{"name": "events", "namespace": ns},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+		},
+	}}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "events-kafka", Namespace: ns, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka", Name: "events", Controller: &isController},
+		}}},
+		// operators also own pods, claims and jobs directly, and namespaced objects may be owned by cluster-scoped ones
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "events-exporter", Namespace: ns, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka", Name: "events", Controller: &isController},
+		}}},
+		&v1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "data-events", Namespace: ns, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "kafka.strimzi.io/v1beta2", Kind: "Kafka", Name: "events"},
+		}}},
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "issue-events", Namespace: ns, OwnerReferences: []metav1.OwnerReference{
+			{APIVersion: "cert-manager.io/v1", Kind: "ClusterIssuer", Name: "letsencrypt", Controller: &isController},
+		}}},
+	)
+	source.Client.(*fake.Clientset).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "kafka.strimzi.io/v1beta2",
+		APIResources: []metav1.APIResource{{Name: "kafkas", Kind: "Kafka", Namespaced: true}},
+	}, {
+		GroupVersion: "cert-manager.io/v1",
+		APIResources: []metav1.APIResource{{Name: "clusterissuers", Kind: "ClusterIssuer"}},
+	}}
+	kafkas, err := (&WatchConfig{CustomResources: []string{"kafkas.v1beta2.kafka.strimzi.io", "clusterissuers.v1.cert-manager.io", "missing.v1.example.com"}}).CustomResourceGVRs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	source.CustomResources = kafkas
+	source.DynamicClient = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{kafkas[0]: "KafkaList", kafkas[1]: "ClusterIssuerList"}, kafka)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	// custom resources are named like kubectl names them, which is how the resources they own refer to them
+	created := sink.waitFor(t, gkube.GCREATE, gkube.GCUSTOMRESOURCE, "kafka.kafka.strimzi.io/events")
+	statefulSet := sink.waitFor(t, gkube.GCREATE, gkube.GSTATEFULSET, "events-kafka")
+	pod := sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "events-exporter")
+	claim := sink.waitFor(t, gkube.GCREATE, gkube.GPERSISTENTVOLUMECLAIM, "data-events")
+	job := sink.waitFor(t, gkube.GCREATE, gkube.GJOB, "issue-events")
+	events := gkube.GCustomOwner{Name: "kafka.kafka.strimzi.io/events", Namespace: ns}
+	checkTests(t, []Test{
+		{created.namespace, ns},
+		{created.status.(*gkube.GCustomResourceStatus).Ready, "True"},
+		{statefulSet.status.(*gkube.GStatefulSetStatus).CustomOwner, events},
+		{pod.status.(*gkube.GPodStatus).CustomOwner, events},
+		{claim.status.(*gkube.GPersistentVolumeClaimStatus).CustomOwner, events},
+		{job.status.(*gkube.GJobStatus).CustomOwner, gkube.GCustomOwner{Name: "clusterissuer.cert-manager.io/letsencrypt", Namespace: gkube.CLUSTER_SCOPED_NAMESPACE}},
+		{len(source.customResourceKinds), 2},
+	})
+
+	_, err = (&WatchConfig{CustomResources: []string{"kafkas"}}).CustomResourceGVRs()
+	checkTests(t, []Test{
+		{err != nil, true},
+	})
+}
+
+func Test_DaemonSetCoverage(t *testing.T) {
+	ns := "kube-system"
+	agent := map[string]string{"role": "agent"}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: agent}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: agent}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n3"}},
+		&v1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n4", Labels: agent}, Spec: v1.NodeSpec{Taints: []v1.Taint{{Key: "dedicated", Value: "gpu", Effect: v1.TaintEffectNoSchedule}}}},
+		&appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "logger", Namespace: ns}, Spec: appsv1.DaemonSetSpec{Template: v1.PodTemplateSpec{Spec: v1.PodSpec{NodeSelector: agent}}}},
+		&v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "logger-a", Namespace: ns, OwnerReferences: ownedBy("DaemonSet", "logger")},
+			Spec:       v1.PodSpec{NodeName: "n1"},
+			Status:     v1.PodStatus{Phase: v1.PodRunning, Conditions: []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}},
+		},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	expected := []gkube.GNodeCoverage{
+		{NodeName: "n1", State: gkube.NodeCoverageHealthy},
+		{NodeName: "n2", State: gkube.NodeCoverageMissing},
+		{NodeName: "n3", State: gkube.NodeCoverageExcluded},
+		{NodeName: "n4", State: gkube.NodeCoverageExcluded},
+	}
+	// nodes, pods and the daemonset sync in any order, so wait for the coverage to settle
+	sink.waitUntil(t, func(e recordedEvent) bool {
+		status, ok := e.status.(*gkube.GDaemonSetStatus)
+		return ok && reflect.DeepEqual(status.Coverage, expected)
+	})
+	pod := sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "logger-a")
+	checkTests(t, []Test{
+		{pod.status.(*gkube.GPodStatus).OwnerReferenceType, "DaemonSet"},
+	})
+}
+
+func Test_ScriptedSource(t *testing.T) {
+	source := &ScriptedSource{Events: []ScriptedEvent{
+		{Type: gkube.GCREATE, Resource: gkube.GDEPLOYMENT, Name: "d1", Namespace: "default", Status: &gkube.GDeploymentStatus{}},
+		{Delay: time.Millisecond, Type: gkube.GCREATE, Resource: gkube.GPOD, Name: "p1", Namespace: "default", Status: &gkube.GPodStatus{}},
+		{Type: gkube.GDELETE, Resource: gkube.GPOD, Name: "p1", Namespace: "default", Status: &gkube.GPodStatus{}},
+	}}
+	sink := &recordingSink{}
+	source.Start(sink)
+	<-source.Done()
+
+	checkTests(t, []Test{
+		{len(sink.events), 3},
+		{sink.events[0].resource, gkube.GDEPLOYMENT},
+		{sink.events[1].name, "p1"},
+		{sink.events[2].eventType, gkube.GDELETE},
+	})
+
+	// nothing is pushed once Stop returns, and stopping twice or before starting is harmless
+	stopped := &ScriptedSource{Events: []ScriptedEvent{{Delay: time.Hour, Type: gkube.GCREATE, Resource: gkube.GPOD, Name: "p2"}}}
+	stopped.Stop()
+	stopped.Start(sink)
+	stopped.Stop()
+	stopped.Stop()
+	(&Watcher{}).Stop()
+	checkTests(t, []Test{
+		{len(sink.events), 3},
+	})
+}
+
+func Test_Events(t *testing.T) {
+	ns := "test-namespace"
+	seen := metav1.NewTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "web-1.backoff", Namespace: ns},
+			InvolvedObject: v1.ObjectReference{Kind: "Pod", APIVersion: "v1", Name: "web-1", Namespace: ns},
+			Type:           v1.EventTypeWarning, Reason: "BackOff", Message: "Back-off restarting failed container", Count: 4, LastTimestamp: seen,
+		},
+		&v1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: "lease.renewed", Namespace: ns},
+			InvolvedObject: v1.ObjectReference{Kind: "Lease", APIVersion: "coordination.k8s.io/v1", Name: "lease", Namespace: ns},
+			Type:           v1.EventTypeNormal, Reason: "LeaderElection",
+		},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	backOff := sink.waitFor(t, gkube.GCREATE, gkube.GKUBEEVENT, "web-1.backoff")
+	checkTests(t, []Test{
+		{backOff.status, &gkube.GKubeEventStatus{
+			InvolvedResource:  gkube.GPOD,
+			InvolvedName:      "web-1",
+			InvolvedNamespace: ns,
+			Type:              "Warning",
+			Reason:            "BackOff",
+			Message:           "Back-off restarting failed container",
+			Count:             4,
+			LastTimestamp:     seen.Time,
+		}},
+	})
+
+	// the event of a kind that is not drawn, i.e. a Lease, is dropped
+	_, found := source.EventPoints.Load(pointKey(ns, "lease.renewed"))
+	checkTests(t, []Test{
+		{found, false},
+	})
+}
+
+func Test_NamespaceDeleted(t *testing.T) {
+	ns := "ci-123"
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "doomed", Namespace: ns}},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	sink.waitFor(t, gkube.GCREATE, gkube.GDEPLOYMENT, "web")
+	sink.waitFor(t, gkube.GCREATE, gkube.GDEPLOYMENT, "doomed")
+	if err := source.Client.AppsV1().Deployments(ns).Delete(t.Context(), "doomed", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GDEPLOYMENT, "doomed")
+
+	// deleting the namespace stops its watchers and forgets the points they saw
+	if err := source.Client.CoreV1().Namespaces().Delete(t.Context(), ns, metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GNAMESPACEOBJECTFRAME, ns)
+	_, watching := source.namespaceWatches.Load(ns)
+	_, found := source.DeploymentPoints.Load(pointKey(ns, "web"))
+	checkTests(t, []Test{
+		{watching, false},
+		{found, false},
+	})
+}
+
+func Test_WatchScope(t *testing.T) {
+	scope := &WatchScope{Namespaces: []string{"team-*", "re:^ci-[0-9]+$"}, ExcludeNamespaces: []string{"*-sandbox"}}
+	checkTests(t, []Test{
+		{scope.Validate(), nil},
+		{scope.IncludesNamespace("team-payments"), true},
+		{scope.IncludesNamespace("ci-123"), true},
+		{scope.IncludesNamespace("ci-abc"), false},
+		{scope.IncludesNamespace("team-payments-sandbox"), false},
+		{scope.IncludesNamespace("kube-system"), false},
+		{(&WatchScope{}).IncludesNamespace("kube-system"), true},
+		{(&WatchScope{Namespaces: []string{"re:("}}).Validate() != nil, true},
+		{(&WatchScope{Namespaces: []string{"team-["}}).Validate() != nil, true},
+		{(&WatchScope{LabelSelector: "app in (web"}).Validate() != nil, true},
+	})
+}
+
+func Test_SetScope(t *testing.T) {
+	web := map[string]string{"app": "web"}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}},
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-a", Labels: web}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "team-a"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "team-b", Labels: web}},
+	)
+	if err := source.SetScope(WatchScope{ExcludeNamespaces: []string{"team-b"}}); err != nil {
+		t.Fatal(err)
+	}
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	sink.waitFor(t, gkube.GCREATE, gkube.GDEPLOYMENT, "batch")
+	_, watchingB := source.NamespacePoints.Load("team-b")
+	checkTests(t, []Test{
+		{watchingB, false},
+	})
+
+	// team-b enters the scope, and the unlabelled deployment of team-a leaves it
+	if err := source.SetScope(WatchScope{LabelSelector: "app=web"}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, "team-b")
+	sink.waitUntil(t, func(e recordedEvent) bool {
+		return e.eventType == gkube.GCREATE && e.resource == gkube.GDEPLOYMENT && e.name == "web" && e.namespace == "team-b"
+	})
+	sink.waitFor(t, gkube.GDELETE, gkube.GDEPLOYMENT, "batch")
+
+	// team-a leaves the scope
+	if err := source.SetScope(WatchScope{Namespaces: []string{"team-b"}, LabelSelector: "app=web"}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GNAMESPACEOBJECTFRAME, "team-a")
+	_, found := source.DeploymentPoints.Load(pointKey("team-a", "web"))
+	checkTests(t, []Test{
+		{found, false},
+		{source.SetScope(WatchScope{Namespaces: []string{"re:("}}) != nil, true},
+	})
+}
+
+// slowPodSink takes a while to receive pods, as the GL thread may
+type slowPodSink struct {
+	recordingSink
+}
+
+func (sink *slowPodSink) PushGObjectEvent(eventType gkube.GEventStatus, resource gkube.GResource, name, namespace string, direction gkube.GDirection, settings gkube.GSettings, overrideLastOffset *mgl.Vec3, status gkube.GStatus, slot int, kubeState map[string]interface{}) {
+	if resource == gkube.GPOD {
+		time.Sleep(5 * time.Millisecond)
+	}
+	sink.recordingSink.PushGObjectEvent(eventType, resource, name, namespace, direction, settings, overrideLastOffset, status, slot, kubeState)
+}
+
+func Test_UnwatchNamespace(t *testing.T) {
+	objects := []runtime.Object{&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}}}
+	for i := range 200 {
+		objects = append(objects, &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("web-%d", i), Namespace: "team-a"}})
+	}
+	source := CreateFakeSource(objects...)
+	sink := &slowPodSink{}
+	source.Start(sink)
+
+	// team-a leaves the scope while its pods are being added, whose handlers return before the namespace is removed
+	sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "web-0")
+	if err := source.SetScope(WatchScope{ExcludeNamespaces: []string{"team-a"}}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GNAMESPACEOBJECTFRAME, "team-a")
+	source.Stop()
+	deleted := slices.IndexFunc(sink.events, func(e recordedEvent) bool {
+		return e.eventType == gkube.GDELETE && e.resource == gkube.GNAMESPACEOBJECTFRAME
+	})
+	checkTests(t, []Test{
+		{slices.ContainsFunc(sink.events[deleted+1:], func(e recordedEvent) bool { return e.namespace == "team-a" }), false},
+	})
+}
+
+func Test_ClusterName(t *testing.T) {
+	sinks := []*recordingSink{}
+	for _, name := range []string{"", "production"} {
+		source := CreateFakeSource()
+		if len(name) > 0 {
+			source.ClusterName = name
+		}
+		sink := &recordingSink{}
+		source.Start(sink)
+		defer source.Stop()
+		sinks = append(sinks, sink)
+	}
+
+	// each watcher frames its cluster under its own name, the current context is framed as "cluster"
+	sinks[0].waitFor(t, gkube.GCREATE, gkube.GCLUSTEROBJECTFRAME, ClusterObjectFrameName)
+	sinks[1].waitFor(t, gkube.GCREATE, gkube.GCLUSTEROBJECTFRAME, "production")
+}
+
+func Test_KubeContexts(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	data := `apiVersion: v1
+kind: Config
+current-context: staging
+clusters:
+- name: local
+  cluster: {server: "https://127.0.0.1:6443"}
+users:
+- name: admin
+contexts:
+- name: staging
+  context: {cluster: local, user: admin}
+- name: production
+  context: {cluster: local, user: admin}
+- name: dev
+  context: {cluster: local, user: admin}
+`
+	if err := os.WriteFile(kubeconfig, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	contexts, current, err := ListKubeContexts()
+	checkTests(t, []Test{
+		{err, nil},
+		{contexts, []string{"dev", "production", "staging"}},
+		{current, "staging"},
+		// switching wraps around and skips the contexts that are drawn beside the switched one
+		{NextKubeContext(contexts, "staging", []string{"staging"}), "dev"},
+		{NextKubeContext(contexts, "dev", []string{"dev", "production"}), "staging"},
+		{NextKubeContext(contexts, "missing", []string{}), "dev"},
+		{NextKubeContext(contexts, "dev", contexts), ""},
+	})
+
+	// a source for another context is bound to it without bringing anything down on errors
+	source, err := NewKubeSource(&WatchConfig{}, "production")
+	checkTests(t, []Test{
+		{err, nil},
+		{source.ClusterName, "production"},
+	})
+	_, err = NewKubeSource(&WatchConfig{}, "missing")
+	checkTests(t, []Test{
+		{err != nil, true},
+	})
+}
+
+func Test_StopWaitsForInformers(t *testing.T) {
+	ns := "test-namespace"
+	source := CreateFakeSource(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}})
+	sink := &recordingSink{}
+	source.Start(sink)
+	sink.waitFor(t, gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, ns)
+	source.Stop()
+
+	// nothing is pushed once Stop has returned, so that the cluster can be torn down
+	sink.mutex.Lock()
+	stopped := len(sink.events)
+	sink.mutex.Unlock()
+	if _, err := source.Client.CoreV1().Namespaces().Create(t.Context(), &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "late"}}, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	checkTests(t, []Test{
+		{len(sink.events), stopped},
+	})
+}
+
+func Test_Manifests(t *testing.T) {
+	// the samples declare the nginx pod twice, the last declaration is kept and placed in the default namespace
+	samples, err := LoadManifests("../assets/samples")
+	checkTests(t, []Test{
+		{err, nil},
+		{len(samples), 3},
+	})
+	expanded := ExpandManifests(samples)
+	kinds := []string{}
+	for _, obj := range expanded {
+		kinds = append(kinds, kindOf(obj))
+	}
+	checkTests(t, []Test{
+		{kinds, []string{"Pod", "ConfigMap", "Namespace"}},
+		{expanded[0].(*v1.Pod).Namespace, "default"},
+		{expanded[0].(*v1.Pod).Labels, map[string]string(nil)},
+	})
+
+	manifest := filepath.Join(t.TempDir(), "shop.yaml")
+	data := `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+spec:
+  replicas: 2
+  selector:
+    matchLabels: {app: web}
+  template:
+    metadata:
+      labels: {app: web}
+    spec:
+      containers: [{name: web, image: nginx}]
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: shop
+spec:
+  selector: {app: web}
+---
+# rendered empty
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: shop
+spec:
+  replicas: 2
+  selector:
+    matchLabels: {app: db}
+  template:
+    metadata:
+      labels: {app: db}
+    spec:
+      containers: [{name: db, image: postgres}]
+  volumeClaimTemplates:
+  - metadata: {name: data}
+    spec:
+      accessModes: [ReadWriteOnce]
+---
+apiVersion: kafka.strimzi.io/v1beta2
+kind: Kafka
+metadata:
+  name: events
+  namespace: shop
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: kafkas.kafka.strimzi.io
+spec:
+  group: kafka.strimzi.io
+  names: {kind: Kafka, plural: kafkas}
+  scope: Namespaced
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: events-kafka-config
+  namespace: shop
+  ownerReferences:
+  - {apiVersion: kafka.strimzi.io/v1beta2, kind: Kafka, name: events, uid: "1", controller: true}
+---
+apiVersion: monitoring.coreos.com/v1
+kind: ServiceMonitor
+metadata:
+  name: web
+  namespace: monitoring
+---
+apiVersion: networking.k8s.io/v1
+kind: IngressClass
+metadata:
+  name: nginx
+---
+apiVersion: scheduling.k8s.io/v1
+kind: PriorityClass
+metadata:
+  name: critical
+value: 1000000
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: clusterissuers.cert-manager.io
+spec:
+  group: cert-manager.io
+  names: {kind: ClusterIssuer, plural: clusterissuers}
+  scope: Cluster
+---
+apiVersion: cert-manager.io/v1
+kind: ClusterIssuer
+metadata:
+  name: letsencrypt
+`
+	if err := os.WriteFile(manifest, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	objects, err := LoadManifests(manifest)
+	checkTests(t, []Test{
+		{err, nil},
+		{len(objects), 11}, // the custom resources and their definitions are kept unstructured
+		{ManifestClusterName(manifest), "shop"},
+	})
+	// cluster-scoped kinds, built in or by the scope of their CustomResourceDefinition, do not land in the default namespace,
+	// and custom resources without their CustomResourceDefinition are not drawn, so they do not make a namespace
+	namespaces := []string{}
+	for _, obj := range ExpandManifests(objects) {
+		if namespace, ok := obj.(*v1.Namespace); ok {
+			namespaces = append(namespaces, namespace.Name)
+		}
+	}
+	checkTests(t, []Test{{namespaces, []string{"shop"}}})
+	source, err := CreateManifestSource(&WatchConfig{}, objects)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	// the namespace, the ReplicaSet and the pods are derived from the deployment
+	sink.waitFor(t, gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, "shop")
+	rs := sink.waitUntil(t, func(e recordedEvent) bool { return e.eventType == gkube.GCREATE && e.resource == gkube.GREPLICASET })
+	webPods := 0
+	service := sink.waitUntil(t, func(e recordedEvent) bool {
+		status, ok := e.status.(*gkube.GServiceStatus)
+		return ok && e.name == "web" && len(status.Endpoints) == 2
+	})
+	for _, endpoint := range service.status.(*gkube.GServiceStatus).Endpoints {
+		pod := sink.waitFor(t, gkube.GCREATE, gkube.GPOD, endpoint.PodName)
+		if pod.status.(*gkube.GPodStatus).OwnerReferenceName == rs.name {
+			webPods++
+		}
+	}
+	checkTests(t, []Test{
+		{rs.status.(*gkube.GReplicaSetStatus).OwnerReferenceName, "web"},
+		{rs.status.(*gkube.GReplicaSetStatus).Revision, int64(1)},
+		{webPods, 2},
+	})
+
+	// custom resources are drawn by their CustomResourceDefinition, and own what they own as in a cluster
+	sink.waitFor(t, gkube.GCREATE, gkube.GCUSTOMRESOURCE, "kafka.kafka.strimzi.io/events")
+	issuer := sink.waitFor(t, gkube.GCREATE, gkube.GCUSTOMRESOURCE, "clusterissuer.cert-manager.io/letsencrypt")
+	config := sink.waitFor(t, gkube.GCREATE, gkube.GCONFIGMAP, "events-kafka-config")
+	checkTests(t, []Test{
+		{issuer.namespace, gkube.CLUSTER_SCOPED_NAMESPACE},
+		{config.status.(*gkube.GConfigMapStatus).CustomOwner, gkube.GCustomOwner{Name: "kafka.kafka.strimzi.io/events", Namespace: "shop"}},
+	})
+
+	// StatefulSet pods are ordinal and claim their volumes
+	db := sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "db-1")
+	sink.waitFor(t, gkube.GCREATE, gkube.GPERSISTENTVOLUMECLAIM, "data-db-1")
+	dbStatus := db.status.(*gkube.GPodStatus)
+	checkTests(t, []Test{
+		{dbStatus.Index, int32(1)},
+		{dbStatus.Phase, "Pending"},
+		{dbStatus.PersistentVolumeClaims, []string{"data-db-1"}},
+		{sink.waitFor(t, gkube.GCREATE, gkube.GSTATEFULSET, "db").status.(*gkube.GStatefulSetStatus).Claims, []string{"data-db-0", "data-db-1"}},
+	})
+}
+
+func Test_Recording(t *testing.T) {
+	ns := "test-namespace"
+	path := filepath.Join(t.TempDir(), "incident.jsonl.gz")
+	recorder, err := CreateRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-abc-1", Namespace: ns, ResourceVersion: "1"}, Status: v1.PodStatus{Phase: v1.PodPending}},
+	)
+	source.Recorder = recorder
+	sink := &recordingSink{}
+	source.Start(sink)
+	sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "web-abc-1")
+	running := &v1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web-abc-1", Namespace: ns, ResourceVersion: "2"}, Status: v1.PodStatus{Phase: v1.PodRunning}}
+	if _, err := source.Client.CoreV1().Pods(ns).UpdateStatus(t.Context(), running, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GMODIFIED, gkube.GPOD, "web-abc-1")
+	if err := source.Client.CoreV1().Pods(ns).Delete(t.Context(), "web-abc-1", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	sink.waitFor(t, gkube.GDELETE, gkube.GPOD, "web-abc-1")
+	source.Stop()
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// every event of the pod is recorded with its kind and the state the informer saw
+	events, err := LoadRecording(path)
+	pods := []RecordedEvent{}
+	for _, e := range events {
+		if e.Kind == "Pod" {
+			pods = append(pods, e)
+		}
+	}
+	if len(pods) != 3 {
+		t.Fatalf("expected 3 recorded pod events but there were %d", len(pods))
+	}
+	checkTests(t, []Test{
+		{err, nil},
+		{[]watch.EventType{pods[0].Type, pods[1].Type, pods[2].Type}, []watch.EventType{watch.Added, watch.Modified, watch.Deleted}},
+		{pods[1].APIVersion, "v1"},
+		{pods[1].GroupVersionResource(), v1.SchemeGroupVersion.WithResource("pods")},
+		{pods[1].Object["status"].(map[string]interface{})["phase"], "Running"},
+		{events[0].Time.After(pods[1].Time), false},
+	})
+}
+
+func Test_RecordingRedactsSecrets(t *testing.T) {
+	ns := "test-namespace"
+	path := filepath.Join(t.TempDir(), "incident.jsonl.gz")
+	recorder, err := CreateRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: ns, Annotations: map[string]string{v1.LastAppliedConfigAnnotation: `{"data":{"password":"aHVudGVyMg=="}}`}},
+			Data:       map[string][]byte{"password": []byte("hunter2")},
+			StringData: map[string]string{"user": "admin"},
+		},
+	)
+	source.Recorder = recorder
+	sink := &recordingSink{}
+	source.Start(sink)
+	sink.waitFor(t, gkube.GCREATE, gkube.GSECRET, "db")
+	source.Stop()
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := LoadRecording(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secrets := []RecordedEvent{}
+	for _, e := range events {
+		if e.Kind == "Secret" {
+			secrets = append(secrets, e)
+		}
+	}
+	if len(secrets) != 1 {
+		t.Fatalf("expected 1 recorded secret event but there were %d", len(secrets))
+	}
+	annotations, _, _ := unstructured.NestedStringMap(secrets[0].Object, "metadata", "annotations")
+	checkTests(t, []Test{
+		{secrets[0].Object["data"], nil},
+		{secrets[0].Object["stringData"], nil},
+		{annotations[v1.LastAppliedConfigAnnotation], ""},
+		{secrets[0].Object["metadata"].(map[string]interface{})["name"], "db"},
+	})
+}
+
+func Test_Replay(t *testing.T) {
+	ns := "test-namespace"
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	pod := func(phase string) map[string]interface{} {
+		return map[string]interface{}{
+			"apiVersion": "v1", "kind": "Pod",
+			"metadata": map[string]interface{}{"name": "web-abc-1", "namespace": ns, "resourceVersion": phase},
+			"status":   map[string]interface{}{"phase": phase},
+		}
+	}
+	source := &ReplaySource{ClusterName: "incident", speed: 1, Events: []RecordedEvent{
+		{Time: start, Type: watch.Added, APIVersion: "v1", Kind: "Namespace", Resource: "namespaces", Object: map[string]interface{}{
+			"apiVersion": "v1", "kind": "Namespace", "metadata": map[string]interface{}{"name": ns, "resourceVersion": "1"},
+		}},
+		{Time: start.Add(2 * time.Second), Type: watch.Added, APIVersion: "v1", Kind: "Pod", Resource: "pods", Object: pod("Pending")},
+		{Time: start.Add(4 * time.Second), Type: watch.Modified, APIVersion: "v1", Kind: "Pod", Resource: "pods", Object: pod("Failed")},
+		{Time: start.Add(6 * time.Second), Type: watch.Deleted, APIVersion: "v1", Kind: "Pod", Resource: "pods", Object: pod("Failed")},
+	}}
+	source.SetPaused(true)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	// the initial lists are in place when the replay starts, later events are stepped through one at a time
+	sink.waitFor(t, gkube.GCREATE, gkube.GCLUSTEROBJECTFRAME, "incident")
+	sink.waitFor(t, gkube.GCREATE, gkube.GNAMESPACEOBJECTFRAME, ns)
+	source.Step()
+	sink.waitFor(t, gkube.GCREATE, gkube.GPOD, "web-abc-1")
+	source.Step()
+	failed := sink.waitFor(t, gkube.GMODIFIED, gkube.GPOD, "web-abc-1")
+	checkTests(t, []Test{
+		{failed.status.(*gkube.GPodStatus).Phase, "Failed"},
+		{source.GetPosition(), 4 * time.Second},
+		{source.GetDuration(), 6 * time.Second},
+	})
+
+	// seeking back removes what did not exist yet, and is handed to play so that it returns while play is busy
+	source.mutex.Lock()
+	source.Seek(time.Second)
+	source.mutex.Unlock()
+	sink.waitFor(t, gkube.GDELETE, gkube.GPOD, "web-abc-1")
+	checkTests(t, []Test{
+		{source.GetPosition(), time.Duration(0)},
+	})
+
+	// and playing on, a hundred times faster, brings it back
+	source.SetSpeed(100)
+	source.SetPaused(false)
+	deadline := time.Now().Add(5 * time.Second)
+	for sink.count(gkube.GDELETE, "web-abc-1") < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	checkTests(t, []Test{
+		{sink.count(gkube.GCREATE, "web-abc-1"), 2},
+		{sink.count(gkube.GDELETE, "web-abc-1"), 2},
+		{source.GetPosition(), 6 * time.Second},
+	})
+}
+
+func Test_Metrics(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test-namespace"},
+		Spec: v1.PodSpec{Containers: []v1.Container{
+			{Name: "web", Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("100m"), v1.ResourceMemory: resource.MustParse("64Mi")},
+				Limits:   v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m"), v1.ResourceMemory: resource.MustParse("128Mi")},
+			}},
+			{Name: "proxy", Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("50m")},
+				Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("32Mi")},
+			}},
+		}},
+	}
+	node := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node-1"},
+		Status:     v1.NodeStatus{Allocatable: v1.ResourceList{v1.ResourceCPU: resource.MustParse("4"), v1.ResourceMemory: resource.MustParse("8Gi")}},
+	}
+	status := (&Watcher{}).CreatePodStatus(pod)
+	checkTests(t, []Test{
+		// the proxy is not CPU limited, so neither is the pod
+		{status.Requests, gkube.GResourceUsage{CPU: 150, Memory: 64 << 20}},
+		{status.Limits, gkube.GResourceUsage{CPU: 0, Memory: 160 << 20}},
+		// while each container carries its own limits
+		{status.Containers[0].Limits, gkube.GResourceUsage{CPU: 500, Memory: 128 << 20}},
+		{status.Containers[1].Limits, gkube.GResourceUsage{CPU: 0, Memory: 32 << 20}},
+		{CreateNodeStatus(ParseNodePoint(node)).Allocatable, gkube.GResourceUsage{CPU: 4000, Memory: 8 << 30}},
+	})
+
+	// a local metrics-server
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/apis/metrics.k8s.io/v1beta1/pods":
+			fmt.Fprint(w, `{"kind":"PodMetricsList","apiVersion":"metrics.k8s.io/v1beta1","metadata":{},"items":[{"metadata":{"name":"web","namespace":"test-namespace"},"window":"15s","containers":[{"name":"web","usage":{"cpu":"250m","memory":"120Mi"}},{"name":"proxy","usage":{"cpu":"1000000n","memory":"8Mi"}}]}]}`)
+		case "/apis/metrics.k8s.io/v1beta1/nodes":
+			fmt.Fprint(w, `{"kind":"NodeMetricsList","apiVersion":"metrics.k8s.io/v1beta1","metadata":{},"items":[{"metadata":{"name":"node-1"},"window":"15s","usage":{"cpu":"1","memory":"2Gi"}}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	watcher := CreateFakeSource(pod, node)
+	watcher.Client.(*fake.Clientset).Resources = []*metav1.APIResourceList{{
+		GroupVersion: "metrics.k8s.io/v1beta1",
+		APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "PodMetrics"}, {Name: "nodes", Kind: "NodeMetrics"}},
+	}}
+	dynamicClient, err := dynamic.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	watcher.DynamicClient = dynamicClient
+	watcher.MetricsPollPeriod = 10 * time.Millisecond
+	sink := &recordingSink{}
+	watcher.Start(sink)
+	defer watcher.Stop()
+
+	usage := sink.waitFor(t, gkube.GMODIFIED, gkube.GRESOURCEUSAGE, ResourceUsageName)
+	time.Sleep(100 * time.Millisecond)
+	checkTests(t, []Test{
+		{usage.status, &gkube.GResourceUsageStatus{
+			Pods: map[string]gkube.GResourceUsage{"test-namespace/web": {CPU: 251, Memory: 128 << 20}},
+			Containers: map[string]map[string]gkube.GResourceUsage{"test-namespace/web": {
+				"web":   {CPU: 250, Memory: 120 << 20},
+				"proxy": {CPU: 1, Memory: 8 << 20},
+			}},
+			Nodes: map[string]gkube.GResourceUsage{"node-1": {CPU: 1000, Memory: 2 << 30}},
+		}},
+		// unchanged usage is not pushed again
+		{sink.count(gkube.GMODIFIED, ResourceUsageName), 1},
+	})
+}
+
+func Test_ContainerStatuses(t *testing.T) {
+	always := v1.ContainerRestartPolicyAlways
+	started := true
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "test-namespace"},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate", Image: "flyway:10"}, {Name: "istio-proxy", Image: "proxyv2", RestartPolicy: &always}, {Name: "seed", Image: "busybox"}},
+			Containers:     []v1.Container{{Name: "web", Image: "nginx:1.25"}, {Name: "proxy", Image: "envoy:1.29"}},
+		},
+		Status: v1.PodStatus{
+			InitContainerStatuses: []v1.ContainerStatus{
+				{Name: "migrate", State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "Completed"}}},
+				{Name: "istio-proxy", Started: &started, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "proxy", RestartCount: 12,
+					State:                v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled"}}},
+				{Name: "web", Ready: true, State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	status := (&Watcher{}).CreatePodStatus(pod)
+	// containers are in spec order, those the kubelet has not reported yet have no state
+	checkTests(t, []Test{
+		{status.InitContainers, []gkube.GContainerStatus{
+			{Name: "migrate", Image: "flyway:10", State: "Terminated", Reason: "Completed"},
+			{Name: "istio-proxy", Image: "proxyv2", State: "Running", RestartPolicy: "Always", Started: true},
+			{Name: "seed", Image: "busybox"},
+		}},
+		{status.Containers, []gkube.GContainerStatus{
+			{Name: "web", Image: "nginx:1.25", State: "Running", Ready: true},
+			{Name: "proxy", Image: "envoy:1.29", State: "Waiting", Reason: "CrashLoopBackOff", RestartCount: 12, LastTerminationReason: "OOMKilled"},
+		}},
+	})
+}
+
+func Test_PodLifecycle(t *testing.T) {
+	scheduled := v1.PodSpec{NodeName: "node-1"}
+	ready := []v1.PodCondition{{Type: v1.PodReady, Status: v1.ConditionTrue}}
+	waiting := func(reason string) v1.ContainerState {
+		return v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: reason}}
+	}
+	oomKilled := v1.ContainerState{Terminated: &v1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}
+	deleted := metav1.Now()
+	checkTests(t, []Test{
+		{podLifecycle(&v1.Pod{Status: v1.PodStatus{Phase: v1.PodPending}}), gkube.PodPending},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodPending, ContainerStatuses: []v1.ContainerStatus{{State: waiting("ContainerCreating")}}}}), gkube.PodContainerCreating},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning, Conditions: ready}}), gkube.PodRunning},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning}}), gkube.PodNotReady},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{State: waiting("CrashLoopBackOff")}}}}), gkube.PodCrashLoopBackOff},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodPending, ContainerStatuses: []v1.ContainerStatus{{State: waiting("ErrImagePull")}}}}), gkube.PodImagePullBackOff},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodPending, InitContainerStatuses: []v1.ContainerStatus{{State: waiting("ImagePullBackOff")}}}}), gkube.PodImagePullBackOff},
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{{State: oomKilled}}}}), gkube.PodOOMKilled},
+		// a sidecar backing off after running out of memory outweighs another crash-looping container
+		{podLifecycle(&v1.Pod{Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning, ContainerStatuses: []v1.ContainerStatus{
+			{State: waiting("CrashLoopBackOff")},
+			{State: waiting("CrashLoopBackOff"), LastTerminationState: oomKilled},
+		}}}), gkube.PodOOMKilled},
+		{podLifecycle(&v1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}, Spec: scheduled, Status: v1.PodStatus{Phase: v1.PodRunning, Conditions: ready}}), gkube.PodTerminating},
+		{podLifecycle(&v1.Pod{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &deleted}, Status: v1.PodStatus{Phase: v1.PodRunning, Reason: "NodeLost"}}), gkube.PodUnknown},
+		{podLifecycle(&v1.Pod{Status: v1.PodStatus{Phase: v1.PodSucceeded}}), gkube.PodSucceeded},
+		{podLifecycle(&v1.Pod{Status: v1.PodStatus{Phase: v1.PodFailed, Reason: "Evicted"}}), gkube.PodFailed},
+		{podLifecycle(&v1.Pod{Status: v1.PodStatus{Phase: v1.PodUnknown}}), gkube.PodUnknown},
+	})
+}
+
+func Test_DeploymentRollout(t *testing.T) {
+	ns := "test-namespace"
+	replicas := int32(3)
+	revision := func(revision string) map[string]string {
+		return map[string]string{RevisionAnnotation: revision}
+	}
+	source := CreateFakeSource(
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+		&appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns, Annotations: revision("3")},
+			Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			Status:     appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1, ReadyReplicas: 3, AvailableReplicas: 3},
+		},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-new", Namespace: ns, Annotations: revision("3"), OwnerReferences: ownedBy("Deployment", "web")}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-old", Namespace: ns, Annotations: revision("two"), OwnerReferences: ownedBy("Deployment", "web")}},
+	)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	deploy := sink.waitFor(t, gkube.GCREATE, gkube.GDEPLOYMENT, "web")
+	status := deploy.status.(*gkube.GDeploymentStatus)
+	checkTests(t, []Test{
+		{*status, gkube.GDeploymentStatus{ReadyReplicas: 3, Replicas: 4, DesiredReplicas: 3, UpdatedReplicas: 1, AvailableReplicas: 3, Revision: 3}},
+		{sink.waitFor(t, gkube.GCREATE, gkube.GREPLICASET, "web-new").status.(*gkube.GReplicaSetStatus).Revision, int64(3)},
+		{sink.waitFor(t, gkube.GCREATE, gkube.GREPLICASET, "web-old").status.(*gkube.GReplicaSetStatus).Revision, int64(0)}, // malformed
+	})
+
+	// the rollout makes no progress within its deadline
+	stalled := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns, ResourceVersion: "2", Annotations: revision("3")},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+		Status: appsv1.DeploymentStatus{Replicas: 4, UpdatedReplicas: 1, ReadyReplicas: 3, AvailableReplicas: 3, Conditions: []appsv1.DeploymentCondition{
+			{Type: appsv1.DeploymentAvailable, Status: v1.ConditionTrue, Reason: "MinimumReplicasAvailable"},
+			{Type: appsv1.DeploymentProgressing, Status: v1.ConditionFalse, Reason: progressDeadlineExceededReason},
+		}},
+	}
+	if _, err := source.Client.AppsV1().Deployments(ns).UpdateStatus(t.Context(), stalled, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := sink.waitFor(t, gkube.GMODIFIED, gkube.GDEPLOYMENT, "web")
+	checkTests(t, []Test{
+		{modified.status.(*gkube.GDeploymentStatus).Stalled, true},
+	})
+}
+
+func Test_HorizontalPodAutoscaler(t *testing.T) {
+	ns := "test-namespace"
+	utilization := func(percent int32) *int32 { return &percent }
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+	minReplicas := int32(2)
+	scaled := metav1.NewTime(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC))
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: ns},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "web"},
+			MinReplicas:    &minReplicas,
+			MaxReplicas:    5,
+			Metrics: []autoscalingv2.MetricSpec{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{Name: v1.ResourceCPU, Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: utilization(70)}}},
+				{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricSource{Metric: autoscalingv2.MetricIdentifier{Name: "http_requests"}, Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: quantity("100")}}},
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricSource{Name: v1.ResourceMemory, Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: utilization(80)}}},
+			},
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 3,
+			DesiredReplicas: 5,
+			LastScaleTime:   &scaled,
+			CurrentMetrics: []autoscalingv2.MetricStatus{
+				{Type: autoscalingv2.ResourceMetricSourceType, Resource: &autoscalingv2.ResourceMetricStatus{Name: v1.ResourceCPU, Current: autoscalingv2.MetricValueStatus{AverageUtilization: utilization(140)}}},
+				{Type: autoscalingv2.PodsMetricSourceType, Pods: &autoscalingv2.PodsMetricStatus{Metric: autoscalingv2.MetricIdentifier{Name: "http_requests"}, Current: autoscalingv2.MetricValueStatus{AverageValue: quantity("150")}}},
+			},
+			Conditions: []autoscalingv2.HorizontalPodAutoscalerCondition{
+				{Type: autoscalingv2.AbleToScale, Status: v1.ConditionTrue, Reason: "SucceededRescale"},
+				{Type: autoscalingv2.ScalingActive, Status: v1.ConditionTrue, Reason: "ValidMetricFound"},
+				{Type: autoscalingv2.ScalingLimited, Status: v1.ConditionTrue, Reason: "TooManyReplicas"},
+			},
+		},
+	}
+	source := CreateFakeSource(&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}}, hpa)
+	sink := &recordingSink{}
+	source.Start(sink)
+	defer source.Stop()
+
+	status := sink.waitFor(t, gkube.GCREATE, gkube.GHORIZONTALPODAUTOSCALER, "web").status.(*gkube.GHorizontalPodAutoscalerStatus)
+	checkTests(t, []Test{
+		{status.ScaleTargetType, "Deployment"},
+		{status.ScaleTargetName, "web"},
+		{status.CustomScaleTarget, gkube.GCustomOwner{}},
+		{[]int32{status.CurrentReplicas, status.DesiredReplicas, status.MinReplicas, status.MaxReplicas}, []int32{3, 5, 2, 5}},
+		{status.Metrics, []gkube.GAutoscalerMetric{
+			{Name: "cpu", Current: "140%", Target: "70%", Ratio: 2},
+			{Name: "http_requests", Current: "150", Target: "100", Ratio: 1.5},
+			{Name: "memory", Target: "80%"}, // not read yet
+		}},
+		{status.LastScaleTime.Equal(scaled.Time), true},
+		{status.Limited, "TooManyReplicas"},
+		{status.Inactive, ""},
+	})
+
+	// the metrics server goes away
+	broken := hpa.DeepCopy()
+	broken.ResourceVersion = "2"
+	broken.Status.CurrentMetrics = nil
+	broken.Status.Conditions = []autoscalingv2.HorizontalPodAutoscalerCondition{
+		{Type: autoscalingv2.AbleToScale, Status: v1.ConditionTrue, Reason: "SucceededGetScale"},
+		{Type: autoscalingv2.ScalingActive, Status: v1.ConditionFalse, Reason: "FailedGetResourceMetric"},
+	}
+	if _, err := source.Client.AutoscalingV2().HorizontalPodAutoscalers(ns).UpdateStatus(t.Context(), broken, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	modified := sink.waitFor(t, gkube.GMODIFIED, gkube.GHORIZONTALPODAUTOSCALER, "web").status.(*gkube.GHorizontalPodAutoscalerStatus)
+	checkTests(t, []Test{
+		{modified.Inactive, "FailedGetResourceMetric"},
+		{modified.Limited, ""},
+		{modified.Metrics[0].Current, ""},
+	})
+}
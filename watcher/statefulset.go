@@ -0,0 +1,154 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	corev1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	appsinformers "k8s.io/client-go/informers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type StatefulSetPoint struct {
+	WatchPoint
+	Replicas int32
+}
+
+func (p *StatefulSetPoint) String() string {
+	return fmt.Sprintf("StatefulSet %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *StatefulSetPoint) Init(obj *corev1.StatefulSet) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+	if obj.Spec.Replicas != nil {
+		p.Replicas = *obj.Spec.Replicas
+	}
+}
+
+func ParseStatefulSetPoint(d *corev1.StatefulSet) *StatefulSetPoint {
+	p := &StatefulSetPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseStatefulSet(rawStatefulSet map[string]interface{}) (*corev1.StatefulSet, error) {
+	ns := &corev1.StatefulSet{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawStatefulSet, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// CreateStatefulSetStatus derives the GStatefulSetStatus of a StatefulSet, with the names of the claims its
+// volumeClaimTemplates generate for each of its ordinals
+func (watcher *Watcher) CreateStatefulSetStatus(statefulset *corev1.StatefulSet) *gkube.GStatefulSetStatus {
+	claims := []string{}
+	for _, template := range statefulset.Spec.VolumeClaimTemplates {
+		for ordinal := range replicasOrDefault(statefulset.Spec.Replicas) {
+			claims = append(claims, fmt.Sprintf("%s-%s-%d", template.Name, statefulset.Name, ordinal))
+		}
+	}
+	return &gkube.GStatefulSetStatus{
+		ReadyReplicas: statefulset.Status.ReadyReplicas,
+		Replicas:      statefulset.Status.Replicas,
+		CustomOwner:   watcher.customOwner(statefulset.Namespace, statefulset.OwnerReferences),
+		Claims:        claims,
+	}
+}
+
+func (watcher *Watcher) WatchStatefulSets(ctx context.Context, nsName string) {
+	informer := appsinformers.NewFilteredStatefulSetInformer(watcher.Client, nsName, ResyncPeriod, cache.Indexers{}, watcher.workloadListOptions)
+	watcher.runInformer(ctx, "statefulsets", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onStatefulSetAdded,
+		UpdateFunc: watcher.onStatefulSetModified,
+		DeleteFunc: watcher.onStatefulSetDeleted,
+	})
+}
+
+func (watcher *Watcher) onStatefulSetAdded(obj interface{}) {
+	statefulset, ok := obj.(*corev1.StatefulSet)
+	if !ok {
+		return
+	}
+	rawStatefulSet, err := watcher.ToUnstructuredSync(statefulset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	statefulsetName := statefulset.GetName()
+	key := pointKey(statefulset.Namespace, statefulsetName)
+	_, found := watcher.StatefulSetPoints.Load(key)
+	if !found {
+		// add statefulset point
+		watcher.StatefulSetPoints.Store(key, ParseStatefulSetPoint(statefulset))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GSTATEFULSET, statefulsetName, statefulset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateStatefulSetStatus(statefulset), -1, rawStatefulSet)
+		log.Println("ADDED statefulset " + statefulsetName)
+	}
+}
+
+func (watcher *Watcher) onStatefulSetModified(oldObj, newObj interface{}) {
+	statefulset, ok := newObj.(*corev1.StatefulSet)
+	if !ok {
+		return
+	}
+
+	statefulsetName := statefulset.GetName()
+	key := pointKey(statefulset.Namespace, statefulsetName)
+	point, found := watcher.StatefulSetPoints.Load(key)
+	if !found {
+		watcher.onStatefulSetAdded(statefulset)
+		return
+	}
+	if point.(*StatefulSetPoint).ResourceVersion == statefulset.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawStatefulSet, err := watcher.ToUnstructuredSync(statefulset)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify statefulset point
+	watcher.StatefulSetPoints.Store(key, ParseStatefulSetPoint(statefulset))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSTATEFULSET, statefulsetName, statefulset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateStatefulSetStatus(statefulset), -1, rawStatefulSet)
+	log.Println("MODIFIED statefulset " + statefulsetName)
+}
+
+func (watcher *Watcher) onStatefulSetDeleted(obj interface{}) {
+	statefulset, ok := unwrapTombstone(obj).(*corev1.StatefulSet)
+	if !ok {
+		return
+	}
+	statefulsetName := statefulset.GetName()
+	key := pointKey(statefulset.Namespace, statefulsetName)
+	_, found := watcher.StatefulSetPoints.Load(key)
+	if found {
+		// delete statefulset point
+		watcher.StatefulSetPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GSTATEFULSET, statefulsetName, statefulset.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, watcher.CreateStatefulSetStatus(statefulset), -1, nil)
+		log.Println("DELETED statefulset " + statefulsetName)
+	}
+}
+
+// parseOrdinal returns the trailing ordinal of names like web-0 or data-web-2, or 0 if the name has none
+func parseOrdinal(name string) int32 {
+	i := strings.LastIndex(name, "-")
+	if i == -1 {
+		return 0
+	}
+	ordinal, err := strconv.ParseInt(name[i+1:], 10, 32)
+	if err != nil || ordinal < 0 {
+		return 0
+	}
+	return int32(ordinal)
+}
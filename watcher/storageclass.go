@@ -0,0 +1,136 @@
+package watcher
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/kabicin/kubechaser/renderer/gkube"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type StorageClassPoint struct {
+	WatchPoint
+}
+
+func (p *StorageClassPoint) String() string {
+	return fmt.Sprintf("StorageClass %s (%s) - %s", p.Name, p.CreationTimestamp, p.ResourceVersion)
+}
+
+func (p *StorageClassPoint) Init(obj *storagev1.StorageClass) {
+	p.Name = obj.GetObjectMeta().GetName()
+	p.Namespace = obj.GetNamespace()
+	p.CreationTimestamp = obj.GetObjectMeta().GetCreationTimestamp().GoString()
+	p.ResourceVersion = obj.GetResourceVersion()
+}
+
+func ParseStorageClassPoint(d *storagev1.StorageClass) *StorageClassPoint {
+	p := &StorageClassPoint{}
+	p.Init(d)
+	return p
+}
+
+func (watcher *Watcher) ParseStorageClass(rawStorageClass map[string]interface{}) (*storagev1.StorageClass, error) {
+	ns := &storagev1.StorageClass{}
+	watcher.UnstructuredConverterMutex.Lock()
+	defer watcher.UnstructuredConverterMutex.Unlock()
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(rawStorageClass, ns); err != nil {
+		return nil, err
+	}
+	return ns, nil
+}
+
+// the annotation marking the storage class used by claims that do not name one
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+func CreateStorageClassStatus(storageClass *storagev1.StorageClass) *gkube.GStorageClassStatus {
+	status := &gkube.GStorageClassStatus{
+		Provisioner: storageClass.Provisioner,
+		IsDefault:   storageClass.Annotations[defaultStorageClassAnnotation] == "true",
+	}
+	if storageClass.ReclaimPolicy != nil {
+		status.ReclaimPolicy = string(*storageClass.ReclaimPolicy)
+	}
+	if storageClass.VolumeBindingMode != nil {
+		status.VolumeBindingMode = string(*storageClass.VolumeBindingMode)
+	}
+	return status
+}
+
+// StorageClasses are cluster-scoped and watched once for the whole cluster
+func (watcher *Watcher) WatchStorageClasses(ctx context.Context) {
+	informer := storageinformers.NewStorageClassInformer(watcher.Client, ResyncPeriod, cache.Indexers{})
+	watcher.runInformer(ctx, "storageclasses", informer, cache.ResourceEventHandlerFuncs{
+		AddFunc:    watcher.onStorageClassAdded,
+		UpdateFunc: watcher.onStorageClassModified,
+		DeleteFunc: watcher.onStorageClassDeleted,
+	})
+}
+
+func (watcher *Watcher) onStorageClassAdded(obj interface{}) {
+	storageClass, ok := obj.(*storagev1.StorageClass)
+	if !ok {
+		return
+	}
+	rawStorageClass, err := watcher.ToUnstructuredSync(storageClass)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	storageClassName := storageClass.GetName()
+	key := pointKey(storageClass.Namespace, storageClassName)
+	_, found := watcher.StorageClassPoints.Load(key)
+	if !found {
+		// add storage class point
+		watcher.StorageClassPoints.Store(key, ParseStorageClassPoint(storageClass))
+		watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GSTORAGECLASS, storageClassName, storageClass.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateStorageClassStatus(storageClass), -1, rawStorageClass)
+		log.Println("ADDED storage class " + storageClassName)
+	}
+}
+
+func (watcher *Watcher) onStorageClassModified(oldObj, newObj interface{}) {
+	storageClass, ok := newObj.(*storagev1.StorageClass)
+	if !ok {
+		return
+	}
+
+	storageClassName := storageClass.GetName()
+	key := pointKey(storageClass.Namespace, storageClassName)
+	point, found := watcher.StorageClassPoints.Load(key)
+	if !found {
+		watcher.onStorageClassAdded(storageClass)
+		return
+	}
+	if point.(*StorageClassPoint).ResourceVersion == storageClass.GetResourceVersion() {
+		return // periodic resync, nothing changed
+	}
+	rawStorageClass, err := watcher.ToUnstructuredSync(storageClass)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	// modify storage class point
+	watcher.StorageClassPoints.Store(key, ParseStorageClassPoint(storageClass))
+	watcher.MainCluster.PushGObjectEvent(gkube.GMODIFIED, gkube.GSTORAGECLASS, storageClassName, storageClass.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateStorageClassStatus(storageClass), -1, rawStorageClass)
+	log.Println("MODIFIED storage class " + storageClassName)
+}
+
+func (watcher *Watcher) onStorageClassDeleted(obj interface{}) {
+	storageClass, ok := unwrapTombstone(obj).(*storagev1.StorageClass)
+	if !ok {
+		return
+	}
+	storageClassName := storageClass.GetName()
+	key := pointKey(storageClass.Namespace, storageClassName)
+	_, found := watcher.StorageClassPoints.Load(key)
+	if found {
+		// delete storage class point
+		watcher.StorageClassPoints.Delete(key)
+		watcher.MainCluster.PushGObjectEvent(gkube.GDELETE, gkube.GSTORAGECLASS, storageClassName, storageClass.Namespace, gkube.GNONE, gkube.GSETTING_NONE, nil, CreateStorageClassStatus(storageClass), -1, nil)
+		log.Println("DELETED storage class " + storageClassName)
+	}
+}
@@ -1,14 +1,26 @@
 package watcher
 
 import (
+	"context"
+	"fmt"
 	"sync"
+	"time"
 
 	"github.com/kabicin/kubechaser/renderer/gkube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"k8s.io/client-go/tools/cache"
 )
 
+// ClusterObjectFrameName names the frame drawn around the whole cluster, unless the watcher is given a ClusterName
+const ClusterObjectFrameName = "cluster"
+
+// ResyncPeriod is how often every informer replays its cache through the update handlers
+const ResyncPeriod = 10 * time.Minute
+
 type WatchPoint struct {
 	Name              string
 	Namespace         string
@@ -17,17 +29,66 @@ type WatchPoint struct {
 }
 
 type Watcher struct {
-	Client                     *kubernetes.Clientset
+	Client                     kubernetes.Interface
 	ClientMutex                *sync.Mutex
 	UnstructuredConverterMutex *sync.Mutex
 
-	MainCluster      *gkube.GCluster
+	// custom resources are watched through the dynamic client, which is optional
+	DynamicClient       dynamic.Interface
+	CustomResources     []schema.GroupVersionResource
+	customResourceKinds []CustomResourceKind
+
+	MainCluster      EventSink
 	MainClusterMutex *sync.Mutex
+	ClusterName      string // names the cluster object frame, i.e. the kubeconfig context
+
+	// records every event received by the informers when set
+	Recorder *Recorder
+
+	// how often usage is polled from metrics.k8s.io
+	MetricsPollPeriod time.Duration
 
 	NamespacePoints  *sync.Map
 	ReplicaSetPoints *sync.Map
 	DeploymentPoints *sync.Map
 	PodPoints        *sync.Map
+	ServicePoints    *sync.Map
+	EndpointSlices   *sync.Map
+
+	StatefulSetPoints             *sync.Map
+	PersistentVolumeClaimPoints   *sync.Map
+	DaemonSetPoints               *sync.Map
+	JobPoints                     *sync.Map
+	CronJobPoints                 *sync.Map
+	HorizontalPodAutoscalerPoints *sync.Map
+	NodePoints                    *sync.Map
+	IngressPoints                 *sync.Map
+	ConfigMapPoints               *sync.Map
+	SecretPoints                  *sync.Map
+	ServiceAccountPoints          *sync.Map
+	PersistentVolumePoints        *sync.Map
+	StorageClassPoints            *sync.Map
+	RolePoints                    *sync.Map
+	RoleBindingPoints             *sync.Map
+	ClusterRolePoints             *sync.Map
+	ClusterRoleBindingPoints      *sync.Map
+	CustomResourcePoints          *sync.Map
+	EventPoints                   *sync.Map
+
+	// what is watched, changeable while running through SetScope
+	scope      WatchScope
+	scopeMutex *sync.Mutex
+
+	// every informer stops with ctx. The namespace informer and the informers of each namespace are grouped so that they
+	// can be stopped, or restarted under a new scope, on their own.
+	ctx              context.Context
+	cancel           context.CancelFunc
+	namespaces       *informerGroup
+	namespaceWatches *sync.Map // namespace name -> *informerGroup
+
+	// informers that have not returned yet, which Stop waits for
+	running      sync.WaitGroup
+	runningMutex *sync.Mutex
 }
 
 func (watcher *Watcher) ToUnstructuredSync(obj interface{}) (map[string]interface{}, error) {
@@ -37,22 +98,133 @@ func (watcher *Watcher) ToUnstructuredSync(obj interface{}) (map[string]interfac
 	return u, err
 }
 
-func (watcher *Watcher) Init(cluster *gkube.GCluster) {
-	clientset, err := kubernetes.NewForConfig(config.GetConfigOrDie())
-	if err != nil {
-		panic(err.Error())
+// points are keyed by namespace/name so that objects sharing a name across namespaces do not collide
+func pointKey(namespace, name string) string {
+	if len(namespace) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s/%s", namespace, name)
+}
+
+// informers hand deleted objects over as a tombstone when the final state was missed between relists
+func unwrapTombstone(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
+
+// beginRunning counts a goroutine in, which Stop waits for until it calls running.Done. Goroutines starting while the
+// watcher stops are not run, so that Stop does not wait on them.
+func (watcher *Watcher) beginRunning(ctx context.Context) bool {
+	watcher.runningMutex.Lock()
+	defer watcher.runningMutex.Unlock()
+	if ctx.Err() != nil {
+		return false
 	}
-	watcher.Client = clientset
+	watcher.running.Add(1)
+	return true
+}
+
+// runInformer binds the handler to the informer and blocks until ctx is done. Informers are named by the resource they
+// watch, which is qualified by its group for custom resources; those started within an informerGroup are tracked by name.
+// The informer lists before it watches, resumes from the last seen resourceVersion, relists when the
+// watch expires ("too old resource version") and replays its cache every ResyncPeriod.
+func (watcher *Watcher) runInformer(ctx context.Context, name string, informer cache.SharedIndexInformer, handler cache.ResourceEventHandler) {
+	if !watcher.beginRunning(ctx) {
+		return
+	}
+	defer watcher.running.Done()
+
+	if watcher.Recorder != nil {
+		handler = watcher.recordingHandler(name, handler)
+	}
+	informer.AddEventHandler(handler)
+	if group, found := ctx.Value(informerGroupKey{}).(*informerGroup); found {
+		if !group.beginRunning() {
+			return
+		}
+		defer group.running.Done()
+		watcher.running.Add(1) // counted while this informer is, so that Stop also waits for the deletes of the group
+		group.running.Add(1)
+		go func() {
+			defer watcher.running.Done()
+			defer group.running.Done()
+			group.track(name, informer, handler)
+		}()
+	}
+	informer.Run(ctx.Done())
+}
+
+func (watcher *Watcher) Init(client kubernetes.Interface) {
+	watcher.Client = client
+	watcher.ClusterName = ClusterObjectFrameName
+	watcher.MetricsPollPeriod = DefaultMetricsPollPeriod
 
 	watcher.NamespacePoints = &sync.Map{}
 	watcher.DeploymentPoints = &sync.Map{}
 	watcher.ReplicaSetPoints = &sync.Map{}
 	watcher.PodPoints = &sync.Map{}
+	watcher.ServicePoints = &sync.Map{}
+	watcher.EndpointSlices = &sync.Map{}
+	watcher.StatefulSetPoints = &sync.Map{}
+	watcher.PersistentVolumeClaimPoints = &sync.Map{}
+	watcher.DaemonSetPoints = &sync.Map{}
+	watcher.JobPoints = &sync.Map{}
+	watcher.CronJobPoints = &sync.Map{}
+	watcher.HorizontalPodAutoscalerPoints = &sync.Map{}
+	watcher.NodePoints = &sync.Map{}
+	watcher.IngressPoints = &sync.Map{}
+	watcher.ConfigMapPoints = &sync.Map{}
+	watcher.SecretPoints = &sync.Map{}
+	watcher.ServiceAccountPoints = &sync.Map{}
+	watcher.PersistentVolumePoints = &sync.Map{}
+	watcher.StorageClassPoints = &sync.Map{}
+	watcher.RolePoints = &sync.Map{}
+	watcher.RoleBindingPoints = &sync.Map{}
+	watcher.ClusterRolePoints = &sync.Map{}
+	watcher.ClusterRoleBindingPoints = &sync.Map{}
+	watcher.CustomResourcePoints = &sync.Map{}
+	watcher.EventPoints = &sync.Map{}
+	watcher.namespaceWatches = &sync.Map{}
 
-	watcher.MainCluster = cluster
 	watcher.MainClusterMutex = &sync.Mutex{}
 	watcher.ClientMutex = &sync.Mutex{}
 	watcher.UnstructuredConverterMutex = &sync.Mutex{}
+	watcher.scopeMutex = &sync.Mutex{}
+	watcher.runningMutex = &sync.Mutex{}
+}
+
+// Start begins watching the cluster and pushes every observed change into sink
+func (watcher *Watcher) Start(sink EventSink) {
+	watcher.MainCluster = sink
+	watcher.ctx, watcher.cancel = context.WithCancel(context.Background())
+
+	// cluster-scoped objects, i.e. storage, are framed by the cluster object frame
+	watcher.MainCluster.PushGObjectEvent(gkube.GCREATE, gkube.GCLUSTEROBJECTFRAME, watcher.ClusterName, "", gkube.GNONE, gkube.GSETTING_NONE, nil, &gkube.GClusterObjectFrameStatus{}, -1, nil)
+
+	// custom resources must be resolved before anything they may own is pushed
+	watcher.resolveCustomResources()
 
-	go watcher.WatchNamespaces()
+	watcher.namespaces = watcher.newInformerGroup(nil)
+	go watcher.WatchNamespaces(watcher.namespaces.ctx)
+	go watcher.WatchNodes(watcher.ctx)
+	go watcher.WatchPersistentVolumes(watcher.ctx)
+	go watcher.WatchStorageClasses(watcher.ctx)
+	go watcher.WatchClusterRoles(watcher.ctx)
+	go watcher.WatchClusterRoleBindings(watcher.ctx)
+	go watcher.WatchCustomResources(watcher.ctx, metav1.NamespaceAll, false)
+	go watcher.WatchMetrics(watcher.ctx)
+}
+
+// Stop terminates every informer started by this watcher and returns once they have, after which nothing more is pushed
+// into the sink
+func (watcher *Watcher) Stop() {
+	if watcher.cancel == nil {
+		return // not started
+	}
+	watcher.runningMutex.Lock()
+	watcher.cancel()
+	watcher.runningMutex.Unlock()
+	watcher.running.Wait()
 }